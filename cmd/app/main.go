@@ -2,13 +2,20 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/ViktorOHJ/expense-tracker/pkg/api"
 	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
 	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mail"
+	"github.com/ViktorOHJ/expense-tracker/pkg/rates"
+	"github.com/ViktorOHJ/expense-tracker/pkg/scheduler"
+	"github.com/ViktorOHJ/expense-tracker/pkg/storage"
 	"github.com/joho/godotenv"
 )
 
@@ -20,18 +27,57 @@ func main() {
 
 	ctx := context.Background()
 
-	pool, err := db.InitDB(ctx, os.Getenv("DB_URL"))
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(ctx, os.Args[2:]))
+	}
+
+	// AUTO_MIGRATE=false — миграции применяются отдельно командой "migrate up";
+	// DB_URL вида sqlite://путь выбирает SQLite вместо PostgreSQL
+	database, closeDB, err := db.Open(ctx, os.Getenv("DB_URL"), os.Getenv("AUTO_MIGRATE") != "false")
 	if err != nil {
 		log.Fatalf("Error initializing database: %v", err)
 	}
-	defer pool.Close()
+	defer closeDB()
+
+	// Курсы валют: разовая загрузка из файла и/или периодическая синхронизация с HTTP API
+	if ratesFile := os.Getenv("RATES_FILE"); ratesFile != "" {
+		if err := rates.Sync(ctx, rates.FileProvider{Path: ratesFile}, database, time.Now()); err != nil {
+			log.Printf("Error loading exchange rates from %s: %v", ratesFile, err)
+		}
+	}
+	if ratesURL := os.Getenv("RATES_URL"); ratesURL != "" {
+		base := os.Getenv("RATES_BASE")
+		if base == "" {
+			base = "EUR"
+		}
+		go rates.Run(ctx, rates.NewHTTPProvider(ratesURL, base), database, 24*time.Hour)
+	}
 
-	database := db.NewPostgresDB(pool)
+	// Регулярные транзакции: планировщик проводит наступившие повторения
+	go scheduler.Run(ctx, database, envDuration("SCHEDULER_INTERVAL", time.Hour))
 
-	jwtService := auth.NewJWTService(os.Getenv("JWT_SECRET"))
+	accessTTL := envDuration("ACCESS_TOKEN_TTL", auth.DefaultAccessTTL)
+	refreshTTL := envDuration("REFRESH_TOKEN_TTL", auth.DefaultRefreshTTL)
+	jwtOptions, err := jwtKeys()
+	if err != nil {
+		log.Fatalf("Error loading JWT signing keys: %v", err)
+	}
+	jwtService := auth.NewJWTServiceWithTTL(os.Getenv("JWT_SECRET"), accessTTL, refreshTTL, jwtOptions...)
 	passwordService := auth.NewPasswordService()
 
-	server := api.NewServer(database, jwtService, passwordService)
+	blobs, err := blobStore()
+	if err != nil {
+		log.Fatalf("Error initializing attachment storage: %v", err)
+	}
+
+	sender, err := mailer()
+	if err != nil {
+		log.Fatalf("Error initializing mailer: %v", err)
+	}
+
+	server := api.NewServer(database, jwtService, passwordService, api.WithBlobStore(blobs),
+		api.WithMailer(sender), api.WithAppURL(os.Getenv("APP_URL")),
+		api.WithTrustProxy(os.Getenv("TRUST_PROXY") == "true"))
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -44,3 +90,81 @@ func main() {
 		log.Fatalf("Error starting server: %v", err)
 	}
 }
+
+// envDuration читает длительность вида "15m" или "720h" из переменной окружения
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Fatalf("Invalid %s %q", name, v)
+	}
+	return d
+}
+
+// jwtKeys загружает асимметричные ключи JWT из JWT_KEYS — списка PEM-файлов через
+// запятую; у файла может быть срок начала подписи: keys/next.pem@2027-01-01T00:00:00Z.
+// JWT_HS256_UNTIL ограничивает приём старых HS256 токенов, подписанных JWT_SECRET.
+func jwtKeys() ([]auth.JWTOption, error) {
+	list := os.Getenv("JWT_KEYS")
+	if list == "" {
+		return nil, nil
+	}
+
+	var keys []*auth.SigningKey
+	for _, entry := range strings.Split(list, ",") {
+		path, activeFrom, scheduled := strings.Cut(strings.TrimSpace(entry), "@")
+		key, err := auth.LoadSigningKey(path)
+		if err != nil {
+			return nil, err
+		}
+		if scheduled {
+			key.ActiveFrom, err = time.Parse(time.RFC3339, activeFrom)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid activation time %q", path, activeFrom)
+			}
+		}
+		log.Printf("Loaded JWT key %s (%s) from %s", key.ID, key.Method.Alg(), path)
+		keys = append(keys, key)
+	}
+	opts := []auth.JWTOption{auth.WithSigningKeys(keys...)}
+
+	if until := os.Getenv("JWT_HS256_UNTIL"); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_HS256_UNTIL %q", until)
+		}
+		opts = append(opts, auth.WithLegacyUntil(t))
+	}
+	return opts, nil
+}
+
+// blobStore выбирает хранилище вложений: S3-совместимое, если задан S3_ENDPOINT,
+// иначе папка ATTACHMENTS_DIR
+func blobStore() (storage.BlobStore, error) {
+	if endpoint := os.Getenv("S3_ENDPOINT"); endpoint != "" {
+		return storage.NewS3Store(endpoint, os.Getenv("S3_BUCKET"), os.Getenv("S3_REGION"),
+			os.Getenv("S3_ACCESS_KEY"), os.Getenv("S3_SECRET_KEY")), nil
+	}
+	dir := os.Getenv("ATTACHMENTS_DIR")
+	if dir == "" {
+		dir = "data/attachments"
+	}
+	return storage.NewLocalStore(dir)
+}
+
+// mailer выбирает отправку писем: через SMTP_ADDR, а без него — только в лог
+func mailer() (mail.Mailer, error) {
+	addr := os.Getenv("SMTP_ADDR")
+	if addr == "" {
+		log.Println("SMTP_ADDR is not set, emails will be written to the log")
+		return mail.LogMailer{}, nil
+	}
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "no-reply@expense-tracker.local"
+	}
+	return mail.NewSMTPMailer(addr, from, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"))
+}
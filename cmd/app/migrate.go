@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+)
+
+const migrateUsage = `usage: app migrate <command>
+
+commands:
+  up           apply all pending migrations
+  down [N]     roll back the last N migrations (default 1)
+  status       list migrations and whether they are applied
+  force V      mark migrations up to V as applied without running them`
+
+// runMigrate выполняет подкоманду migrate и возвращает код завершения
+func runMigrate(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	migrator, closeDB, err := db.OpenMigrator(ctx, os.Getenv("DB_URL"))
+	if err != nil {
+		log.Printf("Error connecting to database: %v", err)
+		return 1
+	}
+	defer closeDB()
+
+	switch args[0] {
+	case "up":
+		n, err := migrator.Up(ctx)
+		if err != nil {
+			log.Printf("Error applying migrations: %v", err)
+			return 1
+		}
+		fmt.Printf("applied %d migration(s)\n", n)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				fmt.Fprintln(os.Stderr, "down: N must be a positive number")
+				return 2
+			}
+		}
+		n, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Printf("Error rolling back migrations: %v", err)
+			return 1
+		}
+		fmt.Printf("rolled back %d migration(s)\n", n)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Printf("Error reading migration status: %v", err)
+			return 1
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-24s %s\n", s.Version, s.Name, applied)
+		}
+	case "force":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, "force: version is required")
+			return 2
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil || version < 0 {
+			fmt.Fprintln(os.Stderr, "force: version must be a non-negative number")
+			return 2
+		}
+		if err := migrator.Force(ctx, version); err != nil {
+			log.Printf("Error forcing migration version: %v", err)
+			return 1
+		}
+		fmt.Printf("schema version set to %d\n", version)
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+)
+
+const maxAPIKeyNameLength = 100
+
+// validScope проверяет область доступа вида "<ресурс>:read|write"
+func validScope(scope string) bool {
+	resource, access, ok := strings.Cut(scope, ":")
+	if !ok || !slices.Contains(models.APIKeyResources, resource) {
+		return false
+	}
+	switch access {
+	case models.ScopeRead:
+		return true
+	case models.ScopeWrite:
+		return resource != "reports"
+	}
+	return false
+}
+
+// hasScope сообщает, разрешает ли набор областей доступ к ресурсу: на чтение
+// достаточно read или write, на изменение нужен write
+func hasScope(scopes []string, resource, access string) bool {
+	for _, scope := range scopes {
+		switch scope {
+		case resource + ":" + models.ScopeWrite:
+			return true
+		case resource + ":" + access:
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) GetAPIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	keys, err := s.db.GetAPIKeys(r.Context(), user.UserID)
+	if err != nil {
+		log.Printf("error retrieving api keys: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error retrieving api keys")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "api keys listed successfully",
+		Data:    keys,
+	})
+}
+
+// CreateAPIKeyHandler выпускает ключ. Сам ключ есть только в этом ответе.
+func (s *Server) CreateAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req models.APIKeyRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		JsonError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if len(req.Name) > maxAPIKeyNameLength {
+		JsonError(w, http.StatusBadRequest, fmt.Sprintf("name must be at most %d characters", maxAPIKeyNameLength))
+		return
+	}
+	if len(req.Scopes) == 0 {
+		JsonError(w, http.StatusBadRequest, "at least one scope is required")
+		return
+	}
+	scopes := []string{}
+	for _, scope := range req.Scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !validScope(scope) {
+			JsonError(w, http.StatusBadRequest, fmt.Sprintf("invalid scope %q", scope))
+			return
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		JsonError(w, http.StatusBadRequest, "expires_at must be in the future")
+		return
+	}
+
+	key, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		log.Printf("error creating api key: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error creating api key")
+		return
+	}
+	created, err := s.db.AddAPIKey(r.Context(), user.UserID, &models.APIKey{
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		log.Printf("error creating api key: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error creating api key")
+		return
+	}
+
+	JsonResponse(w, http.StatusCreated, models.SuccessResponse{
+		Message: "api key created, store it now: it will not be shown again",
+		Data:    models.CreatedAPIKey{Key: key, APIKey: created},
+	})
+}
+
+func (s *Server) DeleteAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = s.db.DeleteAPIKey(r.Context(), user.UserID, id)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("api key with id %d not found or access denied", id))
+		return
+	}
+	if err != nil {
+		log.Printf("failed to delete api key: %v", err)
+		JsonError(w, http.StatusInternalServerError, "failed to delete api key")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("api key with id: %d successfully revoked", id),
+	})
+}
@@ -0,0 +1,311 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+)
+
+func (s *Server) AddAccountHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "request reading error")
+		return
+	}
+
+	account := models.Account{}
+	err = json.Unmarshal(body, &account)
+	if msg, ok := invalidValue(err); ok {
+		JsonError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, "invalid json format")
+		return
+	}
+
+	account.Name = strings.TrimSpace(account.Name)
+	if account.Name == "" {
+		JsonError(w, http.StatusBadRequest, "account name cannot be empty")
+		return
+	}
+
+	if account.Type == "" {
+		account.Type = models.AccountBank
+	}
+	if !models.IsValidAccountType(account.Type) {
+		JsonError(w, http.StatusBadRequest, "type must be one of 'cash', 'bank', 'card', 'savings', 'other'")
+		return
+	}
+
+	account.Currency = strings.ToUpper(strings.TrimSpace(account.Currency))
+	if account.Currency != "" && !models.IsValidCurrency(account.Currency) {
+		JsonError(w, http.StatusBadRequest, "currency must be a 3-letter ISO 4217 code")
+		return
+	}
+
+	if !validOpeningBalance(w, account.OpeningBalance) {
+		return
+	}
+
+	account, err = s.db.AddAccount(r.Context(), user.UserID, &account)
+	if errors.Is(err, db.ErrAlreadyExists) {
+		JsonError(w, http.StatusConflict, "account with this name already exists")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to add account: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error adding account")
+		return
+	}
+
+	JsonResponse(w, http.StatusCreated, models.SuccessResponse{
+		Message: "account added successfully",
+		Data:    account,
+	})
+}
+
+// validOpeningBalance проверяет, что начальный остаток помещается в колонку БД;
+// иначе сам отвечает клиенту и возвращает false
+func validOpeningBalance(w http.ResponseWriter, balance models.Money) bool {
+	if balance > models.MaxBalance || balance < -models.MaxBalance {
+		JsonError(w, http.StatusBadRequest, fmt.Sprintf("opening_balance must be between -%s and %s", models.MaxBalance, models.MaxBalance))
+		return false
+	}
+	return true
+}
+
+func (s *Server) GetAccountsHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	accounts, err := s.db.GetAccounts(r.Context(), user.UserID)
+	if err != nil {
+		log.Printf("error retrieving accounts: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error retrieving accounts")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "accounts listed successfully",
+		Data:    accounts,
+	})
+}
+
+func (s *Server) UpdateAccountHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "request reading error")
+		return
+	}
+
+	update := models.AccountUpdate{}
+	err = json.Unmarshal(body, &update)
+	if msg, ok := invalidValue(err); ok {
+		JsonError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, "invalid json format")
+		return
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			JsonError(w, http.StatusBadRequest, "account name cannot be empty")
+			return
+		}
+		update.Name = &name
+	}
+	if update.Type != nil && !models.IsValidAccountType(*update.Type) {
+		JsonError(w, http.StatusBadRequest, "type must be one of 'cash', 'bank', 'card', 'savings', 'other'")
+		return
+	}
+	if update.OpeningBalance != nil && !validOpeningBalance(w, *update.OpeningBalance) {
+		return
+	}
+
+	account, err := s.db.UpdateAccount(r.Context(), user.UserID, id, &update)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("account with id %d not found or access denied", id))
+		return
+	}
+	if errors.Is(err, db.ErrAlreadyExists) {
+		JsonError(w, http.StatusConflict, "account with this name already exists")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to update account: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error updating account")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("account with id: %d successfully updated", id),
+		Data:    account,
+	})
+}
+
+func (s *Server) DeleteAccountHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = s.db.DeleteAccount(r.Context(), user.UserID, id)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("account with id %d not found or access denied", id))
+		return
+	}
+	if errors.Is(err, db.ErrAccountInUse) {
+		JsonError(w, http.StatusConflict, "account has transactions and cannot be deleted")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to delete account: %v", err)
+		JsonError(w, http.StatusInternalServerError, "failed to delete account")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("account with id: %d successfully deleted", id),
+	})
+}
+
+// AddTransferHandler переводит деньги между счетами пользователя. Для счетов в разных
+// валютах обязателен to_amount — сумма, зачисленная на счёт получателя.
+func (s *Server) AddTransferHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "request reading error")
+		return
+	}
+
+	transfer := models.Transfer{}
+	err = json.Unmarshal(body, &transfer)
+	if msg, ok := invalidValue(err); ok {
+		JsonError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, "invalid json format")
+		return
+	}
+
+	if transfer.FromAccountID <= 0 || transfer.ToAccountID <= 0 {
+		JsonError(w, http.StatusBadRequest, "from_account_id and to_account_id are required")
+		return
+	}
+	if transfer.FromAccountID == transfer.ToAccountID {
+		JsonError(w, http.StatusBadRequest, "cannot transfer to the same account")
+		return
+	}
+	if transfer.Amount <= 0 || transfer.ToAmount < 0 {
+		JsonError(w, http.StatusBadRequest, "amount must be greater than 0")
+		return
+	}
+	if transfer.Amount > models.MaxAmount || transfer.ToAmount > models.MaxAmount {
+		JsonError(w, http.StatusBadRequest, fmt.Sprintf("amount must not exceed %s", models.MaxAmount))
+		return
+	}
+
+	ctx := r.Context()
+
+	from, ok := s.userAccount(w, r, user.UserID, transfer.FromAccountID)
+	if !ok {
+		return
+	}
+	to, ok := s.userAccount(w, r, user.UserID, transfer.ToAccountID)
+	if !ok {
+		return
+	}
+	if transfer.ToAmount == 0 {
+		if from.Currency != to.Currency {
+			JsonError(w, http.StatusBadRequest, "to_amount is required for transfers between accounts in different currencies")
+			return
+		}
+		transfer.ToAmount = transfer.Amount
+	}
+
+	transfer, err = s.db.AddTransfer(ctx, user.UserID, &transfer)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusBadRequest, "account does not exist or access denied")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to add transfer: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error adding transfer")
+		return
+	}
+
+	JsonResponse(w, http.StatusCreated, models.SuccessResponse{
+		Message: "transfer added successfully",
+		Data:    transfer,
+	})
+}
+
+// userAccount загружает счёт пользователя, проверяя права доступа так же, как CheckCategory
+// для категорий; при ошибке пишет ответ и возвращает false
+func (s *Server) userAccount(w http.ResponseWriter, r *http.Request, userID, accountID int) (models.Account, bool) {
+	account, err := s.db.GetAccountByID(r.Context(), userID, accountID)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusBadRequest, "account does not exist or access denied")
+		return models.Account{}, false
+	}
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "database error during account check")
+		return models.Account{}, false
+	}
+	return account, true
+}
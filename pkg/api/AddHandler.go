@@ -2,8 +2,11 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	models "github.com/ViktorOHJ/expense-tracker/pkg"
 )
@@ -25,6 +28,10 @@ func (s *Server) AddHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = json.Unmarshal(body, &transaction)
+	if msg, ok := invalidValue(err); ok {
+		JsonError(w, http.StatusBadRequest, msg)
+		return
+	}
 	if err != nil {
 		JsonError(w, http.StatusBadRequest, "invalid json format")
 		return
@@ -35,6 +42,49 @@ func (s *Server) AddHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if transaction.Amount > models.MaxAmount {
+		JsonError(w, http.StatusBadRequest, fmt.Sprintf("amount must not exceed %s", models.MaxAmount))
+		return
+	}
+
+	transaction.Currency = strings.ToUpper(strings.TrimSpace(transaction.Currency))
+	if transaction.Currency != "" && !models.IsValidCurrency(transaction.Currency) {
+		JsonError(w, http.StatusBadRequest, "currency must be a 3-letter ISO 4217 code")
+		return
+	}
+
+	transaction.Tags, err = models.NormalizeTags(transaction.Tags)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(transaction.Splits) > 0 {
+		if err := models.ValidateSplits(transaction.Amount, transaction.Splits); err != nil {
+			JsonError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		// Без явной категории транзакция получает категорию первой строки разбивки
+		if transaction.CategoryID == 0 {
+			transaction.CategoryID = transaction.Splits[0].CategoryID
+		}
+	}
+
+	// Без категории её назначает первое подходящее правило пользователя
+	if transaction.CategoryID == 0 {
+		rules, err := s.db.GetCategoryRules(r.Context(), user.UserID)
+		if err != nil {
+			JsonError(w, http.StatusInternalServerError, "error retrieving rules")
+			return
+		}
+		if rule := models.MatchRule(rules, &transaction); rule != nil {
+			if err := models.ApplyRule(rule, &transaction); err != nil {
+				JsonError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+	}
+
 	if transaction.CategoryID <= 0 {
 		JsonError(w, http.StatusBadRequest, "category_id is required")
 		return
@@ -52,6 +102,21 @@ func (s *Server) AddHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !s.checkSplitCategories(w, r, user.UserID, transaction.Splits) {
+		return
+	}
+
+	if transaction.AccountID != nil {
+		account, ok := s.userAccount(w, r, user.UserID, *transaction.AccountID)
+		if !ok {
+			return
+		}
+		if transaction.Currency != "" && transaction.Currency != account.Currency {
+			JsonError(w, http.StatusBadRequest, "currency must match the account currency")
+			return
+		}
+	}
+
 	transaction, err = s.db.AddTransaction(ctx, user.UserID, &transaction)
 	if err != nil {
 		JsonError(w, http.StatusInternalServerError, "error adding transaction")
@@ -64,3 +129,36 @@ func (s *Server) AddHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	JsonResponse(w, http.StatusCreated, resp)
 }
+
+// checkSplitCategories проверяет, что категории строк разбивки принадлежат пользователю;
+// при ошибке ответ уже записан
+func (s *Server) checkSplitCategories(w http.ResponseWriter, r *http.Request, userID int, splits []models.Split) bool {
+	checked := make(map[int]bool, len(splits))
+	for _, split := range splits {
+		if checked[split.CategoryID] {
+			continue
+		}
+		exists, err := s.db.CheckCategory(r.Context(), userID, split.CategoryID)
+		if err != nil {
+			JsonError(w, http.StatusInternalServerError, "database error during category check")
+			return false
+		}
+		if !exists {
+			JsonError(w, http.StatusBadRequest, "category does not exist or access denied")
+			return false
+		}
+		checked[split.CategoryID] = true
+	}
+	return true
+}
+
+// invalidValue сопоставляет ошибку разбора суммы или даты (ErrTimeFormat) с сообщением
+// для клиента, чтобы некорректное значение не доходило до Postgres и не превращалось в 500
+func invalidValue(err error) (string, bool) {
+	for _, target := range []error{models.ErrAmountPrecision, models.ErrAmountRange, models.ErrAmountFormat, models.ErrTimeFormat} {
+		if errors.Is(err, target) {
+			return target.Error(), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/storage"
+)
+
+// maxAttachmentFileName — ограничение колонки file_name
+const maxAttachmentFileName = 255
+
+// UploadAttachmentHandler принимает multipart-форму с полем file и прикрепляет файл к транзакции
+func (s *Server) UploadAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if s.blobs == nil {
+		JsonError(w, http.StatusServiceUnavailable, "attachments are not configured")
+		return
+	}
+
+	transactionID, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Запас сверх размера файла — на заголовки multipart
+	r.Body = http.MaxBytesReader(w, r.Body, models.MaxAttachmentSize+1<<20)
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			JsonError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("file must not exceed %d MB", models.MaxAttachmentSize>>20))
+			return
+		}
+		JsonError(w, http.StatusBadRequest, "invalid multipart form")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	if header.Size > models.MaxAttachmentSize {
+		JsonError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("file must not exceed %d MB", models.MaxAttachmentSize>>20))
+		return
+	}
+	if header.Size == 0 {
+		JsonError(w, http.StatusBadRequest, "file is empty")
+		return
+	}
+
+	// Тип определяется по первым байтам файла, заголовку клиента не доверяем
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		JsonError(w, http.StatusBadRequest, "failed to read file")
+		return
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if !models.AttachmentContentTypes[contentType] {
+		JsonError(w, http.StatusUnsupportedMediaType, "file must be a JPEG, PNG, GIF, WebP image or a PDF document")
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		JsonError(w, http.StatusInternalServerError, "request reading error")
+		return
+	}
+
+	key, err := attachmentKey(user.UserID, transactionID)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error saving attachment")
+		return
+	}
+	if err := s.blobs.Put(r.Context(), key, file, header.Size, contentType); err != nil {
+		log.Printf("failed to store attachment: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error saving attachment")
+		return
+	}
+
+	attachment, err := s.db.AddAttachment(r.Context(), user.UserID, &models.Attachment{
+		TransactionID: transactionID,
+		FileName:      attachmentFileName(header.Filename),
+		ContentType:   contentType,
+		Size:          header.Size,
+		StorageKey:    key,
+	})
+	if err != nil {
+		s.deleteBlobs(r.Context(), []string{key})
+		if errors.Is(err, db.ErrNotFound) {
+			JsonError(w, http.StatusNotFound, fmt.Sprintf("transaction with id %d not found or access denied", transactionID))
+			return
+		}
+		log.Printf("failed to add attachment: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error saving attachment")
+		return
+	}
+
+	JsonResponse(w, http.StatusCreated, models.SuccessResponse{
+		Message: "attachment uploaded successfully",
+		Data:    attachment,
+	})
+}
+
+func (s *Server) GetAttachmentsHandler(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	transactionID, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	attachments, err := s.db.GetAttachments(r.Context(), user.UserID, transactionID)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("transaction with id %d not found or access denied", transactionID))
+		return
+	}
+	if err != nil {
+		log.Printf("failed to get attachments: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error retrieving attachments")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "attachments listed successfully",
+		Data:    attachments,
+	})
+}
+
+// DownloadAttachmentHandler отдаёт содержимое файла с исходным именем
+func (s *Server) DownloadAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if s.blobs == nil {
+		JsonError(w, http.StatusServiceUnavailable, "attachments are not configured")
+		return
+	}
+
+	transactionID, attachmentID, err := attachmentPath(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	attachment, err := s.db.GetAttachment(r.Context(), user.UserID, transactionID, attachmentID)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, "attachment not found or access denied")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to get attachment: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error retrieving attachment")
+		return
+	}
+
+	content, err := s.blobs.Get(r.Context(), attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		log.Printf("attachment %d has no stored file %q", attachment.ID, attachment.StorageKey)
+		JsonError(w, http.StatusNotFound, "attachment not found or access denied")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to read attachment: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error retrieving attachment")
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, content); err != nil {
+		log.Printf("attachment download interrupted for user %d: %v", user.UserID, err)
+	}
+}
+
+func (s *Server) DeleteAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	transactionID, attachmentID, err := attachmentPath(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	key, err := s.db.DeleteAttachment(r.Context(), user.UserID, transactionID, attachmentID)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, "attachment not found or access denied")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to delete attachment: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error deleting attachment")
+		return
+	}
+	s.deleteBlobs(r.Context(), []string{key})
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("attachment with id: %d successfully deleted", attachmentID),
+	})
+}
+
+// deleteBlobs удаляет файлы удалённых вложений. Запись в БД уже удалена, поэтому
+// ошибка хранилища только логируется: файл останется сиротой, но не виден пользователю.
+func (s *Server) deleteBlobs(ctx context.Context, keys []string) {
+	if s.blobs == nil {
+		return
+	}
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			log.Printf("failed to delete attachment file %q: %v", key, err)
+		}
+	}
+}
+
+func attachmentPath(r *http.Request) (int, int, error) {
+	transactionID, err := pathID(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	attachmentID, err := strconv.Atoi(strings.TrimSpace(r.PathValue("attachmentID")))
+	if err != nil || attachmentID <= 0 {
+		return 0, 0, errors.New("attachment id must be a positive number")
+	}
+	return transactionID, attachmentID, nil
+}
+
+// attachmentKey формирует случайный ключ файла; имя от клиента в ключ не попадает
+func attachmentKey(userID, transactionID int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("attachments/%d/%d/%s", userID, transactionID, hex.EncodeToString(b)), nil
+}
+
+func attachmentFileName(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, `\`, "/")))
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	for len(name) > maxAttachmentFileName {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
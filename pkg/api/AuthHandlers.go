@@ -1,12 +1,18 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
+	"log"
 	"net/http"
 	"regexp"
+	"strings"
+	"time"
 
 	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
 	"github.com/ViktorOHJ/expense-tracker/pkg/db"
 )
 
@@ -35,6 +41,18 @@ func (s *Server) RegisterHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	req.BaseCurrency = strings.ToUpper(strings.TrimSpace(req.BaseCurrency))
+	if req.BaseCurrency != "" && !models.IsValidCurrency(req.BaseCurrency) {
+		JsonError(w, http.StatusBadRequest, "base_currency must be a 3-letter ISO 4217 code")
+		return
+	}
+
+	req.TimeZone = strings.TrimSpace(req.TimeZone)
+	if req.TimeZone != "" && !models.IsValidTimeZone(req.TimeZone) {
+		JsonError(w, http.StatusBadRequest, "time_zone must be an IANA time zone name")
+		return
+	}
+
 	// Проверяем, существует ли пользователь
 	_, err = s.db.GetUserByEmail(r.Context(), req.Email)
 	if err == nil {
@@ -51,8 +69,10 @@ func (s *Server) RegisterHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Создаем пользователя
 	user := models.User{
-		Email:    req.Email,
-		Password: hashedPassword,
+		Email:        req.Email,
+		Password:     hashedPassword,
+		BaseCurrency: req.BaseCurrency,
+		TimeZone:     req.TimeZone,
 	}
 
 	createdUser, err := s.db.CreateUser(r.Context(), &user)
@@ -61,21 +81,14 @@ func (s *Server) RegisterHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Генерируем токен
-	token, err := s.jwtService.GenerateToken(createdUser.ID, createdUser.Email)
-	if err != nil {
-		JsonError(w, http.StatusInternalServerError, "error generating token")
-		return
-	}
-
-	response := models.AuthResponse{
-		Token: token,
-		User:  createdUser,
+	// Сессия выдаётся только после подтверждения адреса
+	if err := s.sendUserToken(r.Context(), createdUser, models.TokenVerifyEmail); err != nil {
+		log.Printf("failed to send verification email to user %d: %v", createdUser.ID, err)
 	}
 
 	JsonResponse(w, http.StatusCreated, models.SuccessResponse{
-		Message: "user registered successfully",
-		Data:    response,
+		Message: "user registered successfully, check your email to verify the address",
+		Data:    createdUser,
 	})
 }
 
@@ -93,43 +106,225 @@ func (s *Server) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	email := normalizeEmail(req.Email)
+	ip := s.clientIP(r)
+
 	// Получаем пользователя
 	user, err := s.db.GetUserByEmail(r.Context(), req.Email)
-	if err != nil {
-		if err == db.ErrNotFound {
-			JsonError(w, http.StatusUnauthorized, "invalid credentials")
-			return
-		}
+	if err != nil && err != db.ErrNotFound {
 		JsonError(w, http.StatusInternalServerError, "error retrieving user")
 		return
 	}
+	found := err == nil
+	var userID *int
+	if found {
+		userID = &user.ID
+	}
 
-	// Проверяем пароль
-	if !s.passwordService.CheckPassword(user.Password, req.Password) {
+	// Неудачи считаются и для неизвестных адресов, поэтому блокировка тоже не
+	// выдаёт, зарегистрирован ли адрес
+	if !s.checkLoginThrottle(w, r, userID, email, ip) {
+		return
+	}
+
+	// Пароль проверяется всегда, для неизвестного адреса — с фиктивным хешем,
+	// чтобы время ответа не зависело от существования пользователя
+	hash := s.passwordService.DummyHash()
+	if found {
+		hash = user.Password
+	}
+	if !s.passwordService.CheckPassword(hash, req.Password) || !found {
+		s.recordLoginAttempt(r.Context(), userID, email, ip, models.LoginInvalidCredentials)
 		JsonError(w, http.StatusUnauthorized, "invalid credentials")
 		return
 	}
 
-	// Генерируем токен
-	token, err := s.jwtService.GenerateToken(user.ID, user.Email)
-	if err != nil {
-		JsonError(w, http.StatusInternalServerError, "error generating token")
+	if !user.EmailVerified {
+		s.recordLoginAttempt(r.Context(), userID, email, ip, models.LoginEmailNotVerified)
+		JsonError(w, http.StatusForbidden, "email address is not verified")
 		return
 	}
 
-	response := models.AuthResponse{
-		Token: token,
-		User:  user,
+	// С включённой 2FA пароль даёт только challenge-токен для /auth/2fa/verify
+	if user.TwoFactorEnabled {
+		challenge, expiresAt, err := s.jwtService.IssueChallengeToken(user.ID, user.Email)
+		if err != nil {
+			JsonError(w, http.StatusInternalServerError, "error generating token")
+			return
+		}
+		JsonResponse(w, http.StatusOK, models.SuccessResponse{
+			Message: "two-factor authentication required",
+			Data: models.LoginChallenge{
+				TwoFactorRequired: true,
+				ChallengeToken:    challenge,
+				ExpiresAt:         expiresAt,
+			},
+		})
+		return
 	}
 
+	// Выдаём пару access/refresh токенов новой сессии
+	response, err := s.startSession(r.Context(), user)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error generating token")
+		return
+	}
+	s.recordLoginAttempt(r.Context(), userID, email, ip, models.LoginSucceeded)
+
 	JsonResponse(w, http.StatusOK, models.SuccessResponse{
 		Message: "login successful",
 		Data:    response,
 	})
 }
 
+func (s *Server) RefreshHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req models.RefreshRequest
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "request reading error")
+		return
+	}
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		JsonError(w, http.StatusBadRequest, "invalid json format")
+		return
+	}
+	if req.RefreshToken == "" {
+		JsonError(w, http.StatusBadRequest, "refresh_token is required")
+		return
+	}
+
+	// Старый токен обменивается на новый из того же семейства
+	refreshToken, refreshHash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		log.Printf("failed to refresh token: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error refreshing token")
+		return
+	}
+	jti, err := auth.NewTokenID()
+	if err != nil {
+		log.Printf("failed to refresh token: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error refreshing token")
+		return
+	}
+	next := models.RefreshToken{
+		TokenHash:       refreshHash,
+		AccessJTI:       jti,
+		AccessExpiresAt: time.Now().Add(s.jwtService.AccessTTL()),
+		ExpiresAt:       time.Now().Add(s.jwtService.RefreshTTL()),
+	}
+
+	user, err := s.db.RotateRefreshToken(r.Context(), auth.HashToken(req.RefreshToken), &next)
+	if errors.Is(err, db.ErrNotFound) || errors.Is(err, db.ErrTokenExpired) || errors.Is(err, db.ErrTokenReused) {
+		JsonError(w, http.StatusUnauthorized, "invalid refresh token")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to refresh token: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error refreshing token")
+		return
+	}
+
+	token, expiresAt, err := s.jwtService.IssueAccessToken(user.ID, user.Email, jti)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error generating token")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "token refreshed successfully",
+		Data: models.AuthResponse{
+			Token:        token,
+			ExpiresAt:    expiresAt,
+			RefreshToken: refreshToken,
+			User:         user,
+		},
+	})
+}
+
+func (s *Server) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	err := s.db.RevokeSession(r.Context(), user.UserID, user.ID, user.ExpiresAt.Time)
+	if err != nil {
+		log.Printf("failed to revoke session: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error logging out")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "logged out successfully",
+	})
+}
+
+// startSession выдаёт access-токен и refresh-токен нового семейства
+func (s *Server) startSession(ctx context.Context, user models.User) (models.AuthResponse, error) {
+	jti, err := auth.NewTokenID()
+	if err != nil {
+		return models.AuthResponse{}, err
+	}
+	familyID, err := auth.NewTokenID()
+	if err != nil {
+		return models.AuthResponse{}, err
+	}
+	token, expiresAt, err := s.jwtService.IssueAccessToken(user.ID, user.Email, jti)
+	if err != nil {
+		return models.AuthResponse{}, err
+	}
+
+	refreshToken, refreshHash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return models.AuthResponse{}, err
+	}
+	err = s.db.CreateRefreshToken(ctx, &models.RefreshToken{
+		UserID:          user.ID,
+		FamilyID:        familyID,
+		TokenHash:       refreshHash,
+		AccessJTI:       jti,
+		AccessExpiresAt: expiresAt,
+		ExpiresAt:       time.Now().Add(s.jwtService.RefreshTTL()),
+	})
+	if err != nil {
+		return models.AuthResponse{}, err
+	}
+
+	return models.AuthResponse{
+		Token:        token,
+		ExpiresAt:    expiresAt,
+		RefreshToken: refreshToken,
+		User:         user,
+	}, nil
+}
+
 func isValidEmail(email string) bool {
 	pattern := `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
 	matched, _ := regexp.MatchString(pattern, email)
 	return matched
 }
+
+// JWKSHandler публикует открытые ключи подписи JWT (RFC 7517), чтобы другие сервисы
+// проверяли наши токены без общего секрета. Ответ — сам набор ключей, без обёртки.
+func (s *Server) JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	JsonResponse(w, http.StatusOK, s.jwtService.JWKS())
+}
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+)
+
+func (s *Server) AddBudgetHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "request reading error")
+		return
+	}
+
+	budget := models.Budget{}
+	err = json.Unmarshal(body, &budget)
+	if msg, ok := invalidValue(err); ok {
+		JsonError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, "invalid json format")
+		return
+	}
+
+	if budget.Amount <= 0 {
+		JsonError(w, http.StatusBadRequest, "amount must be greater than 0")
+		return
+	}
+	if budget.Amount > models.MaxAmount {
+		JsonError(w, http.StatusBadRequest, fmt.Sprintf("amount must not exceed %s", models.MaxAmount))
+		return
+	}
+
+	switch budget.Period {
+	case "", models.BudgetMonthly:
+		budget.Period = models.BudgetMonthly
+		// Месячный бюджет всегда начинается с первого дня месяца
+		start := budget.StartDate.Time
+		if start.IsZero() {
+			start = time.Now()
+		}
+		budget.StartDate = models.NewDate(time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC))
+	case models.BudgetCustom:
+		if budget.StartDate.IsZero() || budget.EndDate == nil {
+			JsonError(w, http.StatusBadRequest, "start_date and end_date are required for custom period")
+			return
+		}
+		if budget.Rollover {
+			JsonError(w, http.StatusBadRequest, "rollover is only supported for monthly budgets")
+			return
+		}
+	default:
+		JsonError(w, http.StatusBadRequest, "period must be 'monthly' or 'custom'")
+		return
+	}
+
+	if budget.EndDate != nil && budget.EndDate.Before(budget.StartDate.Time) {
+		JsonError(w, http.StatusBadRequest, "end_date must not be before start_date")
+		return
+	}
+
+	ctx := r.Context()
+
+	if budget.CategoryID != nil {
+		exists, err := s.db.CheckCategory(ctx, user.UserID, *budget.CategoryID)
+		if err != nil {
+			JsonError(w, http.StatusInternalServerError, "database error during category check")
+			return
+		}
+		if !exists {
+			JsonError(w, http.StatusBadRequest, "category does not exist or access denied")
+			return
+		}
+	}
+
+	budget, err = s.db.AddBudget(ctx, user.UserID, &budget)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error adding budget")
+		return
+	}
+
+	JsonResponse(w, http.StatusCreated, models.SuccessResponse{
+		Message: "budget added successfully",
+		Data:    budget,
+	})
+}
+
+func (s *Server) GetBudgetsHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	on := time.Now().UTC()
+	if dateStr := strings.TrimSpace(r.URL.Query().Get("date")); dateStr != "" {
+		d, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			JsonError(w, http.StatusBadRequest, "invalid date format for 'date'")
+			return
+		}
+		on = d
+	}
+
+	statuses, err := s.db.GetBudgetStatuses(r.Context(), user.UserID, on)
+	if err != nil {
+		log.Printf("error retrieving budgets: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error retrieving budgets")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "budgets listed successfully",
+		Data:    statuses,
+	})
+}
+
+func (s *Server) UpdateBudgetHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "request reading error")
+		return
+	}
+
+	update := models.BudgetUpdate{}
+	err = json.Unmarshal(body, &update)
+	if msg, ok := invalidValue(err); ok {
+		JsonError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, "invalid json format")
+		return
+	}
+
+	if update.Amount == nil && update.EndDate == nil && update.Rollover == nil {
+		JsonError(w, http.StatusBadRequest, "no fields to update")
+		return
+	}
+	if update.Amount != nil && (*update.Amount <= 0 || *update.Amount > models.MaxAmount) {
+		JsonError(w, http.StatusBadRequest, fmt.Sprintf("amount must be greater than 0 and not exceed %s", models.MaxAmount))
+		return
+	}
+
+	// Изменение проверяется вместе с текущим бюджетом по тем же правилам, что и создание
+	current, err := s.db.GetBudgetByID(r.Context(), user.UserID, id)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("budget with id %d not found or access denied", id))
+		return
+	}
+	if err != nil {
+		log.Printf("failed to retrieve budget: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error updating budget")
+		return
+	}
+	if update.Rollover != nil && *update.Rollover && current.Period != models.BudgetMonthly {
+		JsonError(w, http.StatusBadRequest, "rollover is only supported for monthly budgets")
+		return
+	}
+	if update.EndDate != nil && update.EndDate.Before(current.StartDate.Time) {
+		JsonError(w, http.StatusBadRequest, "end_date must not be before start_date")
+		return
+	}
+
+	budget, err := s.db.UpdateBudget(r.Context(), user.UserID, id, &update)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("budget with id %d not found or access denied", id))
+		return
+	}
+	if err != nil {
+		log.Printf("failed to update budget: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error updating budget")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("budget with id: %d successfully updated", id),
+		Data:    budget,
+	})
+}
+
+func (s *Server) DeleteBudgetHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = s.db.DeleteBudget(r.Context(), user.UserID, id)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("budget with id %d not found or access denied", id))
+		return
+	}
+	if err != nil {
+		log.Printf("failed to delete budget: %v", err)
+		JsonError(w, http.StatusInternalServerError, "failed to delete budget")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("budget with id: %d successfully deleted", id),
+	})
+}
@@ -2,13 +2,17 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
+	"log"
 	"net/http"
+	"strings"
 
 	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
 )
 
-func (s *Server) CategoriesHandler(w http.ResponseWriter, r *http.Request) {
+func (s *Server) AddCategoryHandler(w http.ResponseWriter, r *http.Request) {
 
 	user := GetUserFromContext(r.Context())
 	if user == nil {
@@ -34,7 +38,27 @@ func (s *Server) CategoriesHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if category.ParentID != nil {
+		if *category.ParentID <= 0 {
+			JsonError(w, http.StatusBadRequest, "parent_id must be a positive number")
+			return
+		}
+		exists, err := s.db.CheckCategory(r.Context(), user.UserID, *category.ParentID)
+		if err != nil {
+			JsonError(w, http.StatusInternalServerError, "database error during category check")
+			return
+		}
+		if !exists {
+			JsonError(w, http.StatusBadRequest, "parent category does not exist or access denied")
+			return
+		}
+	}
+
 	category, err = s.db.AddCategory(r.Context(), user.UserID, &category)
+	if errors.Is(err, db.ErrAlreadyExists) {
+		JsonError(w, http.StatusConflict, "category with this name already exists")
+		return
+	}
 	if err != nil {
 		JsonError(w, http.StatusInternalServerError, "error adding category")
 		return
@@ -46,3 +70,35 @@ func (s *Server) CategoriesHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	JsonResponse(w, http.StatusCreated, resp)
 }
+
+func (s *Server) GetCategoriesHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	includeArchived := false
+	archivedStr := strings.TrimSpace(r.URL.Query().Get("include_archived"))
+	switch archivedStr {
+	case "", "false":
+	case "true":
+		includeArchived = true
+	default:
+		JsonError(w, http.StatusBadRequest, "invalid include_archived parameter")
+		return
+	}
+
+	categories, err := s.db.GetCategories(r.Context(), user.UserID, includeArchived)
+	if err != nil {
+		log.Printf("error retrieving categories: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error retrieving categories")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "categories listed successfully",
+		Data:    categories,
+	})
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+)
+
+func (s *Server) CategoryByIdHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	category, err := s.db.GetCategoryByID(r.Context(), user.UserID, id)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("category with id %d not found or access denied", id))
+		return
+	}
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error retrieving category")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("category with id: %d successfully retrieved", id),
+		Data:    category,
+	})
+}
+
+func (s *Server) UpdateCategoryHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "request reading error")
+		return
+	}
+
+	update := models.CategoryUpdate{}
+	if err := json.Unmarshal(body, &update); err != nil {
+		JsonError(w, http.StatusBadRequest, "invalid json format")
+		return
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			JsonError(w, http.StatusBadRequest, "category name cannot be empty")
+			return
+		}
+		update.Name = &name
+	}
+
+	if update.ParentID != nil {
+		if *update.ParentID < 0 {
+			JsonError(w, http.StatusBadRequest, "parent_id must be a positive number or 0")
+			return
+		}
+		if *update.ParentID == id {
+			JsonError(w, http.StatusBadRequest, "category cannot be its own parent")
+			return
+		}
+		if *update.ParentID > 0 {
+			exists, err := s.db.CheckCategory(r.Context(), user.UserID, *update.ParentID)
+			if err != nil {
+				JsonError(w, http.StatusInternalServerError, "database error during category check")
+				return
+			}
+			if !exists {
+				JsonError(w, http.StatusBadRequest, "parent category does not exist or access denied")
+				return
+			}
+		}
+	}
+
+	category, err := s.db.UpdateCategory(r.Context(), user.UserID, id, &update)
+	if errors.Is(err, db.ErrCategoryCycle) {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("category with id %d not found or access denied", id))
+		return
+	}
+	if errors.Is(err, db.ErrAlreadyExists) {
+		JsonError(w, http.StatusConflict, "category with this name already exists")
+		return
+	}
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error updating category")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("category with id: %d successfully updated", id),
+		Data:    category,
+	})
+}
+
+func (s *Server) DeleteCategoryHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Если указан reassign_to, транзакции переносятся в эту категорию перед удалением
+	var reassignTo *int
+	reassignStr := strings.TrimSpace(r.URL.Query().Get("reassign_to"))
+	if reassignStr != "" {
+		target, err := strconv.Atoi(reassignStr)
+		if err != nil || target <= 0 {
+			JsonError(w, http.StatusBadRequest, "reassign_to must be a positive number")
+			return
+		}
+		if target == id {
+			JsonError(w, http.StatusBadRequest, "reassign_to must differ from the deleted category")
+			return
+		}
+
+		exists, err := s.db.CheckCategory(r.Context(), user.UserID, target)
+		if err != nil {
+			JsonError(w, http.StatusInternalServerError, "database error during category check")
+			return
+		}
+		if !exists {
+			JsonError(w, http.StatusBadRequest, "target category does not exist or access denied")
+			return
+		}
+		reassignTo = &target
+	}
+
+	err = s.db.DeleteCategory(r.Context(), user.UserID, id, reassignTo)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, "category not found or access denied")
+		return
+	}
+	if errors.Is(err, db.ErrCategoryInUse) {
+		JsonError(w, http.StatusConflict, "category is in use by transactions or recurring rules; pass reassign_to to move them or archive the category instead")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to delete category: %v", err)
+		JsonError(w, http.StatusInternalServerError, "failed to delete category")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("category with id: %d successfully deleted", id),
+	})
+}
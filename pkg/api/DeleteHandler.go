@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"strconv"
-	"strings"
 
 	models "github.com/ViktorOHJ/expense-tracker/pkg"
 	"github.com/ViktorOHJ/expense-tracker/pkg/db"
@@ -20,19 +18,13 @@ func (s *Server) DeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	idStr := strings.TrimSpace(r.URL.Query().Get("id"))
-	if idStr == "" {
-		JsonError(w, http.StatusBadRequest, "id cannot be empty")
-		return
-	}
-
-	id, err := strconv.Atoi(idStr)
-	if err != nil || id <= 0 {
-		JsonError(w, http.StatusBadRequest, "id must be a positive number")
+	id, err := transactionID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	err = s.db.DeleteTransaction(r.Context(), user.UserID, id)
+	keys, err := s.db.DeleteTransaction(r.Context(), user.UserID, id)
 	if errors.Is(err, db.ErrNotFound) {
 		JsonError(w, http.StatusNotFound, "transaction not found or access denied")
 		return
@@ -43,6 +35,8 @@ func (s *Server) DeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.deleteBlobs(r.Context(), keys)
+
 	JsonResponse(w, http.StatusOK, models.SuccessResponse{
 		Message: fmt.Sprintf("transaction with id: %d successfully deleted", id),
 	})
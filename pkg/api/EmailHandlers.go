@@ -0,0 +1,243 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mail"
+)
+
+const (
+	verifyEmailTTL   = 24 * time.Hour
+	resetPasswordTTL = time.Hour
+	// mailTimeout ограничивает фоновую отправку письма
+	mailTimeout = 30 * time.Second
+)
+
+// VerifyEmailHandler подтверждает адрес по токену из письма: GET ?token= для ссылки
+// из письма или POST {"token": ...} от клиентского приложения
+func (s *Server) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	var token string
+	switch r.Method {
+	case http.MethodGet:
+		token = r.URL.Query().Get("token")
+	case http.MethodPost:
+		var req models.VerifyEmailRequest
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		token = req.Token
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if token == "" {
+		JsonError(w, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	user, err := s.db.VerifyEmail(r.Context(), auth.HashToken(token))
+	if errors.Is(err, db.ErrNotFound) || errors.Is(err, db.ErrTokenExpired) {
+		JsonError(w, http.StatusBadRequest, "invalid or expired token")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to verify email: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error verifying email")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "email verified successfully",
+		Data:    user,
+	})
+}
+
+// ResendVerificationHandler отправляет письмо подтверждения повторно. Ответ не зависит
+// от того, есть ли такой пользователь, чтобы по нему нельзя было перебирать адреса.
+func (s *Server) ResendVerificationHandler(w http.ResponseWriter, r *http.Request) {
+	s.mailTokenByEmail(w, r, models.TokenVerifyEmail,
+		"if the address is registered and not yet verified, a verification email has been sent")
+}
+
+// ForgotPasswordHandler отправляет письмо со ссылкой сброса пароля; ответ, как и
+// при повторном подтверждении, одинаков для известных и неизвестных адресов
+func (s *Server) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	s.mailTokenByEmail(w, r, models.TokenResetPassword,
+		"if the address is registered, a password reset email has been sent")
+}
+
+// ResetPasswordHandler задаёт новый пароль по токену из письма и завершает все сессии
+func (s *Server) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req models.ResetPasswordRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Token == "" {
+		JsonError(w, http.StatusBadRequest, "token is required")
+		return
+	}
+	if len(req.Password) < 6 {
+		JsonError(w, http.StatusBadRequest, "password must be at least 6 characters")
+		return
+	}
+
+	hashedPassword, err := s.passwordService.HashPassword(req.Password)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error processing password")
+		return
+	}
+
+	user, err := s.db.ResetPassword(r.Context(), auth.HashToken(req.Token), hashedPassword)
+	if errors.Is(err, db.ErrNotFound) || errors.Is(err, db.ErrTokenExpired) {
+		JsonError(w, http.StatusBadRequest, "invalid or expired token")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to reset password: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error resetting password")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "password reset successfully",
+		Data:    user,
+	})
+}
+
+// mailTokenByEmail — общая часть повторного подтверждения и забытого пароля. Частота
+// запросов ограничивается для адреса и для IP независимо от того, зарегистрирован ли
+// адрес, а пользователь ищется и письмо отправляется уже после ответа, чтобы ни ответ,
+// ни его время не выдавали зарегистрированные адреса.
+func (s *Server) mailTokenByEmail(w http.ResponseWriter, r *http.Request, purpose, message string) {
+	if r.Method != http.MethodPost {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req models.EmailRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if !isValidEmail(req.Email) {
+		JsonError(w, http.StatusBadRequest, "invalid email format")
+		return
+	}
+
+	if !s.checkMailThrottle(w, r, normalizeEmail(req.Email), s.clientIP(r)) {
+		return
+	}
+	go s.mailToken(context.WithoutCancel(r.Context()), req.Email, purpose)
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: message,
+	})
+}
+
+// checkMailThrottle отвечает 429, если письма на адрес email или с адреса ip запрашивались
+// слишком часто; иначе записывает запрос и возвращает true
+func (s *Server) checkMailThrottle(w http.ResponseWriter, r *http.Request, email, ip string) bool {
+	now := time.Now()
+	f, err := s.db.GetMailRequests(r.Context(), email, ip, now.Add(-throttleWindow))
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error checking email requests")
+		return false
+	}
+	if wait := retryAfter(f, mailAccountThrottle, mailIPThrottle, now); wait > 0 {
+		retryLater(w, wait, "too many email requests")
+		return false
+	}
+	s.recordLoginAttempt(r.Context(), nil, email, ip, models.LoginMailRequested)
+	return true
+}
+
+// mailToken отправляет письмо с токеном purpose владельцу адреса email, если он
+// зарегистрирован; вызывается в фоне, поэтому ошибки только пишутся в лог
+func (s *Server) mailToken(parentCtx context.Context, email, purpose string) {
+	ctx, cancel := context.WithTimeout(parentCtx, mailTimeout)
+	defer cancel()
+
+	user, err := s.db.GetUserByEmail(ctx, email)
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+	case err != nil:
+		log.Printf("failed to retrieve user for %s email: %v", purpose, err)
+	case purpose == models.TokenVerifyEmail && user.EmailVerified:
+	default:
+		if err := s.sendUserToken(ctx, user, purpose); err != nil {
+			log.Printf("failed to send %s email to user %d: %v", purpose, user.ID, err)
+		}
+	}
+}
+
+// sendUserToken выпускает одноразовый токен и отправляет его владельцу адреса
+func (s *Server) sendUserToken(ctx context.Context, user models.User, purpose string) error {
+	ttl, path, subject, expires := verifyEmailTTL, "/verify-email", "Confirm your email address", "24 hours"
+	if purpose == models.TokenResetPassword {
+		ttl, path, subject, expires = resetPasswordTTL, "/reset-password", "Reset your password", "1 hour"
+	}
+
+	token, hash, err := auth.GenerateToken()
+	if err != nil {
+		return err
+	}
+	err = s.db.CreateUserToken(ctx, &models.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	var body strings.Builder
+	if purpose == models.TokenResetPassword {
+		body.WriteString("Someone requested a password reset for your Expense Tracker account.\n")
+		body.WriteString("If it was not you, ignore this email: your password stays the same.\n\n")
+	} else {
+		body.WriteString("Welcome to Expense Tracker! Please confirm your email address.\n\n")
+	}
+	if s.appURL != "" {
+		fmt.Fprintf(&body, "Open this link: %s%s?token=%s\n", s.appURL, path, url.QueryEscape(token))
+	} else {
+		fmt.Fprintf(&body, "Your token: %s\n", token)
+	}
+	fmt.Fprintf(&body, "\nThe link expires in %s.\n", expires)
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: subject,
+		Body:    body.String(),
+	})
+}
+
+// decodeBody читает JSON-тело запроса; при ошибке сам отвечает клиенту и возвращает false
+func decodeBody(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "request reading error")
+		return false
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		JsonError(w, http.StatusBadRequest, "invalid json format")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/export"
+)
+
+// ExportHandler выгружает все транзакции по фильтрам GET /transactions в CSV, JSON Lines или XLSX.
+// Строки пишутся в ответ по мере чтения из БД.
+func (s *Server) ExportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	q := r.URL.Query()
+	format := strings.ToLower(strings.TrimSpace(q.Get("format")))
+	if format == "" {
+		format = "csv"
+	}
+	contentType, ok := export.ContentTypes[format]
+	if !ok {
+		JsonError(w, http.StatusBadRequest, "format must be one of 'csv', 'jsonl', 'xlsx'")
+		return
+	}
+
+	filter, err := parseTransactionFilter(q)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Заголовки отправляются вместе с первой строкой: до неё ошибку ещё можно вернуть в JSON
+	var writer export.Writer
+	write := func(t models.ExportedTransaction) error {
+		if writer == nil {
+			started, err := startExport(w, format, contentType)
+			if err != nil {
+				return err
+			}
+			writer = started
+		}
+		return writer.Write(t)
+	}
+
+	err = s.db.ExportTransactions(r.Context(), user.UserID, filter, write)
+	if err != nil && writer == nil {
+		log.Printf("failed to export transactions: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error exporting transactions")
+		return
+	}
+	if err != nil {
+		// Ответ уже начат, статус не изменить — обрываем выгрузку
+		log.Printf("export interrupted for user %d: %v", user.UserID, err)
+		return
+	}
+
+	if writer == nil {
+		// Пустая выборка — файл только с заголовком
+		if writer, err = startExport(w, format, contentType); err != nil {
+			log.Printf("failed to start export: %v", err)
+			return
+		}
+	}
+	if err := writer.Close(); err != nil {
+		log.Printf("failed to finish export: %v", err)
+	}
+}
+
+func startExport(w http.ResponseWriter, format, contentType string) (export.Writer, error) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="transactions.%s"`, format))
+	w.WriteHeader(http.StatusOK)
+	return export.NewWriter(format, w)
+}
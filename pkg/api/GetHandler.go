@@ -1,7 +1,9 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -18,53 +20,10 @@ func (s *Server) GetHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	q := r.URL.Query()
-	txType := strings.TrimSpace(q.Get("type"))
-	var typeBool *bool
-	if txType != "" {
-		switch txType {
-		case "true":
-			typeBool = new(bool)
-			*typeBool = true
-		case "false":
-			typeBool = new(bool)
-			*typeBool = false
-		default:
-			JsonError(w, http.StatusBadRequest, "invalid type parameter")
-			return
-		}
-	}
-
-	categoryID := strings.TrimSpace(q.Get("category_id"))
-	var categoryInt *int
-	if categoryID != "" {
-		c, err := strconv.Atoi(categoryID)
-		if err != nil {
-			JsonError(w, http.StatusBadRequest, "invalid category_id format")
-			return
-		}
-		categoryInt = &c
-	}
-
-	// Парсинг дат (без изменений)
-	from := strings.TrimSpace(q.Get("from"))
-	var fromTime, toTime *time.Time
-	if from != "" {
-		f, err := time.Parse("2006-01-02", from)
-		if err != nil {
-			JsonError(w, http.StatusBadRequest, "invalid date format for 'from'")
-			return
-		}
-		fromTime = &f
-	}
-
-	to := strings.TrimSpace(q.Get("to"))
-	if to != "" {
-		t, err := time.Parse("2006-01-02", to)
-		if err != nil {
-			JsonError(w, http.StatusBadRequest, "invalid date format for 'to'")
-			return
-		}
-		toTime = &t
+	filter, err := parseTransactionFilter(q)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
 	limitStr := strings.TrimSpace(q.Get("limit"))
@@ -85,7 +44,7 @@ func (s *Server) GetHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	offset := (page - 1) * limit
 
-	transactions, err := s.db.GetTransactions(r.Context(), user.UserID, typeBool, categoryInt, fromTime, toTime, limit, offset)
+	transactions, err := s.db.GetTransactions(r.Context(), user.UserID, filter, limit, offset)
 	if err != nil {
 		JsonError(w, http.StatusInternalServerError, "error retrieving transactions")
 		return
@@ -105,3 +64,68 @@ func (s *Server) GetHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	JsonResponse(w, http.StatusOK, resp)
 }
+
+// parseTransactionFilter разбирает общие для списка и выгрузки фильтры: type, category_id,
+// from, to и tags (через запятую) с tag_mode=any|all
+func parseTransactionFilter(q url.Values) (models.TransactionFilter, error) {
+	var filter models.TransactionFilter
+
+	txType := strings.TrimSpace(q.Get("type"))
+	if txType != "" {
+		switch txType {
+		case "true":
+			filter.IsIncome = new(bool)
+			*filter.IsIncome = true
+		case "false":
+			filter.IsIncome = new(bool)
+			*filter.IsIncome = false
+		default:
+			return filter, errors.New("invalid type parameter")
+		}
+	}
+
+	categoryID := strings.TrimSpace(q.Get("category_id"))
+	if categoryID != "" {
+		c, err := strconv.Atoi(categoryID)
+		if err != nil {
+			return filter, errors.New("invalid category_id format")
+		}
+		filter.CategoryID = &c
+	}
+
+	from := strings.TrimSpace(q.Get("from"))
+	if from != "" {
+		f, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return filter, errors.New("invalid date format for 'from'")
+		}
+		filter.From = &f
+	}
+
+	to := strings.TrimSpace(q.Get("to"))
+	if to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return filter, errors.New("invalid date format for 'to'")
+		}
+		filter.To = &t
+	}
+
+	tags := strings.TrimSpace(q.Get("tags"))
+	if tags != "" {
+		t, err := models.NormalizeTags(strings.Split(tags, ","))
+		if err != nil {
+			return filter, err
+		}
+		filter.Tags = t
+	}
+
+	switch strings.TrimSpace(q.Get("tag_mode")) {
+	case "", "any":
+	case "all":
+		filter.AllTags = true
+	default:
+		return filter, errors.New("tag_mode must be 'any' or 'all'")
+	}
+	return filter, nil
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/importer"
+)
+
+// maxImportSize ограничивает размер загружаемого файла выписки
+const maxImportSize = 10 << 20
+
+// ImportCSVHandler принимает multipart-форму с полями file (CSV), mapping (JSON
+// с сопоставлением колонок), dry_run и create_categories
+func (s *Server) ImportCSVHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if err := r.ParseMultipartForm(maxImportSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			JsonError(w, http.StatusRequestEntityTooLarge, "file is too large")
+			return
+		}
+		JsonError(w, http.StatusBadRequest, "invalid multipart form")
+		return
+	}
+
+	mapping := models.ImportMapping{}
+	if err := json.Unmarshal([]byte(r.FormValue("mapping")), &mapping); err != nil {
+		JsonError(w, http.StatusBadRequest, "invalid mapping format")
+		return
+	}
+
+	opts := models.ImportOptions{}
+	for name, target := range map[string]*bool{"dry_run": &opts.DryRun, "create_categories": &opts.CreateCategories} {
+		if value := r.FormValue(name); value != "" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				JsonError(w, http.StatusBadRequest, "invalid "+name+" parameter")
+				return
+			}
+			*target = b
+		}
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	rows, err := importer.ParseCSV(file, mapping)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(rows) == 0 {
+		JsonError(w, http.StatusBadRequest, "file contains no rows")
+		return
+	}
+
+	result, err := s.db.ImportTransactions(r.Context(), user.UserID, rows, opts)
+	if errors.Is(err, db.ErrImportInvalid) {
+		JsonResponse(w, http.StatusUnprocessableEntity, models.SuccessResponse{
+			Message: "file contains invalid rows, nothing imported",
+			Data:    result,
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("failed to import transactions: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error importing transactions")
+		return
+	}
+
+	message := "transactions imported successfully"
+	if opts.DryRun {
+		message = "import preview"
+	}
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: message,
+		Data:    result,
+	})
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+// throttlePolicy задаёт задержку входа по числу неудачных попыток: первые free
+// проходят без задержки, дальше задержка удваивается от baseDelay, а начиная с
+// lockAfter вход блокируется на lockout после последней неудачи
+type throttlePolicy struct {
+	free      int
+	baseDelay time.Duration
+	lockAfter int
+	lockout   time.Duration
+}
+
+var (
+	accountThrottle = throttlePolicy{free: 3, baseDelay: time.Second, lockAfter: 10, lockout: 15 * time.Minute}
+	ipThrottle      = throttlePolicy{free: 20, baseDelay: time.Second, lockAfter: 100, lockout: time.Hour}
+
+	// Письма со ссылками: не больше 10 на адрес за сутки, чтобы нельзя было завалить ящик
+	mailAccountThrottle = throttlePolicy{free: 3, baseDelay: time.Minute, lockAfter: 10, lockout: 24 * time.Hour}
+	mailIPThrottle      = throttlePolicy{free: 10, baseDelay: time.Minute, lockAfter: 50, lockout: time.Hour}
+)
+
+// throttleWindow — неудачи старше окна не учитываются
+const throttleWindow = 24 * time.Hour
+
+func (p throttlePolicy) delay(failures int) time.Duration {
+	if failures < p.free {
+		return 0
+	}
+	if failures >= p.lockAfter {
+		return p.lockout
+	}
+	// Сдвиг сравнивается с lockout до умножения: при большом числе неудач
+	// baseDelay << shift переполняет time.Duration
+	shift := failures - p.free
+	if shift >= 63 || p.baseDelay > p.lockout>>shift {
+		return p.lockout
+	}
+	return p.baseDelay << shift
+}
+
+// loginRetryAfter возвращает, сколько ещё ждать до следующей попытки входа по адресу
+// email с адреса ip; 0 — попытка разрешена
+func (s *Server) loginRetryAfter(ctx context.Context, email, ip string) (time.Duration, error) {
+	now := time.Now()
+	f, err := s.db.GetLoginFailures(ctx, email, ip, now.Add(-throttleWindow))
+	if err != nil {
+		return 0, err
+	}
+	return retryAfter(f, accountThrottle, ipThrottle, now), nil
+}
+
+// retryAfter возвращает, сколько ещё ждать по счётчикам f при ограничениях для адреса
+// и для IP; 0 — попытка разрешена
+func retryAfter(f models.LoginFailures, account, ip throttlePolicy, now time.Time) time.Duration {
+	wait := f.AccountLast.Add(account.delay(f.Account)).Sub(now)
+	if ipWait := f.IPLast.Add(ip.delay(f.IP)).Sub(now); ipWait > wait {
+		wait = ipWait
+	}
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// retryLater отвечает 429 с заголовком Retry-After
+func retryLater(w http.ResponseWriter, wait time.Duration, reason string) {
+	seconds := int((wait + time.Second - 1) / time.Second)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	JsonError(w, http.StatusTooManyRequests, fmt.Sprintf("%s, try again in %d seconds", reason, seconds))
+}
+
+// checkLoginThrottle отвечает 429, если попытки входа для адреса или IP временно
+// ограничены; отклонённая попытка попадает в журнал, но блокировку не продлевает
+func (s *Server) checkLoginThrottle(w http.ResponseWriter, r *http.Request, userID *int, email, ip string) bool {
+	wait, err := s.loginRetryAfter(r.Context(), email, ip)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error checking login attempts")
+		return false
+	}
+	if wait == 0 {
+		return true
+	}
+
+	s.recordLoginAttempt(r.Context(), userID, email, ip, models.LoginThrottled)
+	retryLater(w, wait, "too many failed login attempts")
+	return false
+}
+
+// recordLoginAttempt пишет попытку в журнал; ошибка записи не мешает ответу клиенту
+func (s *Server) recordLoginAttempt(ctx context.Context, userID *int, email, ip, reason string) {
+	err := s.db.RecordLoginAttempt(ctx, &models.LoginAttempt{
+		UserID:  userID,
+		Email:   email,
+		IP:      ip,
+		Success: reason == models.LoginSucceeded,
+		Reason:  reason,
+	})
+	if err != nil {
+		log.Printf("failed to record login attempt for %s: %v", email, err)
+	}
+}
+
+// clientIP возвращает адрес клиента. За обратным прокси (WithTrustProxy) берётся
+// последний адрес X-Forwarded-For — его дописал сам прокси, остальные мог подставить клиент.
+func (s *Server) clientIP(r *http.Request) string {
+	if s.trustProxy {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			parts := strings.Split(xff, ",")
+			if ip := net.ParseIP(strings.TrimSpace(parts[len(parts)-1])); ip != nil {
+				return ip.String()
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// normalizeEmail приводит адрес к виду, в котором считаются попытки входа
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// LoginAttemptsHandler показывает пользователю попытки входа в его аккаунт, новые первыми
+func (s *Server) LoginAttemptsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	q := r.URL.Query()
+	limit := 50
+	if v := strings.TrimSpace(q.Get("limit")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 200 {
+			JsonError(w, http.StatusBadRequest, "invalid limit parameter")
+			return
+		}
+		limit = n
+	}
+	page := 1
+	if v := strings.TrimSpace(q.Get("offset")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			JsonError(w, http.StatusBadRequest, "invalid offset parameter")
+			return
+		}
+		if n > 0 {
+			page = n
+		}
+	}
+
+	attempts, err := s.db.GetLoginAttempts(r.Context(), user.UserID, limit, (page-1)*limit)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error retrieving login attempts")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "login attempts retrieved successfully",
+		Data:    attempts,
+	})
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottlePolicy_Delay(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy throttlePolicy
+		want   map[int]time.Duration // ожидаемые задержки; остальные проверяются по инвариантам
+	}{
+		{
+			name:   "account",
+			policy: accountThrottle,
+			want: map[int]time.Duration{
+				0: 0, 2: 0, 3: time.Second, 4: 2 * time.Second, 9: 64 * time.Second,
+				10: 15 * time.Minute, 11: 15 * time.Minute,
+			},
+		},
+		{
+			name:   "ip",
+			policy: ipThrottle,
+			want: map[int]time.Duration{
+				19: 0, 20: time.Second, 30: 1024 * time.Second, 31: 2048 * time.Second,
+				// 2^12 секунд больше часа — дальше, в том числе там, где сдвиг переполнял бы
+				// time.Duration (54–69 и 83–99 неудач), задержка равна lockout
+				32: time.Hour, 54: time.Hour, 69: time.Hour, 83: time.Hour, 99: time.Hour, 100: time.Hour,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.policy
+			prev := time.Duration(0)
+			for failures := 0; failures <= p.lockAfter+1; failures++ {
+				d := p.delay(failures)
+				msg := fmt.Sprintf("%d failures", failures)
+				if want, ok := tt.want[failures]; ok {
+					assert.Equal(t, want, d, msg)
+				}
+				assert.GreaterOrEqual(t, d, prev, msg)
+				assert.LessOrEqual(t, d, p.lockout, msg)
+				if failures >= p.lockAfter {
+					assert.Equal(t, p.lockout, d, msg)
+				}
+				prev = d
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+func (s *Server) GetProfileHandler(w http.ResponseWriter, r *http.Request) {
+
+	claims := GetUserFromContext(r.Context())
+	if claims == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	user, err := s.db.GetUserByID(r.Context(), claims.UserID)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error retrieving user")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "profile retrieved successfully",
+		Data:    user,
+	})
+}
+
+func (s *Server) UpdateProfileHandler(w http.ResponseWriter, r *http.Request) {
+
+	claims := GetUserFromContext(r.Context())
+	if claims == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "request reading error")
+		return
+	}
+
+	settings := models.UserSettings{}
+	if err := json.Unmarshal(body, &settings); err != nil {
+		JsonError(w, http.StatusBadRequest, "invalid json format")
+		return
+	}
+
+	if settings.BaseCurrency != nil {
+		currency := strings.ToUpper(strings.TrimSpace(*settings.BaseCurrency))
+		if !models.IsValidCurrency(currency) {
+			JsonError(w, http.StatusBadRequest, "base_currency must be a 3-letter ISO 4217 code")
+			return
+		}
+		settings.BaseCurrency = &currency
+	}
+
+	if settings.TimeZone != nil {
+		timeZone := strings.TrimSpace(*settings.TimeZone)
+		if !models.IsValidTimeZone(timeZone) {
+			JsonError(w, http.StatusBadRequest, "time_zone must be an IANA time zone name")
+			return
+		}
+		settings.TimeZone = &timeZone
+	}
+
+	user, err := s.db.UpdateUserSettings(r.Context(), claims.UserID, &settings)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error updating profile")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "profile updated successfully",
+		Data:    user,
+	})
+}
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+)
+
+func (s *Server) AddRecurringRuleHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "request reading error")
+		return
+	}
+
+	rule := models.RecurringRule{}
+	err = json.Unmarshal(body, &rule)
+	if msg, ok := invalidValue(err); ok {
+		JsonError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, "invalid json format")
+		return
+	}
+
+	if rule.Amount <= 0 {
+		JsonError(w, http.StatusBadRequest, "amount must be greater than 0")
+		return
+	}
+	if rule.Amount > models.MaxAmount {
+		JsonError(w, http.StatusBadRequest, fmt.Sprintf("amount must not exceed %s", models.MaxAmount))
+		return
+	}
+
+	rule.Currency = strings.ToUpper(strings.TrimSpace(rule.Currency))
+	if rule.Currency != "" && !models.IsValidCurrency(rule.Currency) {
+		JsonError(w, http.StatusBadRequest, "currency must be a 3-letter ISO 4217 code")
+		return
+	}
+
+	if rule.CategoryID <= 0 {
+		JsonError(w, http.StatusBadRequest, "category_id is required")
+		return
+	}
+
+	switch rule.Frequency {
+	case models.FrequencyDaily, models.FrequencyWeekly:
+		if rule.DayOfMonth != nil {
+			JsonError(w, http.StatusBadRequest, "day_of_month is only supported for monthly and yearly rules")
+			return
+		}
+	case models.FrequencyMonthly, models.FrequencyYearly:
+		if rule.DayOfMonth != nil && (*rule.DayOfMonth < 1 || *rule.DayOfMonth > 31) {
+			JsonError(w, http.StatusBadRequest, "day_of_month must be between 1 and 31")
+			return
+		}
+	default:
+		JsonError(w, http.StatusBadRequest, "frequency must be one of 'daily', 'weekly', 'monthly', 'yearly'")
+		return
+	}
+
+	if rule.Interval < 0 {
+		JsonError(w, http.StatusBadRequest, "interval must be greater than 0")
+		return
+	}
+	if rule.MaxOccurrences != nil && *rule.MaxOccurrences <= 0 {
+		JsonError(w, http.StatusBadRequest, "max_occurrences must be greater than 0")
+		return
+	}
+
+	if rule.StartDate.IsZero() {
+		rule.StartDate = models.NewDate(time.Now().UTC())
+	}
+	if rule.EndDate != nil && rule.EndDate.Before(rule.StartDate.Time) {
+		JsonError(w, http.StatusBadRequest, "end_date must not be before start_date")
+		return
+	}
+
+	rule.Occurrences = 0
+	rule.Normalize()
+
+	ctx := r.Context()
+
+	exists, err := s.db.CheckCategory(ctx, user.UserID, rule.CategoryID)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "database error during category check")
+		return
+	}
+	if !exists {
+		JsonError(w, http.StatusBadRequest, "category does not exist or access denied")
+		return
+	}
+
+	rule, err = s.db.AddRecurringRule(ctx, user.UserID, &rule)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error adding recurring rule")
+		return
+	}
+
+	JsonResponse(w, http.StatusCreated, models.SuccessResponse{
+		Message: "recurring rule added successfully",
+		Data:    rule,
+	})
+}
+
+func (s *Server) GetRecurringRulesHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	rules, err := s.db.GetRecurringRules(r.Context(), user.UserID)
+	if err != nil {
+		log.Printf("error retrieving recurring rules: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error retrieving recurring rules")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "recurring rules listed successfully",
+		Data:    rules,
+	})
+}
+
+func (s *Server) UpdateRecurringRuleHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "request reading error")
+		return
+	}
+
+	update := models.RecurringRuleUpdate{}
+	err = json.Unmarshal(body, &update)
+	if msg, ok := invalidValue(err); ok {
+		JsonError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, "invalid json format")
+		return
+	}
+
+	if update.Amount == nil && update.CategoryID == nil && update.Note == nil &&
+		update.EndDate == nil && update.MaxOccurrences == nil {
+		JsonError(w, http.StatusBadRequest, "no fields to update")
+		return
+	}
+	if update.Amount != nil && (*update.Amount <= 0 || *update.Amount > models.MaxAmount) {
+		JsonError(w, http.StatusBadRequest, fmt.Sprintf("amount must be greater than 0 and not exceed %s", models.MaxAmount))
+		return
+	}
+	if update.MaxOccurrences != nil && *update.MaxOccurrences <= 0 {
+		JsonError(w, http.StatusBadRequest, "max_occurrences must be greater than 0")
+		return
+	}
+
+	ctx := r.Context()
+
+	if update.CategoryID != nil {
+		exists, err := s.db.CheckCategory(ctx, user.UserID, *update.CategoryID)
+		if err != nil {
+			JsonError(w, http.StatusInternalServerError, "database error during category check")
+			return
+		}
+		if !exists {
+			JsonError(w, http.StatusBadRequest, "category does not exist or access denied")
+			return
+		}
+	}
+
+	rule, err := s.db.UpdateRecurringRule(ctx, user.UserID, id, &update)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("recurring rule with id %d not found or access denied", id))
+		return
+	}
+	if errors.Is(err, db.ErrEndBeforeStart) {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("failed to update recurring rule: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error updating recurring rule")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("recurring rule with id: %d successfully updated", id),
+		Data:    rule,
+	})
+}
+
+func (s *Server) DeleteRecurringRuleHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = s.db.DeleteRecurringRule(r.Context(), user.UserID, id)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("recurring rule with id %d not found or access denied", id))
+		return
+	}
+	if err != nil {
+		log.Printf("failed to delete recurring rule: %v", err)
+		JsonError(w, http.StatusInternalServerError, "failed to delete recurring rule")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("recurring rule with id: %d successfully deleted", id),
+	})
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+// CategoryReportHandler возвращает доходы и расходы по категориям с долями от итогов.
+// Принимает те же фильтры, что и GET /transactions.
+func (s *Server) CategoryReportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	filter, err := parseTransactionFilter(r.URL.Query())
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := s.db.GetCategoryReport(r.Context(), user.UserID, filter)
+	if err != nil {
+		log.Printf("error retrieving category report: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error retrieving category report")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "category report retrieved successfully",
+		Data:    report,
+	})
+}
+
+// TimeSeriesHandler возвращает доходы и расходы по дням, неделям, месяцам или годам
+// за период from–to; периоды без транзакций заполнены нулями
+func (s *Server) TimeSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	q := r.URL.Query()
+	filter, err := parseTransactionFilter(q)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.From == nil || filter.To == nil {
+		JsonError(w, http.StatusBadRequest, "from and to parameters are required")
+		return
+	}
+	if filter.To.Before(*filter.From) {
+		JsonError(w, http.StatusBadRequest, "to date must be after from date")
+		return
+	}
+
+	interval := strings.ToLower(strings.TrimSpace(q.Get("interval")))
+	if interval == "" {
+		interval = models.IntervalMonth
+	}
+	if !models.IsValidInterval(interval) {
+		JsonError(w, http.StatusBadRequest, "interval must be one of 'day', 'week', 'month', 'year'")
+		return
+	}
+	if models.TimeSeriesLength(interval, *filter.From, *filter.To) > models.MaxTimeSeriesPoints {
+		JsonError(w, http.StatusBadRequest,
+			fmt.Sprintf("date range is too long for interval '%s': at most %d points", interval, models.MaxTimeSeriesPoints))
+		return
+	}
+
+	series, err := s.db.GetTimeSeries(r.Context(), user.UserID, filter, interval)
+	if err != nil {
+		log.Printf("error retrieving time series: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error retrieving time series")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "time series retrieved successfully",
+		Data:    series,
+	})
+}
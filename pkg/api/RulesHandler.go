@@ -0,0 +1,289 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+)
+
+// ruleApplyPageSize — по сколько транзакций читается история при прогоне правил
+const ruleApplyPageSize = 500
+
+func (s *Server) AddRuleHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	rule, ok := s.readRule(w, r, user.UserID)
+	if !ok {
+		return
+	}
+
+	rule, err := s.db.AddCategoryRule(r.Context(), user.UserID, &rule)
+	if err != nil {
+		log.Printf("failed to add rule: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error adding rule")
+		return
+	}
+
+	JsonResponse(w, http.StatusCreated, models.SuccessResponse{
+		Message: "rule added successfully",
+		Data:    rule,
+	})
+}
+
+func (s *Server) GetRulesHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	rules, err := s.db.GetCategoryRules(r.Context(), user.UserID)
+	if err != nil {
+		log.Printf("error retrieving rules: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error retrieving rules")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "rules listed successfully",
+		Data:    rules,
+	})
+}
+
+// UpdateRuleHandler заменяет правило целиком: неуказанные условия снимаются
+func (s *Server) UpdateRuleHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rule, ok := s.readRule(w, r, user.UserID)
+	if !ok {
+		return
+	}
+
+	rule, err = s.db.UpdateCategoryRule(r.Context(), user.UserID, id, &rule)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("rule with id %d not found or access denied", id))
+		return
+	}
+	if err != nil {
+		log.Printf("failed to update rule: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error updating rule")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("rule with id: %d successfully updated", id),
+		Data:    rule,
+	})
+}
+
+func (s *Server) DeleteRuleHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = s.db.DeleteCategoryRule(r.Context(), user.UserID, id)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("rule with id %d not found or access denied", id))
+		return
+	}
+	if err != nil {
+		log.Printf("failed to delete rule: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error deleting rule")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("rule with id: %d successfully deleted", id),
+	})
+}
+
+// ApplyRulesHandler прогоняет правила по существующим транзакциям (фильтры те же, что
+// у GET /transactions) и показывает, что изменится. С apply=true изменения сохраняются.
+// Переводы и транзакции с разбивкой не затрагиваются.
+func (s *Server) ApplyRulesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	q := r.URL.Query()
+	filter, err := parseTransactionFilter(q)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	apply := false
+	if value := q.Get("apply"); value != "" {
+		if apply, err = strconv.ParseBool(value); err != nil {
+			JsonError(w, http.StatusBadRequest, "invalid apply parameter")
+			return
+		}
+	}
+
+	ctx := r.Context()
+
+	rules, err := s.db.GetCategoryRules(ctx, user.UserID)
+	if err != nil {
+		log.Printf("error retrieving rules: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error retrieving rules")
+		return
+	}
+
+	result := models.RuleApplyResult{DryRun: !apply, Changes: []models.RuleMatch{}}
+	for offset := 0; len(rules) > 0; offset += ruleApplyPageSize {
+		transactions, err := s.db.GetTransactions(ctx, user.UserID, filter, ruleApplyPageSize, offset)
+		if err != nil {
+			log.Printf("error retrieving transactions: %v", err)
+			JsonError(w, http.StatusInternalServerError, "error retrieving transactions")
+			return
+		}
+		for _, t := range transactions {
+			result.Scanned++
+			if match, ok := ruleChange(rules, t); ok {
+				result.Changes = append(result.Changes, match)
+			}
+		}
+		if len(transactions) < ruleApplyPageSize {
+			break
+		}
+	}
+
+	message := "rules dry run"
+	if apply {
+		message = "rules applied"
+		if len(result.Changes) > 0 {
+			result.Applied, err = s.db.ApplyRuleMatches(ctx, user.UserID, result.Changes)
+			if err != nil {
+				log.Printf("failed to apply rules: %v", err)
+				JsonError(w, http.StatusInternalServerError, "error applying rules")
+				return
+			}
+		}
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: message,
+		Data:    result,
+	})
+}
+
+// ruleChange применяет к копии транзакции первое подходящее правило и возвращает
+// изменение, если правило что-то меняет
+func ruleChange(rules []models.CategoryRule, t *models.Transaction) (models.RuleMatch, bool) {
+	if t.TransferID != nil || len(t.Splits) > 0 {
+		return models.RuleMatch{}, false
+	}
+	rule := models.MatchRule(rules, t)
+	if rule == nil {
+		return models.RuleMatch{}, false
+	}
+	changed := *t
+	if err := models.ApplyRule(rule, &changed); err != nil {
+		return models.RuleMatch{}, false
+	}
+
+	existing := make(map[string]bool, len(t.Tags))
+	for _, tag := range t.Tags {
+		existing[tag] = true
+	}
+	var added []string
+	for _, tag := range changed.Tags {
+		if !existing[tag] {
+			added = append(added, tag)
+		}
+	}
+	if changed.CategoryID == t.CategoryID && len(added) == 0 {
+		return models.RuleMatch{}, false
+	}
+	return models.RuleMatch{
+		TransactionID: t.ID,
+		RuleID:        rule.ID,
+		Note:          t.Note,
+		OldCategoryID: t.CategoryID,
+		CategoryID:    changed.CategoryID,
+		AddedTags:     added,
+		Tags:          changed.Tags,
+	}, true
+}
+
+// readRule разбирает и проверяет правило из тела запроса; при ошибке ответ уже записан
+func (s *Server) readRule(w http.ResponseWriter, r *http.Request, userID int) (models.CategoryRule, bool) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "request reading error")
+		return models.CategoryRule{}, false
+	}
+
+	rule := models.CategoryRule{}
+	err = json.Unmarshal(body, &rule)
+	if msg, ok := invalidValue(err); ok {
+		JsonError(w, http.StatusBadRequest, msg)
+		return models.CategoryRule{}, false
+	}
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, "invalid json format")
+		return models.CategoryRule{}, false
+	}
+
+	if err := rule.Normalize(); err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return models.CategoryRule{}, false
+	}
+
+	if rule.CategoryID != nil {
+		exists, err := s.db.CheckCategory(r.Context(), userID, *rule.CategoryID)
+		if err != nil {
+			JsonError(w, http.StatusInternalServerError, "database error during category check")
+			return models.CategoryRule{}, false
+		}
+		if !exists {
+			JsonError(w, http.StatusBadRequest, "category does not exist or access denied")
+			return models.CategoryRule{}, false
+		}
+	}
+	if rule.AccountID != nil {
+		if _, ok := s.userAccount(w, r, userID, *rule.AccountID); !ok {
+			return models.CategoryRule{}, false
+		}
+	}
+	return rule, true
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+)
+
+func (s *Server) GetTagsHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	tags, err := s.db.GetTags(r.Context(), user.UserID)
+	if err != nil {
+		log.Printf("error retrieving tags: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error retrieving tags")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "tags listed successfully",
+		Data:    tags,
+	})
+}
+
+func (s *Server) DeleteTagHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := pathID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = s.db.DeleteTag(r.Context(), user.UserID, id)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("tag with id %d not found or access denied", id))
+		return
+	}
+	if err != nil {
+		log.Printf("failed to delete tag: %v", err)
+		JsonError(w, http.StatusInternalServerError, "failed to delete tag")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("tag with id: %d successfully deleted", id),
+	})
+}
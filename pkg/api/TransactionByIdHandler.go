@@ -4,8 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
-	"strings"
 
 	models "github.com/ViktorOHJ/expense-tracker/pkg"
 	"github.com/ViktorOHJ/expense-tracker/pkg/db"
@@ -19,15 +17,9 @@ func (s *Server) TransactionByIdHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	idStr := strings.TrimSpace(r.URL.Query().Get("id"))
-	if idStr == "" {
-		JsonError(w, http.StatusBadRequest, "id cannot be empty")
-		return
-	}
-
-	id, err := strconv.Atoi(idStr)
-	if err != nil || id <= 0 {
-		JsonError(w, http.StatusBadRequest, "id must be a positive number")
+	id, err := transactionID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -0,0 +1,405 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+)
+
+const qrCodeSize = 256
+
+var errInvalidCode = errors.New("invalid two-factor code")
+
+// TwoFactorSetupHandler начинает настройку 2FA: выдаёт новый секрет и otpauth:// URI.
+// Коды при входе требуются только после подтверждения через /auth/2fa/enable.
+func (s *Server) TwoFactorSetupHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	claims := GetUserFromContext(r.Context())
+	if claims == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		log.Printf("failed to set up two-factor authentication: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error setting up two-factor authentication")
+		return
+	}
+	err = s.db.SetTOTPSecret(r.Context(), claims.UserID, secret)
+	if errors.Is(err, db.ErrAlreadyExists) {
+		JsonError(w, http.StatusConflict, "two-factor authentication is already enabled")
+		return
+	}
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error setting up two-factor authentication")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "scan the QR code or enter the secret in your authenticator app, then confirm with a code",
+		Data: models.TwoFactorSetup{
+			Secret:     secret,
+			OTPAuthURL: auth.TOTPURI(claims.Email, secret),
+		},
+	})
+}
+
+// TwoFactorQRHandler отдаёт QR-код неподтверждённой настройки в PNG. После включения
+// 2FA секрет больше не показывается.
+func (s *Server) TwoFactorQRHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	claims := GetUserFromContext(r.Context())
+	if claims == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	tf, err := s.db.GetTwoFactor(r.Context(), claims.UserID)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error retrieving two-factor settings")
+		return
+	}
+	if tf.Secret == "" || tf.Enabled {
+		JsonError(w, http.StatusNotFound, "no pending two-factor setup")
+		return
+	}
+
+	png, err := auth.TOTPQRCode(auth.TOTPURI(claims.Email, tf.Secret), qrCodeSize)
+	if err != nil {
+		log.Printf("failed to render QR code: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error rendering QR code")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(png)
+}
+
+// TwoFactorEnableHandler включает 2FA по первому коду из приложения и выдаёт коды
+// восстановления — они показываются один раз
+func (s *Server) TwoFactorEnableHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	claims := GetUserFromContext(r.Context())
+	if claims == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	tf, err := s.db.GetTwoFactor(r.Context(), claims.UserID)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error retrieving two-factor settings")
+		return
+	}
+	if tf.Enabled {
+		JsonError(w, http.StatusConflict, "two-factor authentication is already enabled")
+		return
+	}
+	if tf.Secret == "" {
+		JsonError(w, http.StatusBadRequest, "two-factor setup has not been started")
+		return
+	}
+
+	step, ok := auth.ValidateTOTP(tf.Secret, req.Code, time.Now())
+	if !ok {
+		JsonError(w, http.StatusBadRequest, "invalid two-factor code")
+		return
+	}
+
+	codes, hashes, err := auth.GenerateRecoveryCodes()
+	if err != nil {
+		log.Printf("failed to enable two-factor authentication: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error enabling two-factor authentication")
+		return
+	}
+	err = s.db.EnableTOTP(r.Context(), claims.UserID, step, hashes)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusConflict, "two-factor authentication is already enabled")
+		return
+	}
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error enabling two-factor authentication")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "two-factor authentication enabled, store the recovery codes in a safe place",
+		Data:    models.RecoveryCodes{RecoveryCodes: codes},
+	})
+}
+
+// TwoFactorDisableHandler отключает 2FA; нужны пароль и код из приложения или код восстановления
+func (s *Server) TwoFactorDisableHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	claims := GetUserFromContext(r.Context())
+	if claims == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req models.TwoFactorDisableRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	// Пароль и код подбираются здесь не быстрее, чем при входе
+	email, ip := normalizeEmail(claims.Email), s.clientIP(r)
+	if !s.checkLoginThrottle(w, r, &claims.UserID, email, ip) {
+		return
+	}
+
+	user, err := s.db.GetUserByID(r.Context(), claims.UserID)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error retrieving user")
+		return
+	}
+	if !s.passwordService.CheckPassword(user.Password, req.Password) {
+		s.recordLoginAttempt(r.Context(), &claims.UserID, email, ip, models.LoginInvalidCredentials)
+		JsonError(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+
+	tf, err := s.db.GetTwoFactor(r.Context(), claims.UserID)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error retrieving two-factor settings")
+		return
+	}
+	if !tf.Enabled {
+		JsonError(w, http.StatusBadRequest, "two-factor authentication is not enabled")
+		return
+	}
+	if !s.checkSecondFactor(w, r, claims.UserID, email, ip, tf.Secret, req.Code) {
+		return
+	}
+
+	if err := s.db.DisableTOTP(r.Context(), claims.UserID); err != nil {
+		JsonError(w, http.StatusInternalServerError, "error disabling two-factor authentication")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "two-factor authentication disabled",
+	})
+}
+
+// RecoveryCodesHandler заменяет коды восстановления новыми по коду из приложения
+func (s *Server) RecoveryCodesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	claims := GetUserFromContext(r.Context())
+	if claims == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	tf, err := s.db.GetTwoFactor(r.Context(), claims.UserID)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error retrieving two-factor settings")
+		return
+	}
+	if !tf.Enabled {
+		JsonError(w, http.StatusBadRequest, "two-factor authentication is not enabled")
+		return
+	}
+	email, ip := normalizeEmail(claims.Email), s.clientIP(r)
+	if !s.checkLoginThrottle(w, r, &claims.UserID, email, ip) {
+		return
+	}
+	if !s.checkSecondFactor(w, r, claims.UserID, email, ip, tf.Secret, req.Code) {
+		return
+	}
+
+	codes, hashes, err := auth.GenerateRecoveryCodes()
+	if err != nil {
+		log.Printf("failed to generate recovery codes: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error generating recovery codes")
+		return
+	}
+	if err := s.db.ReplaceRecoveryCodes(r.Context(), claims.UserID, hashes); err != nil {
+		JsonError(w, http.StatusInternalServerError, "error generating recovery codes")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "recovery codes regenerated, previous codes no longer work",
+		Data:    models.RecoveryCodes{RecoveryCodes: codes},
+	})
+}
+
+// TwoFactorVerifyHandler — второй шаг входа: обменивает challenge-токен и код на сессию
+func (s *Server) TwoFactorVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req models.TwoFactorVerifyRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	claims, err := s.jwtService.ValidateChallengeToken(req.ChallengeToken)
+	if err != nil {
+		JsonError(w, http.StatusUnauthorized, "invalid challenge token")
+		return
+	}
+	used, err := s.db.IsTokenRevoked(r.Context(), claims.ID)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error checking token")
+		return
+	}
+	if used {
+		JsonError(w, http.StatusUnauthorized, "invalid challenge token")
+		return
+	}
+
+	tf, err := s.db.GetTwoFactor(r.Context(), claims.UserID)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error retrieving two-factor settings")
+		return
+	}
+	if !tf.Enabled {
+		JsonError(w, http.StatusUnauthorized, "invalid challenge token")
+		return
+	}
+
+	// Неверные коды считаются вместе с неверными паролями, поэтому подбирать код
+	// можно не быстрее, чем пароль
+	email, ip := normalizeEmail(claims.Email), s.clientIP(r)
+	if !s.checkLoginThrottle(w, r, &claims.UserID, email, ip) {
+		return
+	}
+	err = s.useSecondFactor(r.Context(), claims.UserID, tf.Secret, req.Code)
+	if errors.Is(err, errInvalidCode) {
+		s.recordLoginAttempt(r.Context(), &claims.UserID, email, ip, models.LoginInvalidCode)
+		JsonError(w, http.StatusUnauthorized, "invalid two-factor code")
+		return
+	}
+	if err != nil {
+		log.Printf("failed to check two-factor code: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error checking two-factor code")
+		return
+	}
+
+	// Challenge-токен одноразовый: его jti попадает в список отзыва
+	if err := s.db.RevokeSession(r.Context(), claims.UserID, claims.ID, claims.ExpiresAt.Time); err != nil {
+		log.Printf("failed to revoke challenge token: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error verifying code")
+		return
+	}
+
+	user, err := s.db.GetUserByID(r.Context(), claims.UserID)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error retrieving user")
+		return
+	}
+
+	response, err := s.startSession(r.Context(), user)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error generating token")
+		return
+	}
+	s.recordLoginAttempt(r.Context(), &claims.UserID, email, ip, models.LoginSucceeded)
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: "login successful",
+		Data:    response,
+	})
+}
+
+// checkSecondFactor принимает шестизначный код из приложения или код восстановления.
+// При неверном коде записывает неудачную попытку для ограничения подбора, сам
+// отвечает клиенту и возвращает false.
+func (s *Server) checkSecondFactor(w http.ResponseWriter, r *http.Request, userID int, email, ip, secret, code string) bool {
+	err := s.useSecondFactor(r.Context(), userID, secret, code)
+	if errors.Is(err, errInvalidCode) {
+		s.recordLoginAttempt(r.Context(), &userID, email, ip, models.LoginInvalidCode)
+		JsonError(w, http.StatusUnauthorized, "invalid two-factor code")
+		return false
+	}
+	if err != nil {
+		log.Printf("failed to check two-factor code: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error checking two-factor code")
+		return false
+	}
+	return true
+}
+
+func (s *Server) useSecondFactor(ctx context.Context, userID int, secret, code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return errInvalidCode
+	}
+
+	if isTOTPCode(code) {
+		step, ok := auth.ValidateTOTP(secret, code, time.Now())
+		if !ok {
+			return errInvalidCode
+		}
+		// Код одного шага принимается один раз, даже если он ещё действует
+		err := s.db.UseTOTPStep(ctx, userID, step)
+		if errors.Is(err, db.ErrTokenReused) {
+			return errInvalidCode
+		}
+		return err
+	}
+
+	err := s.db.UseRecoveryCode(ctx, userID, auth.HashRecoveryCode(code))
+	if errors.Is(err, db.ErrNotFound) {
+		return errInvalidCode
+	}
+	if err == nil {
+		log.Printf("Recovery code used by user %d", userID)
+	}
+	return err
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
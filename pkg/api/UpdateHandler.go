@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+)
+
+func (s *Server) UpdateHandler(w http.ResponseWriter, r *http.Request) {
+
+	user := GetUserFromContext(r.Context())
+	if user == nil {
+		JsonError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := transactionID(r)
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "request reading error")
+		return
+	}
+
+	update := models.TransactionUpdate{}
+	err = json.Unmarshal(body, &update)
+	if msg, ok := invalidValue(err); ok {
+		JsonError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if err != nil {
+		JsonError(w, http.StatusBadRequest, "invalid json format")
+		return
+	}
+
+	// Старые клиенты меняли дату операции через created_at
+	if update.OccurredAt == nil && update.CreatedAt != nil {
+		update.OccurredAt = &models.DateTime{Time: *update.CreatedAt}
+	}
+
+	if update.Amount != nil && *update.Amount <= 0 {
+		JsonError(w, http.StatusBadRequest, "amount must be greater than 0")
+		return
+	}
+
+	if update.Amount != nil && *update.Amount > models.MaxAmount {
+		JsonError(w, http.StatusBadRequest, fmt.Sprintf("amount must not exceed %s", models.MaxAmount))
+		return
+	}
+
+	if update.Currency != nil {
+		currency := strings.ToUpper(strings.TrimSpace(*update.Currency))
+		if !models.IsValidCurrency(currency) {
+			JsonError(w, http.StatusBadRequest, "currency must be a 3-letter ISO 4217 code")
+			return
+		}
+		update.Currency = &currency
+	}
+
+	if update.Tags != nil {
+		tags, err := models.NormalizeTags(*update.Tags)
+		if err != nil {
+			JsonError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		update.Tags = &tags
+	}
+
+	// Пустой список убирает разбивку; сверка с суммой — в БД, если сумма не меняется
+	if update.Splits != nil && len(*update.Splits) > 0 {
+		if update.Amount != nil {
+			err = models.ValidateSplits(*update.Amount, *update.Splits)
+		} else {
+			err = models.ValidateSplitLines(*update.Splits)
+		}
+		if err != nil {
+			JsonError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	ctx := r.Context()
+
+	if update.CategoryID != nil {
+		exists, err := s.db.CheckCategory(ctx, user.UserID, *update.CategoryID)
+		if err != nil {
+			JsonError(w, http.StatusInternalServerError, "database error during category check")
+			return
+		}
+		if !exists {
+			JsonError(w, http.StatusBadRequest, "category does not exist or access denied")
+			return
+		}
+	}
+
+	if update.Splits != nil && !s.checkSplitCategories(w, r, user.UserID, *update.Splits) {
+		return
+	}
+
+	if update.AccountID != nil && *update.AccountID != 0 {
+		if _, ok := s.userAccount(w, r, user.UserID, *update.AccountID); !ok {
+			return
+		}
+	}
+
+	transaction, err := s.db.UpdateTransaction(ctx, user.UserID, id, &update)
+	if errors.Is(err, db.ErrNotFound) {
+		JsonError(w, http.StatusNotFound, fmt.Sprintf("transaction with id %d not found or access denied", id))
+		return
+	}
+	if errors.Is(err, db.ErrTransferTransaction) {
+		JsonError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, db.ErrCurrencyMismatch) || errors.Is(err, db.ErrSplitMismatch) {
+		JsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("failed to update transaction: %v", err)
+		JsonError(w, http.StatusInternalServerError, "error updating transaction")
+		return
+	}
+
+	JsonResponse(w, http.StatusOK, models.SuccessResponse{
+		Message: fmt.Sprintf("transaction with id: %d successfully updated", id),
+		Data:    transaction,
+	})
+}
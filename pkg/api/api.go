@@ -2,26 +2,73 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	models "github.com/ViktorOHJ/expense-tracker/pkg"
 	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
 	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mail"
+	"github.com/ViktorOHJ/expense-tracker/pkg/storage"
 )
 
 type Server struct {
 	db              db.DB
 	jwtService      *auth.JWTService
 	passwordService *auth.PasswordService
+	blobs           storage.BlobStore
+	mailer          mail.Mailer
+	appURL          string
+	trustProxy      bool
 }
 
-func NewServer(db db.DB, jwtService *auth.JWTService, passwordService *auth.PasswordService) *Server {
-	return &Server{
+// Option настраивает необязательные зависимости сервера
+type Option func(*Server)
+
+// WithBlobStore задаёт хранилище файлов вложений; без него вложения недоступны
+func WithBlobStore(blobs storage.BlobStore) Option {
+	return func(s *Server) {
+		s.blobs = blobs
+	}
+}
+
+// WithMailer задаёт отправку писем; без него письма только пишутся в лог
+func WithMailer(mailer mail.Mailer) Option {
+	return func(s *Server) {
+		s.mailer = mailer
+	}
+}
+
+// WithAppURL задаёт адрес клиентского приложения для ссылок в письмах,
+// например https://app.example.com; без него в письмах только сам токен
+func WithAppURL(appURL string) Option {
+	return func(s *Server) {
+		s.appURL = strings.TrimRight(appURL, "/")
+	}
+}
+
+// WithTrustProxy включает чтение адреса клиента из X-Forwarded-For; только для
+// запуска за обратным прокси, иначе клиент может подставить любой адрес
+func WithTrustProxy(trust bool) Option {
+	return func(s *Server) {
+		s.trustProxy = trust
+	}
+}
+
+func NewServer(db db.DB, jwtService *auth.JWTService, passwordService *auth.PasswordService, opts ...Option) *Server {
+	s := &Server{
 		db:              db,
 		jwtService:      jwtService,
 		passwordService: passwordService,
+		mailer:          mail.LogMailer{},
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *Server) InitRoutes() http.Handler {
@@ -30,12 +77,51 @@ func (s *Server) InitRoutes() http.Handler {
 	// Публичные маршруты
 	mux.HandleFunc("/auth/register", s.RegisterHandler)
 	mux.HandleFunc("/auth/login", s.LoginHandler)
+	mux.HandleFunc("/auth/refresh", s.RefreshHandler)
+	mux.HandleFunc("/auth/verify-email", s.VerifyEmailHandler)
+	mux.HandleFunc("/auth/resend-verification", s.ResendVerificationHandler)
+	mux.HandleFunc("/auth/forgot-password", s.ForgotPasswordHandler)
+	mux.HandleFunc("/auth/reset-password", s.ResetPasswordHandler)
+	mux.HandleFunc("/auth/2fa/verify", s.TwoFactorVerifyHandler)
+	mux.HandleFunc("/.well-known/jwks.json", s.JWKSHandler)
 
 	// Защищенные маршруты
-	mux.HandleFunc("/transactions", s.AuthMiddleware(s.TransactionHandler))
-	mux.HandleFunc("/transaction/", s.AuthMiddleware(s.DeleteGetHandler))
-	mux.HandleFunc("/categories", s.AuthMiddleware(s.CategoriesHandler))
-	mux.HandleFunc("/summary", s.AuthMiddleware(s.SummaryHandler))
+	mux.HandleFunc("/auth/logout", s.AuthMiddleware(s.LogoutHandler))
+	mux.HandleFunc("/auth/2fa/setup", s.AuthMiddleware(s.TwoFactorSetupHandler))
+	mux.HandleFunc("/auth/2fa/qr", s.AuthMiddleware(s.TwoFactorQRHandler))
+	mux.HandleFunc("/auth/2fa/enable", s.AuthMiddleware(s.TwoFactorEnableHandler))
+	mux.HandleFunc("/auth/2fa/disable", s.AuthMiddleware(s.TwoFactorDisableHandler))
+	mux.HandleFunc("/auth/2fa/recovery-codes", s.AuthMiddleware(s.RecoveryCodesHandler))
+	mux.HandleFunc("/auth/login-attempts", s.AuthMiddleware(s.LoginAttemptsHandler))
+	mux.HandleFunc("/api-keys", s.AuthMiddleware(s.APIKeysHandler))
+	mux.HandleFunc("/api-keys/{id}", s.AuthMiddleware(s.APIKeyHandler))
+
+	// Маршруты данных доступны и по API-ключу с нужной областью доступа
+	mux.HandleFunc("/transactions", s.ScopeMiddleware("transactions", s.TransactionHandler))
+	mux.HandleFunc("/transactions/export", s.ScopeMiddleware("transactions", s.ExportHandler))
+	mux.HandleFunc("/transaction/", s.ScopeMiddleware("transactions", s.DeleteGetHandler))
+	mux.HandleFunc("/transaction/{id}", s.ScopeMiddleware("transactions", s.DeleteGetHandler))
+	mux.HandleFunc("/transaction/{id}/attachments", s.ScopeMiddleware("transactions", s.AttachmentsHandler))
+	mux.HandleFunc("/transaction/{id}/attachments/{attachmentID}", s.ScopeMiddleware("transactions", s.AttachmentHandler))
+	mux.HandleFunc("/categories", s.ScopeMiddleware("categories", s.CategoriesHandler))
+	mux.HandleFunc("/categories/{id}", s.ScopeMiddleware("categories", s.CategoryHandler))
+	mux.HandleFunc("/summary", s.ScopeMiddleware("reports", s.SummaryHandler))
+	mux.HandleFunc("/reports/categories", s.ScopeMiddleware("reports", s.CategoryReportHandler))
+	mux.HandleFunc("/reports/timeseries", s.ScopeMiddleware("reports", s.TimeSeriesHandler))
+	mux.HandleFunc("/profile", s.ScopeMiddleware("profile", s.ProfileHandler))
+	mux.HandleFunc("/budgets", s.ScopeMiddleware("budgets", s.BudgetsHandler))
+	mux.HandleFunc("/budgets/{id}", s.ScopeMiddleware("budgets", s.BudgetHandler))
+	mux.HandleFunc("/recurring", s.ScopeMiddleware("recurring", s.RecurringRulesHandler))
+	mux.HandleFunc("/recurring/{id}", s.ScopeMiddleware("recurring", s.RecurringRuleHandler))
+	mux.HandleFunc("/import/csv", s.ScopeMiddleware("transactions", s.ImportCSVHandler))
+	mux.HandleFunc("/accounts", s.ScopeMiddleware("accounts", s.AccountsHandler))
+	mux.HandleFunc("/accounts/{id}", s.ScopeMiddleware("accounts", s.AccountHandler))
+	mux.HandleFunc("/transfers", s.ScopeMiddleware("transactions", s.AddTransferHandler))
+	mux.HandleFunc("/tags", s.ScopeMiddleware("tags", s.TagsHandler))
+	mux.HandleFunc("/tags/{id}", s.ScopeMiddleware("tags", s.TagHandler))
+	mux.HandleFunc("/rules", s.ScopeMiddleware("rules", s.RulesHandler))
+	mux.HandleFunc("/rules/{id}", s.ScopeMiddleware("rules", s.RuleHandler))
+	mux.HandleFunc("/rules/apply", s.ScopeMiddleware("rules", s.ApplyRulesHandler))
 
 	return mux
 }
@@ -44,6 +130,8 @@ func (s *Server) DeleteGetHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
 		s.TransactionByIdHandler(w, r)
+	case http.MethodPut, http.MethodPatch:
+		s.UpdateHandler(w, r)
 	case http.MethodDelete:
 		s.DeleteHandler(w, r)
 	default:
@@ -62,6 +150,215 @@ func (s *Server) TransactionHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *Server) AttachmentsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.UploadAttachmentHandler(w, r)
+	case http.MethodGet:
+		s.GetAttachmentsHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) AttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.DownloadAttachmentHandler(w, r)
+	case http.MethodDelete:
+		s.DeleteAttachmentHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) RulesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.AddRuleHandler(w, r)
+	case http.MethodGet:
+		s.GetRulesHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) RuleHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		s.UpdateRuleHandler(w, r)
+	case http.MethodDelete:
+		s.DeleteRuleHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) CategoriesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.AddCategoryHandler(w, r)
+	case http.MethodGet:
+		s.GetCategoriesHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) CategoryHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.CategoryByIdHandler(w, r)
+	case http.MethodPatch:
+		s.UpdateCategoryHandler(w, r)
+	case http.MethodDelete:
+		s.DeleteCategoryHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) ProfileHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.GetProfileHandler(w, r)
+	case http.MethodPatch:
+		s.UpdateProfileHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) BudgetsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.AddBudgetHandler(w, r)
+	case http.MethodGet:
+		s.GetBudgetsHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) BudgetHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPatch:
+		s.UpdateBudgetHandler(w, r)
+	case http.MethodDelete:
+		s.DeleteBudgetHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) RecurringRulesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.AddRecurringRuleHandler(w, r)
+	case http.MethodGet:
+		s.GetRecurringRulesHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) RecurringRuleHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPatch:
+		s.UpdateRecurringRuleHandler(w, r)
+	case http.MethodDelete:
+		s.DeleteRecurringRuleHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) AccountsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.AddAccountHandler(w, r)
+	case http.MethodGet:
+		s.GetAccountsHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) AccountHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPatch:
+		s.UpdateAccountHandler(w, r)
+	case http.MethodDelete:
+		s.DeleteAccountHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) TagsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.GetTagsHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) TagHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodDelete:
+		s.DeleteTagHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) APIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.GetAPIKeysHandler(w, r)
+	case http.MethodPost:
+		s.CreateAPIKeyHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) APIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodDelete:
+		s.DeleteAPIKeyHandler(w, r)
+	default:
+		JsonError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// pathID извлекает положительный числовой {id} из пути запроса
+func pathID(r *http.Request) (int, error) {
+	return parseID(r.PathValue("id"))
+}
+
+// transactionID читает id из пути /transaction/{id}, а у прежней формы
+// /transaction/?id= — из параметра запроса
+func transactionID(r *http.Request) (int, error) {
+	if idStr := r.PathValue("id"); idStr != "" {
+		return parseID(idStr)
+	}
+	return parseID(r.URL.Query().Get("id"))
+}
+
+func parseID(idStr string) (int, error) {
+	idStr = strings.TrimSpace(idStr)
+	if idStr == "" {
+		return 0, errors.New("id cannot be empty")
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		return 0, errors.New("id must be a positive number")
+	}
+	return id, nil
+}
+
 func JsonResponse(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	w.WriteHeader(status)
@@ -0,0 +1,179 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAPIKeyHandler(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService())
+
+	var stored *models.APIKey
+	mockDB.On("AddAPIKey", mock.Anything, 1, mock.MatchedBy(func(k *models.APIKey) bool {
+		stored = k
+		return k.Name == "sync script"
+	})).Return(func(ctx context.Context, userID int, k *models.APIKey) models.APIKey {
+		created := *k
+		created.ID, created.UserID = 5, userID
+		return created
+	}, nil)
+
+	body := `{"name": " sync script ", "scopes": ["transactions:read", "Categories:Write", "transactions:read"]}`
+	req := httptest.NewRequest(http.MethodPost, "/api-keys", bytes.NewReader([]byte(body)))
+	ctx := context.WithValue(req.Context(), api.UserContextKey, &auth.Claims{UserID: 1})
+	rr := httptest.NewRecorder()
+	s.APIKeysHandler(rr, req.WithContext(ctx))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var resp struct {
+		Data models.CreatedAPIKey `json:"data"`
+	}
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+
+	// Ключ виден только в ответе, в БД — хеш и открытое начало
+	assert.True(t, strings.HasPrefix(resp.Data.Key, "et_"))
+	assert.Equal(t, auth.HashToken(resp.Data.Key), stored.KeyHash)
+	assert.True(t, strings.HasPrefix(resp.Data.Key, stored.Prefix))
+	assert.Equal(t, []string{"transactions:read", "categories:write"}, stored.Scopes)
+	assert.Equal(t, 5, resp.Data.ID)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestCreateAPIKeyHandler_InvalidRequest(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService())
+
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	tests := map[string]string{
+		"no name":        `{"scopes": ["tags:read"]}`,
+		"no scopes":      `{"name": "ci"}`,
+		"unknown scope":  `{"name": "ci", "scopes": ["users:read"]}`,
+		"reports write":  `{"name": "ci", "scopes": ["reports:write"]}`,
+		"expired":        `{"name": "ci", "scopes": ["tags:read"], "expires_at": "` + past + `"}`,
+		"missing access": `{"name": "ci", "scopes": ["tags"]}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api-keys", bytes.NewReader([]byte(body)))
+			ctx := context.WithValue(req.Context(), api.UserContextKey, &auth.Claims{UserID: 1})
+			rr := httptest.NewRecorder()
+			s.APIKeysHandler(rr, req.WithContext(ctx))
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+	mockDB.AssertNotCalled(t, "AddAPIKey", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteAPIKeyHandler(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService())
+
+	mockDB.On("DeleteAPIKey", mock.Anything, 1, 5).Return(nil)
+	mockDB.On("DeleteAPIKey", mock.Anything, 1, 6).Return(db.ErrNotFound)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api-keys/{id}", s.APIKeyHandler)
+	ctx := context.WithValue(context.Background(), api.UserContextKey, &auth.Claims{UserID: 1})
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/api-keys/5", nil).WithContext(ctx))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/api-keys/6", nil).WithContext(ctx))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestScopeMiddleware_APIKey(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService())
+
+	mockDB.On("UseAPIKey", mock.Anything, auth.HashToken("et_reader")).
+		Return(models.APIKey{ID: 1, UserID: 7, Scopes: []string{"transactions:read"}}, nil)
+	mockDB.On("UseAPIKey", mock.Anything, auth.HashToken("et_writer")).
+		Return(models.APIKey{ID: 2, UserID: 7, Scopes: []string{"transactions:write"}}, nil)
+	mockDB.On("UseAPIKey", mock.Anything, auth.HashToken("et_old")).Return(models.APIKey{}, db.ErrTokenExpired)
+	mockDB.On("UseAPIKey", mock.Anything, auth.HashToken("et_unknown")).Return(models.APIKey{}, db.ErrNotFound)
+
+	var userID int
+	handler := s.ScopeMiddleware("transactions", func(w http.ResponseWriter, r *http.Request) {
+		userID = api.GetUserFromContext(r.Context()).UserID
+	})
+
+	tests := []struct {
+		name   string
+		key    string
+		method string
+		code   int
+	}{
+		{"read with read scope", "et_reader", http.MethodGet, http.StatusOK},
+		{"write with read scope", "et_reader", http.MethodPost, http.StatusForbidden},
+		{"read with write scope", "et_writer", http.MethodGet, http.StatusOK},
+		{"write with write scope", "et_writer", http.MethodDelete, http.StatusOK},
+		{"expired key", "et_old", http.MethodGet, http.StatusUnauthorized},
+		{"unknown key", "et_unknown", http.MethodGet, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID = 0
+			req := httptest.NewRequest(tt.method, "/transactions", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.key)
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			assert.Equal(t, tt.code, rr.Code)
+			if tt.code == http.StatusOK {
+				assert.Equal(t, 7, userID)
+			} else {
+				assert.Zero(t, userID)
+			}
+		})
+	}
+
+	// Ключ с доступом к транзакциям не открывает другие ресурсы
+	req := httptest.NewRequest(http.MethodGet, "/budgets", nil)
+	req.Header.Set("Authorization", "Bearer et_writer")
+	rr := httptest.NewRecorder()
+	s.ScopeMiddleware("budgets", func(w http.ResponseWriter, r *http.Request) {})(rr, req)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "budgets:read")
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAuthMiddleware_RejectsAPIKey(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService())
+
+	called := false
+	handler := s.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	// Ключом нельзя управлять ключами и аккаунтом
+	req := httptest.NewRequest(http.MethodPost, "/api-keys", nil)
+	req.Header.Set("Authorization", "Bearer et_anything")
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.False(t, called)
+	mockDB.AssertNotCalled(t, "UseAPIKey", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,189 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddAccountHandler_Success(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("AddAccount", mock.Anything, 1, mock.MatchedBy(func(a *models.Account) bool {
+		return a.Name == "Wallet" && a.Type == "cash" && a.Currency == "EUR" && a.OpeningBalance == 5000
+	})).Return(models.Account{ID: 1, UserID: 1, Name: "Wallet", Type: "cash", Currency: "EUR",
+		OpeningBalance: 5000, Balance: 5000}, nil)
+
+	body := `{"name": " Wallet ", "type": "cash", "currency": "eur", "opening_balance": 50}`
+	req := httptest.NewRequest(http.MethodPost, "/accounts", bytes.NewReader([]byte(body)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.AccountsHandler(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAccountHandlers_OpeningBalanceRange(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService())
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+
+	mockDB.On("AddAccount", mock.Anything, 1, mock.Anything).Return(models.Account{ID: 1, UserID: 1}, nil).Once()
+
+	// Остаток NUMERIC(12,2) может быть отрицательным, но не больше 9999999999.99 по модулю
+	for _, tc := range []struct {
+		balance string
+		status  int
+	}{
+		{"-9999999999.99", http.StatusCreated},
+		{"10000000000", http.StatusBadRequest},
+		{"-10000000000", http.StatusBadRequest},
+	} {
+		body := `{"name": "Card", "opening_balance": ` + tc.balance + `}`
+		req := httptest.NewRequest(http.MethodPost, "/accounts", bytes.NewReader([]byte(body)))
+		req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+		rr := httptest.NewRecorder()
+		s.AccountsHandler(rr, req)
+		assert.Equal(t, tc.status, rr.Code, tc.balance)
+	}
+
+	req := httptest.NewRequest(http.MethodPatch, "/accounts/1", bytes.NewReader([]byte(`{"opening_balance": 12345678901.00}`)))
+	req.SetPathValue("id", "1")
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr := httptest.NewRecorder()
+	s.AccountHandler(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "opening_balance must be between")
+
+	mockDB.AssertNotCalled(t, "UpdateAccount", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetAccountsHandler_Balances(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	accounts := []models.Account{
+		{ID: 1, UserID: 1, Name: "Card", Type: "card", Currency: "EUR", Balance: -12050},
+		{ID: 2, UserID: 1, Name: "Savings", Type: "savings", Currency: "EUR", OpeningBalance: 100000, Balance: 150000},
+	}
+	mockDB.On("GetAccounts", mock.Anything, 1).Return(accounts, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.AccountsHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data []models.Account `json:"data"`
+	}
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, accounts, resp.Data)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestDeleteAccountHandler_InUse(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("DeleteAccount", mock.Anything, 1, 2).Return(db.ErrAccountInUse)
+
+	req := httptest.NewRequest(http.MethodDelete, "/accounts/2", nil)
+	req.SetPathValue("id", "2")
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.AccountHandler(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAddTransferHandler_SameCurrency(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("GetAccountByID", mock.Anything, 1, 1).Return(models.Account{ID: 1, Currency: "EUR"}, nil)
+	mockDB.On("GetAccountByID", mock.Anything, 1, 2).Return(models.Account{ID: 2, Currency: "EUR"}, nil)
+	mockDB.On("AddTransfer", mock.Anything, 1, mock.MatchedBy(func(t *models.Transfer) bool {
+		return t.Amount == 20000 && t.ToAmount == 20000
+	})).Return(models.Transfer{ID: 1, UserID: 1, FromAccountID: 1, ToAccountID: 2, Amount: 20000, ToAmount: 20000,
+		DebitID: 10, CreditID: 11}, nil)
+
+	body := `{"from_account_id": 1, "to_account_id": 2, "amount": 200}`
+	req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(body)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.AddTransferHandler(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"credit_transaction_id":11`)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAddTransferHandler_CrossCurrencyRequiresToAmount(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("GetAccountByID", mock.Anything, 1, 1).Return(models.Account{ID: 1, Currency: "EUR"}, nil)
+	mockDB.On("GetAccountByID", mock.Anything, 1, 3).Return(models.Account{ID: 3, Currency: "PLN"}, nil)
+
+	body := `{"from_account_id": 1, "to_account_id": 3, "amount": 100}`
+	req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(body)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.AddTransferHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "to_amount is required for transfers between accounts in different currencies", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
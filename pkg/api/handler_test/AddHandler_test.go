@@ -262,3 +262,274 @@ func TestAddHandler_DBError(t *testing.T) {
 
 	mockDB.AssertExpectations(t)
 }
+
+func TestAddHandler_AmountValidation(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	tests := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{"Too many decimals", `{"amount": 10.005, "category_id": 2}`, "amount must have at most two decimal places"},
+		{"Too many decimals as string", `{"amount": "0.001", "category_id": 2}`, "amount must have at most two decimal places"},
+		{"Above column precision", `{"amount": 100000000, "category_id": 2}`, "amount must not exceed 99999999.99"},
+		{"Not a number", `{"amount": "ten", "category_id": 2}`, "invalid amount format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader([]byte(tt.body)))
+			claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+			ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+
+			s.AddHandler(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+			var actualResp models.ErrorResponse
+			err := json.NewDecoder(rr.Body).Decode(&actualResp)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.message, actualResp.Message)
+		})
+	}
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAddHandler_ExactDecimalAmount(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	returned := models.Transaction{ID: 1, Amount: 1999, CategoryID: 2, UserID: 1}
+
+	mockDB.On("CheckCategory", mock.Anything, 1, 2).Return(true, nil)
+	mockDB.On("AddTransaction", mock.Anything, 1, mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.Amount == models.Money(1999)
+	})).Return(returned, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader([]byte(`{"amount": 19.99, "category_id": 2}`)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.AddHandler(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"amount":19.99`)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAddHandler_Currency(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("CheckCategory", mock.Anything, 1, 2).Return(true, nil)
+	mockDB.On("AddTransaction", mock.Anything, 1, mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.Currency == "PLN"
+	})).Return(models.Transaction{ID: 1, Amount: 5000, CategoryID: 2, UserID: 1, Currency: "PLN"}, nil)
+
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+
+	req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader([]byte(`{"amount": 50, "category_id": 2, "currency": "pln"}`)))
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr := httptest.NewRecorder()
+
+	s.AddHandler(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader([]byte(`{"amount": 50, "category_id": 2, "currency": "zloty"}`)))
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr = httptest.NewRecorder()
+
+	s.AddHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var actualResp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&actualResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "currency must be a 3-letter ISO 4217 code", actualResp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAddHandler_Tags(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("CheckCategory", mock.Anything, 1, 2).Return(true, nil)
+	mockDB.On("AddTransaction", mock.Anything, 1, mock.MatchedBy(func(tx *models.Transaction) bool {
+		return assert.ObjectsAreEqual([]string{"vacation-2026", "reimbursable"}, tx.Tags)
+	})).Return(models.Transaction{ID: 1, Amount: 5000, CategoryID: 2, UserID: 1,
+		Tags: []string{"reimbursable", "vacation-2026"}}, nil)
+
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+
+	req := httptest.NewRequest(http.MethodPost, "/transactions",
+		bytes.NewReader([]byte(`{"amount": 50, "category_id": 2, "tags": [" Vacation-2026", "reimbursable", "vacation-2026 "]}`)))
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr := httptest.NewRecorder()
+
+	s.AddHandler(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/transactions",
+		bytes.NewReader([]byte(`{"amount": 50, "category_id": 2, "tags": ["business", " "]}`)))
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr = httptest.NewRecorder()
+
+	s.AddHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var actualResp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&actualResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "tag cannot be empty", actualResp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAddHandler_Splits(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	splits := []models.Split{
+		{CategoryID: 2, Amount: 6000, Note: "groceries"},
+		{CategoryID: 3, Amount: 4000, Note: "household"},
+	}
+
+	mockDB.On("CheckCategory", mock.Anything, 1, 2).Return(true, nil)
+	mockDB.On("CheckCategory", mock.Anything, 1, 3).Return(true, nil)
+	mockDB.On("AddTransaction", mock.Anything, 1, mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.CategoryID == 2 && assert.ObjectsAreEqual(splits, tx.Splits)
+	})).Return(models.Transaction{ID: 1, Amount: 10000, CategoryID: 2, UserID: 1, Splits: splits}, nil)
+
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+
+	req := httptest.NewRequest(http.MethodPost, "/transactions",
+		bytes.NewReader([]byte(`{"amount": 100, "splits": [
+			{"category_id": 2, "amount": 60, "note": "groceries"},
+			{"category_id": 3, "amount": 40, "note": "household"}]}`)))
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr := httptest.NewRecorder()
+
+	s.AddHandler(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/transactions",
+		bytes.NewReader([]byte(`{"amount": 100, "category_id": 2, "splits": [
+			{"category_id": 2, "amount": 60},
+			{"category_id": 3, "amount": 39.99}]}`)))
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr = httptest.NewRecorder()
+
+	s.AddHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var actualResp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&actualResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "split amounts add up to 99.99, expected 100.00", actualResp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAddHandler_CategoryFromRule(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	rent, groceries := 4, 5
+	income := true
+	mockDB.On("GetCategoryRules", mock.Anything, 1).Return([]models.CategoryRule{
+		{ID: 1, Name: "Salary", IsIncome: &income, CategoryID: &rent},
+		{ID: 2, Name: "Supermarket", NoteRegex: `(?i)\b(lidl|aldi)\b`, CategoryID: &groceries, Tags: []string{"food"}},
+	}, nil)
+	mockDB.On("CheckCategory", mock.Anything, 1, 5).Return(true, nil)
+	mockDB.On("AddTransaction", mock.Anything, 1, mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.CategoryID == 5 && assert.ObjectsAreEqual([]string{"weekly", "food"}, tx.Tags)
+	})).Return(models.Transaction{ID: 1, Amount: 3250, CategoryID: 5, UserID: 1, Tags: []string{"food", "weekly"}}, nil)
+
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+
+	req := httptest.NewRequest(http.MethodPost, "/transactions",
+		bytes.NewReader([]byte(`{"amount": 32.50, "note": "LIDL Berlin", "tags": ["weekly"]}`)))
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr := httptest.NewRecorder()
+
+	s.AddHandler(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	// Ни одно правило не подошло — категория по-прежнему обязательна
+	req = httptest.NewRequest(http.MethodPost, "/transactions",
+		bytes.NewReader([]byte(`{"amount": 12, "note": "Bakery"}`)))
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr = httptest.NewRecorder()
+
+	s.AddHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var actualResp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&actualResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "category_id is required", actualResp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAddHandler_OccurredAt(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	occurredAt := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	mockDB.On("CheckCategory", mock.Anything, 1, 2).Return(true, nil)
+	mockDB.On("AddTransaction", mock.Anything, 1, mock.MatchedBy(func(tx *models.Transaction) bool {
+		return tx.OccurredAt.Equal(occurredAt)
+	})).Return(models.Transaction{ID: 1, Amount: 100, CategoryID: 2, UserID: 1,
+		OccurredAt: models.DateTime{Time: occurredAt}}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/transactions",
+		bytes.NewReader([]byte(`{"amount": 1, "category_id": 2, "occurred_at": "2025-07-01"}`)))
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, &auth.Claims{UserID: 1, Email: "test@example.com"}))
+	rr := httptest.NewRecorder()
+
+	s.AddHandler(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var resp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-07-01T00:00:00Z", resp.Data["occurred_at"])
+
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,147 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/ViktorOHJ/expense-tracker/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var pngReceipt = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0x42}, 600)...)
+
+func attachmentRequest(t *testing.T, fileName string, content []byte) *http.Request {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", fileName)
+	assert.NoError(t, err)
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/transaction/5/attachments", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.SetPathValue("id", "5")
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	return req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+}
+
+func TestUploadAttachmentHandler_UploadAndDownload(t *testing.T) {
+	mockDB := new(mocks.DB)
+	blobs, err := storage.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService(), api.WithBlobStore(blobs))
+
+	var stored models.Attachment
+	mockDB.On("AddAttachment", mock.Anything, 1, mock.MatchedBy(func(a *models.Attachment) bool {
+		return a.TransactionID == 5 && a.FileName == "receipt.png" && a.ContentType == "image/png" &&
+			a.Size == int64(len(pngReceipt)) && a.StorageKey != ""
+	})).Run(func(args mock.Arguments) {
+		stored = *args.Get(2).(*models.Attachment)
+		stored.ID = 3
+		stored.UserID = 1
+	}).Return(func(_ context.Context, _ int, _ *models.Attachment) models.Attachment { return stored }, nil)
+
+	rr := httptest.NewRecorder()
+	s.UploadAttachmentHandler(rr, attachmentRequest(t, "C:\\photos\\receipt.png", pngReceipt))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.NotContains(t, rr.Body.String(), stored.StorageKey)
+
+	mockDB.On("GetAttachment", mock.Anything, 1, 5, 3).Return(stored, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/transaction/5/attachments/3", nil)
+	req.SetPathValue("id", "5")
+	req.SetPathValue("attachmentID", "3")
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, &auth.Claims{UserID: 1, Email: "test@example.com"}))
+	rr = httptest.NewRecorder()
+
+	s.DownloadAttachmentHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename=receipt.png`, rr.Header().Get("Content-Disposition"))
+	assert.Equal(t, pngReceipt, rr.Body.Bytes())
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUploadAttachmentHandler_UnsupportedType(t *testing.T) {
+	mockDB := new(mocks.DB)
+	blobs, err := storage.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService(), api.WithBlobStore(blobs))
+
+	// Расширение и заголовок клиента не важны — тип определяется по содержимому
+	rr := httptest.NewRecorder()
+	s.UploadAttachmentHandler(rr, attachmentRequest(t, "receipt.png", []byte("#!/bin/sh\necho not a receipt\n")))
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+
+	var actualResp models.ErrorResponse
+	err = json.NewDecoder(rr.Body).Decode(&actualResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "file must be a JPEG, PNG, GIF, WebP image or a PDF document", actualResp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUploadAttachmentHandler_TransactionNotFound(t *testing.T) {
+	mockDB := new(mocks.DB)
+	blobs, err := storage.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService(), api.WithBlobStore(blobs))
+
+	var key string
+	mockDB.On("AddAttachment", mock.Anything, 1, mock.Anything).Run(func(args mock.Arguments) {
+		key = args.Get(2).(*models.Attachment).StorageKey
+	}).Return(models.Attachment{}, db.ErrNotFound)
+
+	rr := httptest.NewRecorder()
+	s.UploadAttachmentHandler(rr, attachmentRequest(t, "receipt.png", pngReceipt))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	// Уже записанный файл удаляется
+	_, err = blobs.Get(context.Background(), key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestDeleteHandler_RemovesAttachmentFiles(t *testing.T) {
+	mockDB := new(mocks.DB)
+	blobs, err := storage.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService(), api.WithBlobStore(blobs))
+
+	key := "attachments/1/1/0123456789abcdef"
+	assert.NoError(t, blobs.Put(context.Background(), key, strings.NewReader("%PDF-1.7"), 8, "application/pdf"))
+
+	mockDB.On("DeleteTransaction", mock.Anything, 1, 1).Return([]string{key}, nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/transaction/?id=1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, &auth.Claims{UserID: 1, Email: "test@example.com"}))
+	rr := httptest.NewRecorder()
+
+	s.DeleteHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	_, err = blobs.Get(context.Background(), key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,217 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLoginHandler_IssuesRefreshToken(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	hash, err := passwordService.HashPassword("secret123")
+	assert.NoError(t, err)
+
+	mockDB.On("GetUserByEmail", mock.Anything, "test@example.com").
+		Return(models.User{ID: 1, Email: "test@example.com", Password: hash, EmailVerified: true}, nil)
+	mockDB.On("GetLoginFailures", mock.Anything, "test@example.com", mock.Anything, mock.Anything).
+		Return(models.LoginFailures{}, nil)
+	mockDB.On("RecordLoginAttempt", mock.Anything, mock.MatchedBy(func(a *models.LoginAttempt) bool {
+		return a.Success && *a.UserID == 1 && a.Email == "test@example.com"
+	})).Return(nil)
+
+	var stored *models.RefreshToken
+	mockDB.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(rt *models.RefreshToken) bool {
+		stored = rt
+		return rt.UserID == 1 && rt.FamilyID != "" && rt.AccessJTI != ""
+	})).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/login",
+		bytes.NewReader([]byte(`{"email": "test@example.com", "password": "secret123"}`)))
+	rr := httptest.NewRecorder()
+
+	s.LoginHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data models.AuthResponse `json:"data"`
+	}
+	err = json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, resp.Data.RefreshToken)
+
+	// В БД хранится только хеш refresh-токена, а jti access-токена связывает его с сессией
+	assert.Equal(t, auth.HashToken(resp.Data.RefreshToken), stored.TokenHash)
+	claims, err := jwtService.ValidateToken(resp.Data.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, stored.AccessJTI, claims.ID)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestRefreshHandler_Rotates(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	var next *models.RefreshToken
+	mockDB.On("RotateRefreshToken", mock.Anything, auth.HashToken("old-token"), mock.MatchedBy(func(rt *models.RefreshToken) bool {
+		next = rt
+		return rt.TokenHash != "" && rt.AccessJTI != ""
+	})).Return(models.User{ID: 1, Email: "test@example.com"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewReader([]byte(`{"refresh_token": "old-token"}`)))
+	rr := httptest.NewRecorder()
+
+	s.RefreshHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data models.AuthResponse `json:"data"`
+	}
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "old-token", resp.Data.RefreshToken)
+	assert.Equal(t, auth.HashToken(resp.Data.RefreshToken), next.TokenHash)
+
+	claims, err := jwtService.ValidateToken(resp.Data.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, claims.UserID)
+	assert.Equal(t, next.AccessJTI, claims.ID)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestRefreshHandler_ReuseDetected(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("RotateRefreshToken", mock.Anything, auth.HashToken("used-token"), mock.Anything).
+		Return(models.User{}, db.ErrTokenReused)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewReader([]byte(`{"refresh_token": "used-token"}`)))
+	rr := httptest.NewRecorder()
+
+	s.RefreshHandler(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestLogoutHandler_RevokesSession(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	token, err := jwtService.GenerateToken(1, "test@example.com")
+	assert.NoError(t, err)
+	claims, err := jwtService.ValidateToken(token)
+	assert.NoError(t, err)
+
+	mockDB.On("RevokeSession", mock.Anything, 1, claims.ID, claims.ExpiresAt.Time).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.LogoutHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAuthMiddleware_RevokedToken(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	token, err := jwtService.GenerateToken(1, "test@example.com")
+	assert.NoError(t, err)
+	claims, err := jwtService.ValidateToken(token)
+	assert.NoError(t, err)
+
+	mockDB.On("IsTokenRevoked", mock.Anything, claims.ID).Return(true, nil)
+
+	called := false
+	handler := s.AuthMiddleware(func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	req := httptest.NewRequest(http.MethodGet, "/summary", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+
+	handler(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.False(t, called)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestJWKSHandler(t *testing.T) {
+	mockDB := new(mocks.DB)
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	assert.NoError(t, err)
+	key, err := auth.ParseSigningKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	assert.NoError(t, err)
+
+	jwtService := auth.NewJWTService("test-secret", auth.WithSigningKeys(key))
+	s := api.NewServer(mockDB, jwtService, auth.NewPasswordService())
+	routes := s.InitRoutes()
+
+	rr := httptest.NewRecorder()
+	routes.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Набор ключей отдаётся без обёртки data, секрет HS256 не публикуется
+	var jwks auth.JWKS
+	err = json.NewDecoder(rr.Body).Decode(&jwks)
+	assert.NoError(t, err)
+	assert.Len(t, jwks.Keys, 1)
+	assert.Equal(t, key.ID, jwks.Keys[0].Kid)
+	assert.Equal(t, "EdDSA", jwks.Keys[0].Alg)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+
+	// Токен, подписанный ключом, принимается защищёнными маршрутами
+	token, err := jwtService.GenerateToken(1, "test@example.com")
+	assert.NoError(t, err)
+	mockDB.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil)
+	mockDB.On("GetTags", mock.Anything, 1).Return([]models.Tag{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr = httptest.NewRecorder()
+	routes.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,190 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddBudgetHandler_MonthlyStartsOnFirstDay(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("CheckCategory", mock.Anything, 1, 2).Return(true, nil)
+	mockDB.On("AddBudget", mock.Anything, 1, mock.MatchedBy(func(b *models.Budget) bool {
+		return b.Period == models.BudgetMonthly && b.Amount == 40000 && b.Rollover &&
+			b.StartDate.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	})).Return(models.Budget{ID: 1, UserID: 1, Amount: 40000, Period: models.BudgetMonthly}, nil)
+
+	body := `{"category_id": 2, "amount": 400, "start_date": "2024-05-17", "rollover": true}`
+	req := httptest.NewRequest(http.MethodPost, "/budgets", bytes.NewReader([]byte(body)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.BudgetsHandler(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var resp models.SuccessResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "budget added successfully", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAddBudgetHandler_Validation(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	tests := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{"Zero amount", `{"amount": 0}`, "amount must be greater than 0"},
+		{"Unknown period", `{"amount": 10, "period": "weekly"}`, "period must be 'monthly' or 'custom'"},
+		{"Custom without end", `{"amount": 10, "period": "custom", "start_date": "2024-05-01"}`, "start_date and end_date are required for custom period"},
+		{"Custom with rollover", `{"amount": 10, "period": "custom", "start_date": "2024-05-01", "end_date": "2024-05-20", "rollover": true}`, "rollover is only supported for monthly budgets"},
+		{"End before start", `{"amount": 10, "period": "custom", "start_date": "2024-05-10", "end_date": "2024-05-01"}`, "end_date must not be before start_date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/budgets", bytes.NewReader([]byte(tt.body)))
+			claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+			ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+
+			s.AddBudgetHandler(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+			var resp models.ErrorResponse
+			err := json.NewDecoder(rr.Body).Decode(&resp)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.message, resp.Message)
+		})
+	}
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetBudgetsHandler_Success(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	on := time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)
+	statuses := []models.BudgetStatus{
+		{
+			Budget:      models.Budget{ID: 1, UserID: 1, Amount: 40000, Period: models.BudgetMonthly},
+			CarriedOver: 5000,
+			Limit:       45000,
+			Spent:       47250,
+			Remaining:   -2250,
+			Percentage:  105,
+			Overspent:   true,
+		},
+	}
+	mockDB.On("GetBudgetStatuses", mock.Anything, 1, on).Return(statuses, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/budgets?date=2024-05-17", nil)
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.BudgetsHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"remaining":-22.50`)
+	assert.Contains(t, rr.Body.String(), `"overspent":true`)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestDeleteBudgetHandler_NotFound(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("DeleteBudget", mock.Anything, 1, 3).Return(db.ErrNotFound)
+
+	req := httptest.NewRequest(http.MethodDelete, "/budgets/3", nil)
+	req.SetPathValue("id", "3")
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.BudgetHandler(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateBudgetHandler_Validation(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	endDate := models.NewDate(time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC))
+	mockDB.On("GetBudgetByID", mock.Anything, 1, 4).Return(models.Budget{ID: 4, UserID: 1, Amount: 1000,
+		Period: models.BudgetCustom, StartDate: models.NewDate(time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)), EndDate: &endDate}, nil)
+
+	tests := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{"Custom with rollover", `{"rollover": true}`, "rollover is only supported for monthly budgets"},
+		{"End before start", `{"end_date": "2024-05-01"}`, "end_date must not be before start_date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/budgets/4", bytes.NewReader([]byte(tt.body)))
+			req.SetPathValue("id", "4")
+			claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+			ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+
+			s.UpdateBudgetHandler(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+			var resp models.ErrorResponse
+			err := json.NewDecoder(rr.Body).Decode(&resp)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.message, resp.Message)
+		})
+	}
+
+	mockDB.AssertNotCalled(t, "UpdateBudget", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockDB.AssertExpectations(t)
+}
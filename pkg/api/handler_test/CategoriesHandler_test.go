@@ -0,0 +1,345 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddCategoryHandler_Duplicate(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("AddCategory", mock.Anything, 1, mock.Anything).Return(models.Category{}, db.ErrAlreadyExists)
+
+	req := httptest.NewRequest(http.MethodPost, "/categories", bytes.NewReader([]byte(`{"name": "Food"}`)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.CategoriesHandler(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	var resp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "category with this name already exists", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetCategoriesHandler_Success(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	expected := []models.Category{
+		{ID: 1, Name: "Food", UserID: 1, TransactionCount: 3},
+		{ID: 2, Name: "Old", UserID: 1, Archived: true},
+	}
+	mockDB.On("GetCategories", mock.Anything, 1, true).Return(expected, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/categories?include_archived=true", nil)
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.CategoriesHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp models.SuccessResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "categories listed successfully", resp.Message)
+
+	dataBytes, err := json.Marshal(resp.Data)
+	assert.NoError(t, err)
+	var actual []models.Category
+	err = json.Unmarshal(dataBytes, &actual)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestGetCategoriesHandler_InvalidArchivedFlag(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	req := httptest.NewRequest(http.MethodGet, "/categories?include_archived=maybe", nil)
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.GetCategoriesHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestCategoryByIdHandler_NotFound(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("GetCategoryByID", mock.Anything, 1, 5).Return(models.Category{}, db.ErrNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/categories/5", nil)
+	req.SetPathValue("id", "5")
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.CategoryHandler(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	var resp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "category with id 5 not found or access denied", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateCategoryHandler_Archive(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	updated := models.Category{ID: 5, Name: "Groceries", UserID: 1, Archived: true}
+	mockDB.On("UpdateCategory", mock.Anything, 1, 5, mock.MatchedBy(func(u *models.CategoryUpdate) bool {
+		return u.Archived != nil && *u.Archived && u.Name != nil && *u.Name == "Groceries" && u.Description == nil
+	})).Return(updated, nil)
+
+	body := `{"name": "  Groceries ", "archived": true}`
+	req := httptest.NewRequest(http.MethodPatch, "/categories/5", bytes.NewReader([]byte(body)))
+	req.SetPathValue("id", "5")
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.CategoryHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp models.SuccessResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "category with id: 5 successfully updated", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateCategoryHandler_EmptyName(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	req := httptest.NewRequest(http.MethodPatch, "/categories/5", bytes.NewReader([]byte(`{"name": " "}`)))
+	req.SetPathValue("id", "5")
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.UpdateCategoryHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "category name cannot be empty", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestDeleteCategoryHandler_InUse(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("DeleteCategory", mock.Anything, 1, 5, (*int)(nil)).Return(db.ErrCategoryInUse)
+
+	req := httptest.NewRequest(http.MethodDelete, "/categories/5", nil)
+	req.SetPathValue("id", "5")
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.CategoryHandler(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestDeleteCategoryHandler_Reassign(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("CheckCategory", mock.Anything, 1, 7).Return(true, nil)
+	mockDB.On("DeleteCategory", mock.Anything, 1, 5, mock.MatchedBy(func(target *int) bool {
+		return target != nil && *target == 7
+	})).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/categories/5?reassign_to=7", nil)
+	req.SetPathValue("id", "5")
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.DeleteCategoryHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp models.SuccessResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "category with id: 5 successfully deleted", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestDeleteCategoryHandler_BadRequest(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("CheckCategory", mock.Anything, 1, 9).Return(false, nil)
+
+	tests := []struct {
+		name    string
+		id      string
+		query   string
+		message string
+	}{
+		{"Invalid ID", "abc", "/categories/abc", "id must be a positive number"},
+		{"Reassign to itself", "5", "/categories/5?reassign_to=5", "reassign_to must differ from the deleted category"},
+		{"Invalid target", "5", "/categories/5?reassign_to=x", "reassign_to must be a positive number"},
+		{"Foreign target", "5", "/categories/5?reassign_to=9", "target category does not exist or access denied"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, tt.query, nil)
+			req.SetPathValue("id", tt.id)
+			claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+			ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+
+			s.DeleteCategoryHandler(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+			var resp models.ErrorResponse
+			err := json.NewDecoder(rr.Body).Decode(&resp)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.message, resp.Message)
+		})
+	}
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAddCategoryHandler_ForeignParent(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("CheckCategory", mock.Anything, 1, 42).Return(false, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/categories", bytes.NewReader([]byte(`{"name": "Groceries", "parent_id": 42}`)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.CategoriesHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "parent category does not exist or access denied", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateCategoryHandler_ParentCycle(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+
+	// Категория не может быть родителем самой себе — до БД запрос не доходит
+	req := httptest.NewRequest(http.MethodPatch, "/categories/5", bytes.NewReader([]byte(`{"parent_id": 5}`)))
+	req.SetPathValue("id", "5")
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr := httptest.NewRecorder()
+
+	s.CategoryHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// Перенос в собственного потомка отклоняет БД
+	mockDB.On("CheckCategory", mock.Anything, 1, 7).Return(true, nil)
+	mockDB.On("UpdateCategory", mock.Anything, 1, 5, mock.MatchedBy(func(u *models.CategoryUpdate) bool {
+		return u.ParentID != nil && *u.ParentID == 7
+	})).Return(models.Category{}, db.ErrCategoryCycle)
+
+	req = httptest.NewRequest(http.MethodPatch, "/categories/5", bytes.NewReader([]byte(`{"parent_id": 7}`)))
+	req.SetPathValue("id", "5")
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr = httptest.NewRecorder()
+
+	s.CategoryHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, db.ErrCategoryCycle.Error(), resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
@@ -23,7 +23,7 @@ func TestDeleteHandler_Success(t *testing.T) {
 	s := api.NewServer(mockDB, jwtService, passwordService)
 
 	// Mock с userID
-	mockDB.On("DeleteTransaction", mock.Anything, 1, 1).Return(nil)
+	mockDB.On("DeleteTransaction", mock.Anything, 1, 1).Return(nil, nil)
 
 	req := httptest.NewRequest(http.MethodDelete, "/transaction/?id=1", nil)
 
@@ -68,7 +68,7 @@ func TestDeleteHandler_NotFound(t *testing.T) {
 	jwtService := auth.NewJWTService("test-secret")
 	passwordService := auth.NewPasswordService()
 	s := api.NewServer(mockDB, jwtService, passwordService)
-	mockDB.On("DeleteTransaction", mock.Anything, 1, 1).Return(db.ErrNotFound)
+	mockDB.On("DeleteTransaction", mock.Anything, 1, 1).Return(nil, db.ErrNotFound)
 
 	req := httptest.NewRequest(http.MethodDelete, "/transaction/?id=1", nil)
 	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
@@ -133,7 +133,7 @@ func TestDeleteHandler_DBError(t *testing.T) {
 	passwordService := auth.NewPasswordService()
 	s := api.NewServer(mockDB, jwtService, passwordService)
 
-	mockDB.On("DeleteTransaction", mock.Anything, 1, 1).Return(assert.AnError)
+	mockDB.On("DeleteTransaction", mock.Anything, 1, 1).Return(nil, assert.AnError)
 
 	req := httptest.NewRequest(http.MethodDelete, "/transaction/?id=1", nil)
 
@@ -0,0 +1,240 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mail"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeMailer запоминает отправленные письма; письма по запросу пользователя
+// отправляются в фоне, поэтому доступ под мьютексом
+type fakeMailer struct {
+	mu   sync.Mutex
+	sent []mail.Message
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func (m *fakeMailer) messages() []mail.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]mail.Message(nil), m.sent...)
+}
+
+func newMailServer(mockDB *mocks.DB) (*api.Server, *fakeMailer) {
+	mailer := &fakeMailer{}
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService(),
+		api.WithMailer(mailer), api.WithAppURL("https://app.example.com/"))
+	return s, mailer
+}
+
+func TestRegisterHandler_SendsVerificationEmail(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s, mailer := newMailServer(mockDB)
+
+	mockDB.On("GetUserByEmail", mock.Anything, "new@example.com").Return(models.User{}, db.ErrNotFound)
+	mockDB.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.User")).
+		Return(models.User{ID: 7, Email: "new@example.com"}, nil)
+
+	var stored *models.UserToken
+	mockDB.On("CreateUserToken", mock.Anything, mock.MatchedBy(func(t *models.UserToken) bool {
+		stored = t
+		return t.UserID == 7 && t.Purpose == models.TokenVerifyEmail
+	})).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/register",
+		bytes.NewReader([]byte(`{"email": "new@example.com", "password": "secret123"}`)))
+	rr := httptest.NewRecorder()
+
+	s.RegisterHandler(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	// До подтверждения адреса сессия не выдаётся
+	assert.NotContains(t, rr.Body.String(), "refresh_token")
+
+	assert.Len(t, mailer.messages(), 1)
+	msg := mailer.messages()[0]
+	assert.Equal(t, "new@example.com", msg.To)
+	assert.Contains(t, msg.Body, "https://app.example.com/verify-email?token=")
+
+	// В письме сам токен, в БД только его хеш
+	token := msg.Body[strings.Index(msg.Body, "token=")+len("token="):]
+	token = token[:strings.IndexByte(token, '\n')]
+	assert.Equal(t, auth.HashToken(token), stored.TokenHash)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), stored.ExpiresAt, time.Minute)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestLoginHandler_UnverifiedEmail(t *testing.T) {
+	mockDB := new(mocks.DB)
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), passwordService)
+
+	hash, err := passwordService.HashPassword("secret123")
+	assert.NoError(t, err)
+	mockDB.On("GetUserByEmail", mock.Anything, "test@example.com").
+		Return(models.User{ID: 1, Email: "test@example.com", Password: hash}, nil)
+	mockDB.On("GetLoginFailures", mock.Anything, "test@example.com", mock.Anything, mock.Anything).
+		Return(models.LoginFailures{}, nil)
+	mockDB.On("RecordLoginAttempt", mock.Anything, mock.MatchedBy(func(a *models.LoginAttempt) bool {
+		return a.Reason == models.LoginEmailNotVerified
+	})).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/login",
+		bytes.NewReader([]byte(`{"email": "test@example.com", "password": "secret123"}`)))
+	rr := httptest.NewRecorder()
+
+	s.LoginHandler(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	mockDB.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything)
+}
+
+func TestVerifyEmailHandler(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s, _ := newMailServer(mockDB)
+
+	mockDB.On("VerifyEmail", mock.Anything, auth.HashToken("good-token")).
+		Return(models.User{ID: 1, Email: "test@example.com", EmailVerified: true}, nil)
+	mockDB.On("VerifyEmail", mock.Anything, auth.HashToken("old-token")).
+		Return(models.User{}, db.ErrTokenExpired)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/verify-email?token=good-token", nil)
+	rr := httptest.NewRecorder()
+	s.VerifyEmailHandler(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data models.User `json:"data"`
+	}
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.True(t, resp.Data.EmailVerified)
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/verify-email", bytes.NewReader([]byte(`{"token": "old-token"}`)))
+	rr = httptest.NewRecorder()
+	s.VerifyEmailHandler(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestForgotPasswordHandler(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s, mailer := newMailServer(mockDB)
+
+	mockDB.On("GetMailRequests", mock.Anything, mock.Anything, "192.0.2.1", mock.Anything).
+		Return(models.LoginFailures{}, nil)
+	mockDB.On("RecordLoginAttempt", mock.Anything, mock.MatchedBy(func(a *models.LoginAttempt) bool {
+		return a.UserID == nil && a.Reason == models.LoginMailRequested
+	})).Return(nil).Twice()
+	mockDB.On("GetUserByEmail", mock.Anything, "test@example.com").
+		Return(models.User{ID: 1, Email: "test@example.com", EmailVerified: true}, nil)
+	lookedUp := make(chan struct{})
+	mockDB.On("GetUserByEmail", mock.Anything, "nobody@example.com").Return(models.User{}, db.ErrNotFound).
+		Run(func(mock.Arguments) { close(lookedUp) })
+	mockDB.On("CreateUserToken", mock.Anything, mock.MatchedBy(func(t *models.UserToken) bool {
+		return t.UserID == 1 && t.Purpose == models.TokenResetPassword
+	})).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/forgot-password", bytes.NewReader([]byte(`{"email": "test@example.com"}`)))
+	rr := httptest.NewRecorder()
+	s.ForgotPasswordHandler(rr, req)
+	known := rr.Body.String()
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Письмо отправляется в фоне после ответа
+	assert.Eventually(t, func() bool { return len(mailer.messages()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Contains(t, mailer.messages()[0].Body, "https://app.example.com/reset-password?token=")
+
+	// Неизвестный адрес получает тот же ответ, но письмо не отправляется
+	req = httptest.NewRequest(http.MethodPost, "/auth/forgot-password", bytes.NewReader([]byte(`{"email": "nobody@example.com"}`)))
+	rr = httptest.NewRecorder()
+	s.ForgotPasswordHandler(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, known, rr.Body.String())
+
+	select {
+	case <-lookedUp:
+	case <-time.After(time.Second):
+		t.Fatal("user lookup did not run")
+	}
+	assert.Len(t, mailer.messages(), 1)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestForgotPasswordHandler_Throttled(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s, mailer := newMailServer(mockDB)
+
+	// Ограничение одинаково для зарегистрированных и неизвестных адресов: пользователь
+	// даже не ищется
+	mockDB.On("GetMailRequests", mock.Anything, "test@example.com", mock.Anything, mock.Anything).
+		Return(models.LoginFailures{Account: 10, AccountLast: time.Now().Add(-time.Hour)}, nil)
+	mockDB.On("GetMailRequests", mock.Anything, "other@example.com", mock.Anything, mock.Anything).
+		Return(models.LoginFailures{IP: 50, IPLast: time.Now()}, nil)
+
+	for email, retryAfter := range map[string]int{"Test@Example.com": 23 * 60 * 60, "other@example.com": 60 * 60} {
+		body := `{"email": "` + email + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/auth/forgot-password", bytes.NewReader([]byte(body)))
+		rr := httptest.NewRecorder()
+		s.ForgotPasswordHandler(rr, req)
+
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code, email)
+		seconds, err := strconv.Atoi(rr.Header().Get("Retry-After"))
+		assert.NoError(t, err)
+		assert.InDelta(t, retryAfter, seconds, 2, email)
+	}
+
+	mockDB.AssertNotCalled(t, "RecordLoginAttempt", mock.Anything, mock.Anything)
+	mockDB.AssertNotCalled(t, "GetUserByEmail", mock.Anything, mock.Anything)
+	assert.Empty(t, mailer.messages())
+	mockDB.AssertExpectations(t)
+}
+
+func TestResetPasswordHandler(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s, _ := newMailServer(mockDB)
+	passwordService := auth.NewPasswordService()
+
+	mockDB.On("ResetPassword", mock.Anything, auth.HashToken("reset-token"), mock.MatchedBy(func(hash string) bool {
+		return passwordService.CheckPassword(hash, "new-secret")
+	})).Return(models.User{ID: 1, Email: "test@example.com", EmailVerified: true}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/reset-password",
+		bytes.NewReader([]byte(`{"token": "reset-token", "password": "new-secret"}`)))
+	rr := httptest.NewRecorder()
+	s.ResetPasswordHandler(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/reset-password",
+		bytes.NewReader([]byte(`{"token": "reset-token", "password": "123"}`)))
+	rr = httptest.NewRecorder()
+	s.ResetPasswordHandler(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,159 @@
+package handler_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var exportedTransactions = []models.ExportedTransaction{
+	{
+		Transaction: models.Transaction{ID: 1, Amount: 123450, CategoryID: 2, UserID: 1, Note: "Rent, May",
+			Currency: "EUR", Tags: []string{"home", "monthly"}, OccurredAt: models.DateTime{Time: time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)}},
+		CategoryName: "Housing",
+	},
+	{
+		Transaction: models.Transaction{ID: 2, IsIncome: true, Amount: 250000, CategoryID: 3, UserID: 1,
+			Currency: "EUR", OccurredAt: models.DateTime{Time: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)}},
+		CategoryName: "Salary",
+	},
+}
+
+func mockExport(mockDB *mocks.DB, filter interface{}) {
+	mockDB.On("ExportTransactions", mock.Anything, 1, filter, mock.Anything).
+		Run(func(args mock.Arguments) {
+			write := args.Get(3).(func(models.ExportedTransaction) error)
+			for _, t := range exportedTransactions {
+				write(t)
+			}
+		}).
+		Return(nil)
+}
+
+func TestExportHandler_CSV(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockExport(mockDB, mock.MatchedBy(func(f models.TransactionFilter) bool {
+		return f.IsIncome == nil && f.CategoryID == nil && f.From != nil && f.To != nil
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/transactions/export?format=csv&from=2024-05-01&to=2024-05-31", nil)
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.ExportHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "id,date,type,amount,currency,category,note,tags\n"+
+		"1,2024-05-01 09:30:00,expense,1234.50,EUR,Housing,\"Rent, May\",home;monthly\n"+
+		"2,2024-05-02 00:00:00,income,2500.00,EUR,Salary,,\n", rr.Body.String())
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestExportHandler_JSONLines(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockExport(mockDB, mock.Anything)
+
+	req := httptest.NewRequest(http.MethodGet, "/transactions/export?format=jsonl", nil)
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.ExportHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	dec := json.NewDecoder(rr.Body)
+	var lines []models.ExportedTransaction
+	for dec.More() {
+		var line models.ExportedTransaction
+		assert.NoError(t, dec.Decode(&line))
+		lines = append(lines, line)
+	}
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "Housing", lines[0].CategoryName)
+	assert.Equal(t, models.Money(250000), lines[1].Amount)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestExportHandler_XLSX(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockExport(mockDB, mock.Anything)
+
+	req := httptest.NewRequest(http.MethodGet, "/transactions/export?format=xlsx", nil)
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.ExportHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	body := rr.Body.Bytes()
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	assert.NoError(t, err)
+
+	var sheet []byte
+	for _, f := range archive.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, err := f.Open()
+			assert.NoError(t, err)
+			sheet, _ = io.ReadAll(rc)
+			rc.Close()
+		}
+	}
+	assert.Contains(t, string(sheet), `<c><v>1234.50</v></c>`)
+	assert.Contains(t, string(sheet), `<t xml:space="preserve">Housing</t>`)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestExportHandler_InvalidFormat(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	req := httptest.NewRequest(http.MethodGet, "/transactions/export?format=pdf", nil)
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.ExportHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
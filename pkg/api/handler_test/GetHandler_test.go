@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	models "github.com/ViktorOHJ/expense-tracker/pkg"
 	"github.com/ViktorOHJ/expense-tracker/pkg/api"
@@ -29,7 +30,11 @@ func TestGetHandler_Success(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 
-	mockDB.On("GetTransactions", mock.Anything, 1, mock.Anything, mock.Anything, mock.Anything, mock.Anything, 10, 0).
+	mockDB.On("GetTransactions", mock.Anything, 1, mock.MatchedBy(func(f models.TransactionFilter) bool {
+		return f.IsIncome != nil && *f.IsIncome && f.CategoryID != nil && *f.CategoryID == 1 &&
+			f.From != nil && f.From.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) &&
+			f.To != nil && f.To.Equal(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+	}), 10, 0).
 		Return([]*models.Transaction{
 			{ID: 1, Amount: 100, CategoryID: 1, UserID: 1},
 		}, nil)
@@ -131,7 +136,7 @@ func TestGetHandler_NoTransactions(t *testing.T) {
 	req = req.WithContext(ctx)
 	rr := httptest.NewRecorder()
 
-	mockDB.On("GetTransactions", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, 10, 0).
+	mockDB.On("GetTransactions", mock.Anything, mock.Anything, mock.Anything, 10, 0).
 		Return([]*models.Transaction{}, nil)
 
 	s.GetHandler(rr, req)
@@ -189,10 +194,49 @@ func TestGetHandler_DBError(t *testing.T) {
 	req = req.WithContext(ctx)
 	rr := httptest.NewRecorder()
 
-	mockDB.On("GetTransactions", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, 10, 0).
+	mockDB.On("GetTransactions", mock.Anything, mock.Anything, mock.Anything, 10, 0).
 		Return(nil, assert.AnError)
 
 	s.GetHandler(rr, req)
 
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 }
+
+func TestGetHandler_TagFilter(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+
+	mockDB.On("GetTransactions", mock.Anything, 1, mock.MatchedBy(func(f models.TransactionFilter) bool {
+		return assert.ObjectsAreEqual([]string{"business", "reimbursable"}, f.Tags) && f.AllTags
+	}), 10, 0).
+		Return([]*models.Transaction{
+			{ID: 1, Amount: 100, CategoryID: 1, UserID: 1, Tags: []string{"business", "reimbursable"}},
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/transactions?tags=Business,reimbursable&tag_mode=all&limit=10&offset=1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr := httptest.NewRecorder()
+
+	s.GetHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/transactions?tags=business&tag_mode=some&limit=10&offset=1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr = httptest.NewRecorder()
+
+	s.GetHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "tag_mode must be 'any' or 'all'", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
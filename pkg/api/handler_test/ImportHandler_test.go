@@ -0,0 +1,122 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newImportRequest(t *testing.T, csv string, fields map[string]string) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		assert.NoError(t, writer.WriteField(name, value))
+	}
+	part, err := writer.CreateFormFile("file", "statement.csv")
+	assert.NoError(t, err)
+	part.Write([]byte(csv))
+	assert.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/import/csv", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	return req.WithContext(ctx)
+}
+
+func TestImportCSVHandler_DryRunPreview(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	csv := "Datum;Betrag;Verwendungszweck;Kategorie\n" +
+		"17.05.2024;-1 234,50;Miete;Wohnen\n" +
+		"18.05.2024;2500,00;Gehalt;Lohn\n" +
+		"19.05.2024;abc;Kaffee;Essen\n"
+	mapping := `{"date": "Datum", "date_format": "02.01.2006", "amount": "Betrag", "decimal_comma": true,
+		"note": "Verwendungszweck", "category": "Kategorie", "delimiter": ";"}`
+
+	mockDB.On("ImportTransactions", mock.Anything, 1, mock.MatchedBy(func(rows []models.ImportRow) bool {
+		if len(rows) != 3 {
+			return false
+		}
+		rent, salary, invalid := rows[0], rows[1], rows[2]
+		return rent.Status == models.ImportRowNew && !rent.Transaction.IsIncome && rent.Transaction.Amount == 123450 &&
+			rent.Transaction.OccurredAt.Equal(time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)) &&
+			rent.Transaction.Note == "Miete" && rent.CategoryName == "Wohnen" &&
+			salary.Transaction.IsIncome && salary.Transaction.Amount == 250000 &&
+			invalid.Status == models.ImportRowInvalid && invalid.Line == 4
+	}), models.ImportOptions{DryRun: true, CreateCategories: true}).
+		Return(models.ImportResult{DryRun: true, Total: 3, Imported: 2, Invalid: 1}, nil)
+
+	req := newImportRequest(t, csv, map[string]string{"mapping": mapping, "dry_run": "true", "create_categories": "true"})
+	rr := httptest.NewRecorder()
+
+	s.ImportCSVHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp models.SuccessResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "import preview", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestImportCSVHandler_InvalidRows(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("ImportTransactions", mock.Anything, 1, mock.Anything, models.ImportOptions{}).
+		Return(models.ImportResult{Total: 1, Invalid: 1}, db.ErrImportInvalid)
+
+	csv := "date,amount,category\n2024-05-17,-12.00,Unknown\n"
+	req := newImportRequest(t, csv, map[string]string{"mapping": `{"date": "date", "amount": "amount", "category": "category"}`})
+	rr := httptest.NewRecorder()
+
+	s.ImportCSVHandler(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"invalid":1`)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestImportCSVHandler_MissingColumn(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	csv := "date,amount,category\n2024-05-17,-12.00,Food\n"
+	req := newImportRequest(t, csv, map[string]string{"mapping": `{"date": "date", "amount": "sum", "category": "category"}`})
+	rr := httptest.NewRecorder()
+
+	s.ImportCSVHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "column 'sum' not found in file", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
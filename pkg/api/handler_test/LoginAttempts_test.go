@@ -0,0 +1,149 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func loginRequest(email, password string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/auth/login",
+		bytes.NewReader([]byte(`{"email": "`+email+`", "password": "`+password+`"}`)))
+	req.RemoteAddr = "203.0.113.7:51234"
+	return req
+}
+
+func TestLoginHandler_UnknownEmail(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService())
+
+	mockDB.On("GetUserByEmail", mock.Anything, "Nobody@Example.com").Return(models.User{}, db.ErrNotFound)
+	// Попытки считаются по адресу в нижнем регистре и IP клиента
+	mockDB.On("GetLoginFailures", mock.Anything, "nobody@example.com", "203.0.113.7", mock.Anything).
+		Return(models.LoginFailures{}, nil)
+	mockDB.On("RecordLoginAttempt", mock.Anything, mock.MatchedBy(func(a *models.LoginAttempt) bool {
+		return a.UserID == nil && a.Email == "nobody@example.com" && a.IP == "203.0.113.7" &&
+			!a.Success && a.Reason == models.LoginInvalidCredentials
+	})).Return(nil)
+
+	rr := httptest.NewRecorder()
+	s.LoginHandler(rr, loginRequest("Nobody@Example.com", "secret123"))
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), "invalid credentials")
+	mockDB.AssertExpectations(t)
+}
+
+func TestLoginHandler_Throttled(t *testing.T) {
+	mockDB := new(mocks.DB)
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), passwordService)
+
+	hash, err := passwordService.HashPassword("secret123")
+	assert.NoError(t, err)
+	mockDB.On("GetUserByEmail", mock.Anything, "test@example.com").
+		Return(models.User{ID: 1, Email: "test@example.com", Password: hash, EmailVerified: true}, nil)
+	// После 10 неудач аккаунт заблокирован на 15 минут даже для верного пароля
+	mockDB.On("GetLoginFailures", mock.Anything, "test@example.com", mock.Anything, mock.Anything).
+		Return(models.LoginFailures{Account: 10, AccountLast: time.Now().Add(-time.Minute)}, nil)
+	mockDB.On("RecordLoginAttempt", mock.Anything, mock.MatchedBy(func(a *models.LoginAttempt) bool {
+		return *a.UserID == 1 && a.Reason == models.LoginThrottled
+	})).Return(nil)
+
+	rr := httptest.NewRecorder()
+	s.LoginHandler(rr, loginRequest("test@example.com", "secret123"))
+
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	retryAfter, err := strconv.Atoi(rr.Header().Get("Retry-After"))
+	assert.NoError(t, err)
+	assert.InDelta(t, 14*60, retryAfter, 2)
+	mockDB.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything)
+	mockDB.AssertExpectations(t)
+}
+
+func TestLoginHandler_BackoffExpired(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService())
+
+	mockDB.On("GetUserByEmail", mock.Anything, "test@example.com").Return(models.User{}, db.ErrNotFound)
+	mockDB.On("RecordLoginAttempt", mock.Anything, mock.Anything).Return(nil)
+
+	// Пятая неудача даёт задержку 4 секунды: через 2 секунды вход ещё закрыт, через 5 — открыт
+	mockDB.On("GetLoginFailures", mock.Anything, "test@example.com", mock.Anything, mock.Anything).
+		Return(models.LoginFailures{Account: 5, AccountLast: time.Now().Add(-2 * time.Second)}, nil).Once()
+	rr := httptest.NewRecorder()
+	s.LoginHandler(rr, loginRequest("test@example.com", "wrong"))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+
+	mockDB.On("GetLoginFailures", mock.Anything, "test@example.com", mock.Anything, mock.Anything).
+		Return(models.LoginFailures{Account: 5, AccountLast: time.Now().Add(-5 * time.Second)}, nil).Once()
+	rr = httptest.NewRecorder()
+	s.LoginHandler(rr, loginRequest("test@example.com", "wrong"))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestLoginHandler_ThrottledByIP(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService(), api.WithTrustProxy(true))
+
+	mockDB.On("GetUserByEmail", mock.Anything, "test@example.com").Return(models.User{}, db.ErrNotFound)
+	// За прокси берётся последний адрес X-Forwarded-For, подставленный клиентом адрес игнорируется
+	mockDB.On("GetLoginFailures", mock.Anything, "test@example.com", "198.51.100.4", mock.Anything).
+		Return(models.LoginFailures{IP: 100, IPLast: time.Now()}, nil)
+	mockDB.On("RecordLoginAttempt", mock.Anything, mock.Anything).Return(nil)
+
+	req := loginRequest("test@example.com", "secret123")
+	req.Header.Set("X-Forwarded-For", "10.0.0.1, 198.51.100.4")
+	rr := httptest.NewRecorder()
+	s.LoginHandler(rr, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	mockDB.AssertExpectations(t)
+}
+
+func TestLoginAttemptsHandler(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService())
+
+	userID := 1
+	mockDB.On("GetLoginAttempts", mock.Anything, 1, 20, 20).Return([]models.LoginAttempt{
+		{ID: 2, UserID: &userID, Email: "test@example.com", IP: "203.0.113.7", Success: true, Reason: models.LoginSucceeded},
+		{ID: 1, UserID: &userID, Email: "test@example.com", IP: "198.51.100.4", Reason: models.LoginInvalidCredentials},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/login-attempts?limit=20&offset=2", nil)
+	ctx := context.WithValue(req.Context(), api.UserContextKey, &auth.Claims{UserID: 1})
+	rr := httptest.NewRecorder()
+	s.LoginAttemptsHandler(rr, req.WithContext(ctx))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp struct {
+		Data []models.LoginAttempt `json:"data"`
+	}
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Data, 2)
+	assert.Equal(t, models.LoginInvalidCredentials, resp.Data[1].Reason)
+
+	req = httptest.NewRequest(http.MethodGet, "/auth/login-attempts?limit=1000", nil)
+	rr = httptest.NewRecorder()
+	s.LoginAttemptsHandler(rr, req.WithContext(ctx))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
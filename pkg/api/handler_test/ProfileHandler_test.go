@@ -0,0 +1,114 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateProfileHandler_BaseCurrency(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("UpdateUserSettings", mock.Anything, 1, mock.MatchedBy(func(settings *models.UserSettings) bool {
+		return settings.BaseCurrency != nil && *settings.BaseCurrency == "EUR"
+	})).Return(models.User{ID: 1, Email: "test@example.com", BaseCurrency: "EUR"}, nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/profile", bytes.NewReader([]byte(`{"base_currency": "eur"}`)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.ProfileHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp models.SuccessResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "profile updated successfully", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateProfileHandler_InvalidCurrency(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	req := httptest.NewRequest(http.MethodPatch, "/profile", bytes.NewReader([]byte(`{"base_currency": "euro"}`)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.UpdateProfileHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "base_currency must be a 3-letter ISO 4217 code", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateProfileHandler_TimeZone(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+
+	mockDB.On("UpdateUserSettings", mock.Anything, 1, mock.MatchedBy(func(settings *models.UserSettings) bool {
+		return settings.TimeZone != nil && *settings.TimeZone == "Europe/Berlin" && settings.BaseCurrency == nil
+	})).Return(models.User{ID: 1, Email: "test@example.com", BaseCurrency: "EUR", TimeZone: "Europe/Berlin"}, nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/profile", bytes.NewReader([]byte(`{"time_zone": " Europe/Berlin "}`)))
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr := httptest.NewRecorder()
+
+	s.ProfileHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data models.User `json:"data"`
+	}
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", resp.Data.TimeZone)
+
+	for _, body := range []string{`{"time_zone": "Mars/Olympus"}`, `{"time_zone": "Local"}`, `{"time_zone": ""}`} {
+		req = httptest.NewRequest(http.MethodPatch, "/profile", bytes.NewReader([]byte(body)))
+		req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+		rr = httptest.NewRecorder()
+
+		s.ProfileHandler(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+
+		var errResp models.ErrorResponse
+		err = json.NewDecoder(rr.Body).Decode(&errResp)
+		assert.NoError(t, err)
+		assert.Equal(t, "time_zone must be an IANA time zone name", errResp.Message)
+	}
+
+	mockDB.AssertExpectations(t)
+}
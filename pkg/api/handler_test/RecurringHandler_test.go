@@ -0,0 +1,140 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddRecurringRuleHandler_FirstOccurrence(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	// Старт 20 января, платёж 5-го числа: первое повторение — 5 февраля
+	mockDB.On("CheckCategory", mock.Anything, 1, 2).Return(true, nil)
+	mockDB.On("AddRecurringRule", mock.Anything, 1, mock.MatchedBy(func(r *models.RecurringRule) bool {
+		return r.Frequency == models.FrequencyMonthly && r.Interval == 1 && r.Amount == 120000 &&
+			r.StartDate.Equal(time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC)) &&
+			r.NextDate != nil && r.NextDate.Equal(r.StartDate.Time)
+	})).Return(models.RecurringRule{ID: 1, UserID: 1, Amount: 120000, Frequency: models.FrequencyMonthly}, nil)
+
+	body := `{"category_id": 2, "amount": 1200, "frequency": "monthly", "day_of_month": 5, "start_date": "2024-01-20"}`
+	req := httptest.NewRequest(http.MethodPost, "/recurring", bytes.NewReader([]byte(body)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.RecurringRulesHandler(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var resp models.SuccessResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "recurring rule added successfully", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestAddRecurringRuleHandler_Validation(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	tests := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{"Zero amount", `{"category_id": 2, "amount": 0, "frequency": "daily"}`, "amount must be greater than 0"},
+		{"Unknown frequency", `{"category_id": 2, "amount": 10, "frequency": "hourly"}`, "frequency must be one of 'daily', 'weekly', 'monthly', 'yearly'"},
+		{"Day of month for weekly", `{"category_id": 2, "amount": 10, "frequency": "weekly", "day_of_month": 3}`, "day_of_month is only supported for monthly and yearly rules"},
+		{"Day of month out of range", `{"category_id": 2, "amount": 10, "frequency": "monthly", "day_of_month": 32}`, "day_of_month must be between 1 and 31"},
+		{"Negative interval", `{"category_id": 2, "amount": 10, "frequency": "daily", "interval": -1}`, "interval must be greater than 0"},
+		{"Zero max occurrences", `{"category_id": 2, "amount": 10, "frequency": "daily", "max_occurrences": 0}`, "max_occurrences must be greater than 0"},
+		{"End before start", `{"category_id": 2, "amount": 10, "frequency": "daily", "start_date": "2024-05-10", "end_date": "2024-05-01"}`, "end_date must not be before start_date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/recurring", bytes.NewReader([]byte(tt.body)))
+			claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+			ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+
+			s.AddRecurringRuleHandler(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+			var resp models.ErrorResponse
+			err := json.NewDecoder(rr.Body).Decode(&resp)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.message, resp.Message)
+		})
+	}
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateRecurringRuleHandler_NotFound(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("UpdateRecurringRule", mock.Anything, 1, 3, mock.MatchedBy(func(u *models.RecurringRuleUpdate) bool {
+		return u.Amount != nil && *u.Amount == 5000
+	})).Return(models.RecurringRule{}, db.ErrNotFound)
+
+	req := httptest.NewRequest(http.MethodPatch, "/recurring/3", bytes.NewReader([]byte(`{"amount": 50}`)))
+	req.SetPathValue("id", "3")
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.RecurringRuleHandler(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestDeleteRecurringRuleHandler_Success(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("DeleteRecurringRule", mock.Anything, 1, 4).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/recurring/4", nil)
+	req.SetPathValue("id", "4")
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.RecurringRuleHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,120 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCategoryReportHandler_Success(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	parentID := 1
+	report := models.CategoryReport{
+		Currency:     "EUR",
+		TotalExpense: 50000,
+		Categories: []models.CategoryReportRow{
+			{CategoryID: 1, Name: "Food", Expense: 30000, ExpenseShare: 60},
+			{CategoryID: 2, Name: "Groceries", ParentID: &parentID, Expense: 20000, ExpenseShare: 40},
+		},
+	}
+	mockDB.On("GetCategoryReport", mock.Anything, 1, mock.MatchedBy(func(f models.TransactionFilter) bool {
+		return f.IsIncome != nil && !*f.IsIncome && f.From != nil && f.To != nil
+	})).Return(report, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/reports/categories?type=false&from=2024-01-01&to=2024-12-31", nil)
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.CategoryReportHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data models.CategoryReport `json:"data"`
+	}
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, report, resp.Data)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestTimeSeriesHandler_Success(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	series := models.TimeSeries{
+		Currency: "EUR",
+		Interval: "week",
+		Points: []models.TimeSeriesPoint{
+			{Period: models.NewDate(time.Date(2024, 4, 29, 0, 0, 0, 0, time.UTC)), Expense: 1500, Balance: -1500},
+			{Period: models.NewDate(time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC))},
+		},
+	}
+	mockDB.On("GetTimeSeries", mock.Anything, 1, mock.MatchedBy(func(f models.TransactionFilter) bool {
+		return f.CategoryID != nil && *f.CategoryID == 3
+	}), "week").Return(series, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/reports/timeseries?interval=week&category_id=3&from=2024-05-01&to=2024-05-10", nil)
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.TimeSeriesHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `{"period":"2024-05-06","income":0.00,"expense":0.00,"balance":0.00}`)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestTimeSeriesHandler_Validation(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+
+	cases := map[string]string{
+		"/reports/timeseries?interval=month":                               "from and to parameters are required",
+		"/reports/timeseries?interval=hour&from=2024-01-01&to=2024-12-31":  "interval must be one of 'day', 'week', 'month', 'year'",
+		"/reports/timeseries?interval=day&from=2000-01-01&to=2024-12-31":   "date range is too long for interval 'day': at most 1000 points",
+		"/reports/timeseries?interval=month&from=2024-12-31&to=2024-01-01": "to date must be after from date",
+	}
+	for url, message := range cases {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+		rr := httptest.NewRecorder()
+
+		s.TimeSeriesHandler(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, url)
+
+		var resp models.ErrorResponse
+		err := json.NewDecoder(rr.Body).Decode(&resp)
+		assert.NoError(t, err)
+		assert.Equal(t, message, resp.Message, url)
+	}
+
+	mockDB.AssertExpectations(t)
+}
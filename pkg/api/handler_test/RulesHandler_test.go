@@ -0,0 +1,137 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func coffeeRule() models.CategoryRule {
+	category := 7
+	expense := false
+	return models.CategoryRule{ID: 2, UserID: 1, Name: "Coffee", NoteContains: "coffee", IsIncome: &expense,
+		CategoryID: &category, Tags: []string{"cafe"}}
+}
+
+func TestAddRuleHandler(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+
+	mockDB.On("CheckCategory", mock.Anything, 1, 7).Return(true, nil)
+	mockDB.On("AddCategoryRule", mock.Anything, 1, mock.MatchedBy(func(r *models.CategoryRule) bool {
+		return r.Name == "Coffee" && r.NoteRegex == "(?i)starbucks|costa" && r.MaxAmount != nil && *r.MaxAmount == 1500 &&
+			*r.CategoryID == 7 && assert.ObjectsAreEqual([]string{"cafe"}, r.Tags)
+	})).Return(coffeeRule(), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/rules", bytes.NewReader([]byte(
+		`{"name": " Coffee ", "note_regex": "(?i)starbucks|costa", "max_amount": 15, "category_id": 7, "tags": ["Cafe"]}`)))
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr := httptest.NewRecorder()
+
+	s.AddRuleHandler(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	for body, message := range map[string]string{
+		`{"name": "Anything", "category_id": 7}`:                               "rule must have at least one condition",
+		`{"name": "Broken", "note_regex": "(unclosed", "category_id": 7}`:      "invalid note_regex: error parsing regexp: missing closing ): `(unclosed`",
+		`{"name": "Nothing", "note_contains": "rent"}`:                         "rule must assign a category or tags",
+		`{"name": "Range", "min_amount": 20, "max_amount": 10, "tags": ["x"]}`: "min_amount must not exceed max_amount",
+		`{"name": "Huge", "max_amount": 100000000, "tags": ["x"]}`:             "amount bounds must not exceed 99999999.99",
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/rules", bytes.NewReader([]byte(body)))
+		req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+		rr := httptest.NewRecorder()
+
+		s.AddRuleHandler(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+
+		var resp models.ErrorResponse
+		err := json.NewDecoder(rr.Body).Decode(&resp)
+		assert.NoError(t, err)
+		assert.Equal(t, message, resp.Message, body)
+	}
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestApplyRulesHandler_DryRun(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	transferID := 4
+	mockDB.On("GetCategoryRules", mock.Anything, 1).Return([]models.CategoryRule{coffeeRule()}, nil)
+	mockDB.On("GetTransactions", mock.Anything, 1, mock.Anything, 500, 0).Return([]*models.Transaction{
+		{ID: 10, Amount: 450, CategoryID: 3, Note: "Coffee at the station", Tags: []string{"work"}},
+		{ID: 11, Amount: 450, CategoryID: 7, Note: "coffee beans", Tags: []string{"cafe"}},
+		{ID: 12, Amount: 9000, CategoryID: 3, Note: "Groceries"},
+		{ID: 13, Amount: 450, CategoryID: 3, Note: "coffee money", TransferID: &transferID},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/rules/apply?from=2024-01-01", nil)
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, &auth.Claims{UserID: 1, Email: "test@example.com"}))
+	rr := httptest.NewRecorder()
+
+	s.ApplyRulesHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Message string                 `json:"message"`
+		Data    models.RuleApplyResult `json:"data"`
+	}
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "rules dry run", resp.Message)
+	assert.True(t, resp.Data.DryRun)
+	assert.Equal(t, 4, resp.Data.Scanned)
+	assert.Equal(t, []models.RuleMatch{
+		{TransactionID: 10, RuleID: 2, Note: "Coffee at the station", OldCategoryID: 3, CategoryID: 7, AddedTags: []string{"cafe"}},
+	}, resp.Data.Changes)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestApplyRulesHandler_Apply(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("GetCategoryRules", mock.Anything, 1).Return([]models.CategoryRule{coffeeRule()}, nil)
+	mockDB.On("GetTransactions", mock.Anything, 1, mock.Anything, 500, 0).Return([]*models.Transaction{
+		{ID: 10, Amount: 450, CategoryID: 3, Note: "Coffee at the station", Tags: []string{"work"}},
+	}, nil)
+	mockDB.On("ApplyRuleMatches", mock.Anything, 1, mock.MatchedBy(func(m []models.RuleMatch) bool {
+		return len(m) == 1 && m[0].TransactionID == 10 && m[0].CategoryID == 7 &&
+			assert.ObjectsAreEqual([]string{"work", "cafe"}, m[0].Tags)
+	})).Return(1, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/rules/apply?apply=true", nil)
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, &auth.Claims{UserID: 1, Email: "test@example.com"}))
+	rr := httptest.NewRecorder()
+
+	s.ApplyRulesHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"applied":1`)
+
+	mockDB.AssertExpectations(t)
+}
@@ -116,3 +116,90 @@ func TestSummaryHandler_DBError(t *testing.T) {
 
 	mockDB.AssertExpectations(t)
 }
+
+func TestSummaryHandler_MultiCurrency(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	expectedSummary := models.Summary{
+		TotalIncome:  500000,
+		TotalExpense: 32150,
+		Balance:      467850,
+		Currency:     "EUR",
+		ByCurrency: []models.CurrencySummary{
+			{Currency: "EUR", TotalIncome: 500000, TotalExpense: 20000, Balance: 480000},
+			{Currency: "PLN", TotalExpense: 52000, Balance: -52000},
+		},
+		MissingRates: []string{"USD"},
+	}
+
+	mockDB.On("GetSummary", mock.Anything, 1, mock.Anything, mock.Anything).Return(expectedSummary, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/summary?from=2024-01-01&to=2024-12-31", nil)
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.SummaryHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var actualResp models.SuccessResponse
+	err := json.NewDecoder(rr.Body).Decode(&actualResp)
+	assert.NoError(t, err)
+
+	dataBytes, err := json.Marshal(actualResp.Data)
+	assert.NoError(t, err)
+
+	var actualSummary models.Summary
+	err = json.Unmarshal(dataBytes, &actualSummary)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSummary, actualSummary)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestSummaryHandler_ByTag(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	expectedSummary := models.Summary{
+		TotalIncome:  100000,
+		TotalExpense: 45000,
+		Balance:      55000,
+		Currency:     "EUR",
+		ByCurrency: []models.CurrencySummary{
+			{Currency: "EUR", TotalIncome: 100000, TotalExpense: 45000, Balance: 55000},
+		},
+		ByTag: []models.TagSummary{
+			{Tag: "business", TotalIncome: 100000, TotalExpense: 15000},
+			{Tag: "vacation-2026", TotalExpense: 30000},
+		},
+	}
+
+	mockDB.On("GetSummary", mock.Anything, 1, mock.Anything, mock.Anything).Return(expectedSummary, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/summary?from=2024-01-01&to=2024-12-31", nil)
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.SummaryHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data models.Summary `json:"data"`
+	}
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSummary.ByTag, resp.Data.ByTag)
+
+	mockDB.AssertExpectations(t)
+}
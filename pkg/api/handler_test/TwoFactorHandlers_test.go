@@ -0,0 +1,249 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+func TestLoginHandler_TwoFactorChallenge(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	hash, err := passwordService.HashPassword("secret123")
+	assert.NoError(t, err)
+	mockDB.On("GetUserByEmail", mock.Anything, "test@example.com").
+		Return(models.User{ID: 1, Email: "test@example.com", Password: hash, EmailVerified: true, TwoFactorEnabled: true}, nil)
+	mockDB.On("GetLoginFailures", mock.Anything, "test@example.com", mock.Anything, mock.Anything).
+		Return(models.LoginFailures{}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/login",
+		bytes.NewReader([]byte(`{"email": "test@example.com", "password": "secret123"}`)))
+	rr := httptest.NewRecorder()
+
+	s.LoginHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp struct {
+		Data models.LoginChallenge `json:"data"`
+	}
+	err = json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.True(t, resp.Data.TwoFactorRequired)
+
+	// Сессия не выдаётся, а challenge-токен не годится как access-токен
+	mockDB.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything)
+	_, err = jwtService.ValidateToken(resp.Data.ChallengeToken)
+	assert.Error(t, err)
+	claims, err := jwtService.ValidateChallengeToken(resp.Data.ChallengeToken)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, claims.UserID)
+}
+
+func TestTwoFactorVerifyHandler(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	s := api.NewServer(mockDB, jwtService, auth.NewPasswordService())
+
+	challenge, _, err := jwtService.IssueChallengeToken(1, "test@example.com")
+	assert.NoError(t, err)
+	claims, err := jwtService.ValidateChallengeToken(challenge)
+	assert.NoError(t, err)
+	code, err := totp.GenerateCode(testTOTPSecret, time.Now())
+	assert.NoError(t, err)
+
+	mockDB.On("IsTokenRevoked", mock.Anything, claims.ID).Return(false, nil)
+	mockDB.On("GetTwoFactor", mock.Anything, 1).Return(models.TwoFactor{Secret: testTOTPSecret, Enabled: true}, nil)
+	mockDB.On("UseTOTPStep", mock.Anything, 1, mock.AnythingOfType("int64")).Return(nil).Once()
+	mockDB.On("RevokeSession", mock.Anything, 1, claims.ID, mock.Anything).Return(nil)
+	mockDB.On("GetUserByID", mock.Anything, 1).
+		Return(models.User{ID: 1, Email: "test@example.com", EmailVerified: true, TwoFactorEnabled: true}, nil)
+	mockDB.On("CreateRefreshToken", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+	mockDB.On("GetLoginFailures", mock.Anything, "test@example.com", mock.Anything, mock.Anything).
+		Return(models.LoginFailures{}, nil)
+	mockDB.On("RecordLoginAttempt", mock.Anything, mock.MatchedBy(func(a *models.LoginAttempt) bool {
+		return a.Success
+	})).Return(nil).Once()
+	mockDB.On("RecordLoginAttempt", mock.Anything, mock.MatchedBy(func(a *models.LoginAttempt) bool {
+		return a.Reason == models.LoginInvalidCode
+	})).Return(nil).Once()
+
+	body := fmt.Sprintf(`{"challenge_token": %q, "code": %q}`, challenge, code)
+	req := httptest.NewRequest(http.MethodPost, "/auth/2fa/verify", bytes.NewReader([]byte(body)))
+	rr := httptest.NewRecorder()
+	s.TwoFactorVerifyHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp struct {
+		Data models.AuthResponse `json:"data"`
+	}
+	err = json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	_, err = jwtService.ValidateToken(resp.Data.Token)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, resp.Data.RefreshToken)
+
+	// Тот же код второй раз не принимается
+	mockDB.On("UseTOTPStep", mock.Anything, 1, mock.AnythingOfType("int64")).Return(db.ErrTokenReused)
+	req = httptest.NewRequest(http.MethodPost, "/auth/2fa/verify", bytes.NewReader([]byte(body)))
+	rr = httptest.NewRecorder()
+	s.TwoFactorVerifyHandler(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestTwoFactorVerifyHandler_RecoveryCode(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	s := api.NewServer(mockDB, jwtService, auth.NewPasswordService())
+
+	challenge, _, err := jwtService.IssueChallengeToken(1, "test@example.com")
+	assert.NoError(t, err)
+
+	mockDB.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil)
+	mockDB.On("GetTwoFactor", mock.Anything, 1).Return(models.TwoFactor{Secret: testTOTPSecret, Enabled: true}, nil)
+	mockDB.On("UseRecoveryCode", mock.Anything, 1, auth.HashRecoveryCode("abcd-efgh-ijkl-mnop")).Return(db.ErrNotFound)
+	mockDB.On("GetLoginFailures", mock.Anything, "test@example.com", mock.Anything, mock.Anything).
+		Return(models.LoginFailures{}, nil)
+	mockDB.On("RecordLoginAttempt", mock.Anything, mock.MatchedBy(func(a *models.LoginAttempt) bool {
+		return a.Reason == models.LoginInvalidCode
+	})).Return(nil)
+
+	// Код восстановления сравнивается без учёта регистра и дефисов
+	body := fmt.Sprintf(`{"challenge_token": %q, "code": "ABCDEFGHIJKLMNOP"}`, challenge)
+	req := httptest.NewRequest(http.MethodPost, "/auth/2fa/verify", bytes.NewReader([]byte(body)))
+	rr := httptest.NewRecorder()
+	s.TwoFactorVerifyHandler(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// Access-токен вместо challenge-токена не принимается
+	access, err := jwtService.GenerateToken(1, "test@example.com")
+	assert.NoError(t, err)
+	body = fmt.Sprintf(`{"challenge_token": %q, "code": "123456"}`, access)
+	req = httptest.NewRequest(http.MethodPost, "/auth/2fa/verify", bytes.NewReader([]byte(body)))
+	rr = httptest.NewRecorder()
+	s.TwoFactorVerifyHandler(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestTwoFactorEnableHandler(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService())
+
+	mockDB.On("GetTwoFactor", mock.Anything, 1).Return(models.TwoFactor{Secret: testTOTPSecret}, nil)
+	var stored []string
+	mockDB.On("EnableTOTP", mock.Anything, 1, mock.AnythingOfType("int64"), mock.MatchedBy(func(hashes []string) bool {
+		stored = hashes
+		return len(hashes) == auth.RecoveryCodeCount
+	})).Return(nil)
+
+	code, err := totp.GenerateCode(testTOTPSecret, time.Now())
+	assert.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/auth/2fa/enable", bytes.NewReader([]byte(fmt.Sprintf(`{"code": %q}`, code))))
+	ctx := context.WithValue(req.Context(), api.UserContextKey, &auth.Claims{UserID: 1, Email: "test@example.com"})
+	rr := httptest.NewRecorder()
+	s.TwoFactorEnableHandler(rr, req.WithContext(ctx))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp struct {
+		Data models.RecoveryCodes `json:"data"`
+	}
+	err = json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Data.RecoveryCodes, auth.RecoveryCodeCount)
+	// Коды показываются клиенту, а в БД уходят только их хеши
+	assert.Equal(t, auth.HashRecoveryCode(resp.Data.RecoveryCodes[0]), stored[0])
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/2fa/enable", bytes.NewReader([]byte(`{"code": "abcdef"}`)))
+	rr = httptest.NewRecorder()
+	s.TwoFactorEnableHandler(rr, req.WithContext(ctx))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestTwoFactorQRHandler(t *testing.T) {
+	mockDB := new(mocks.DB)
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), auth.NewPasswordService())
+
+	mockDB.On("GetTwoFactor", mock.Anything, 1).Return(models.TwoFactor{Secret: testTOTPSecret}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/2fa/qr", nil)
+	ctx := context.WithValue(req.Context(), api.UserContextKey, &auth.Claims{UserID: 1, Email: "test@example.com"})
+	rr := httptest.NewRecorder()
+	s.TwoFactorQRHandler(rr, req.WithContext(ctx))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+	assert.True(t, bytes.HasPrefix(rr.Body.Bytes(), []byte("\x89PNG")))
+}
+
+func TestTwoFactorDisableHandler_Throttled(t *testing.T) {
+	mockDB := new(mocks.DB)
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, auth.NewJWTService("test-secret"), passwordService)
+
+	hash, err := passwordService.HashPassword("secret123")
+	assert.NoError(t, err)
+	mockDB.On("GetUserByID", mock.Anything, 1).
+		Return(models.User{ID: 1, Email: "test@example.com", Password: hash, EmailVerified: true, TwoFactorEnabled: true}, nil)
+	mockDB.On("GetTwoFactor", mock.Anything, 1).Return(models.TwoFactor{Secret: testTOTPSecret, Enabled: true}, nil)
+	mockDB.On("UseRecoveryCode", mock.Anything, 1, mock.Anything).Return(db.ErrNotFound)
+
+	disable := func(password, code string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"password": %q, "code": %q}`, password, code)
+		req := httptest.NewRequest(http.MethodPost, "/auth/2fa/disable", bytes.NewReader([]byte(body)))
+		ctx := context.WithValue(req.Context(), api.UserContextKey, &auth.Claims{UserID: 1, Email: "test@example.com"})
+		rr := httptest.NewRecorder()
+		s.TwoFactorDisableHandler(rr, req.WithContext(ctx))
+		return rr
+	}
+
+	// Неверный пароль и неверный код считаются неудачными попытками входа
+	mockDB.On("GetLoginFailures", mock.Anything, "test@example.com", mock.Anything, mock.Anything).
+		Return(models.LoginFailures{}, nil).Twice()
+	mockDB.On("RecordLoginAttempt", mock.Anything, mock.MatchedBy(func(a *models.LoginAttempt) bool {
+		return *a.UserID == 1 && a.Reason == models.LoginInvalidCredentials
+	})).Return(nil).Once()
+	mockDB.On("RecordLoginAttempt", mock.Anything, mock.MatchedBy(func(a *models.LoginAttempt) bool {
+		return *a.UserID == 1 && a.Reason == models.LoginInvalidCode
+	})).Return(nil).Once()
+	assert.Equal(t, http.StatusUnauthorized, disable("wrong", "123456").Code)
+	assert.Equal(t, http.StatusUnauthorized, disable("secret123", "abcd-efgh-ijkl-mnop").Code)
+
+	// После блокировки не проверяются ни пароль, ни код
+	mockDB.On("GetLoginFailures", mock.Anything, "test@example.com", mock.Anything, mock.Anything).
+		Return(models.LoginFailures{Account: 10, AccountLast: time.Now()}, nil).Once()
+	mockDB.On("RecordLoginAttempt", mock.Anything, mock.MatchedBy(func(a *models.LoginAttempt) bool {
+		return a.Reason == models.LoginThrottled
+	})).Return(nil).Once()
+	rr := disable("secret123", "123456")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+
+	mockDB.AssertNotCalled(t, "DisableTOTP", mock.Anything, mock.Anything)
+	mockDB.AssertNumberOfCalls(t, "GetUserByID", 2)
+	mockDB.AssertNotCalled(t, "GetUserByEmail", mock.Anything, mock.Anything)
+	mockDB.AssertExpectations(t)
+}
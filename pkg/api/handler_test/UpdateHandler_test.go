@@ -0,0 +1,270 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/api"
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
+	"github.com/ViktorOHJ/expense-tracker/pkg/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateHandler_PartialUpdate(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	updated := models.Transaction{
+		ID:         1,
+		IsIncome:   false,
+		Amount:     12000,
+		CategoryID: 2,
+		UserID:     1,
+		Note:       "Lunch",
+		CreatedAt:  time.Date(2025, 7, 20, 0, 0, 0, 0, time.UTC),
+	}
+
+	mockDB.On("UpdateTransaction", mock.Anything, 1, 1, mock.MatchedBy(func(u *models.TransactionUpdate) bool {
+		return u.Amount != nil && *u.Amount == 12000 &&
+			u.Note == nil && u.CategoryID == nil && u.IsIncome == nil && u.CreatedAt == nil
+	})).Return(updated, nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/transaction/?id=1", bytes.NewReader([]byte(`{"amount": 120}`)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.DeleteGetHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var actualResp models.SuccessResponse
+	err := json.NewDecoder(rr.Body).Decode(&actualResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "transaction with id: 1 successfully updated", actualResp.Message)
+
+	dataBytes, err := json.Marshal(actualResp.Data)
+	assert.NoError(t, err)
+	var actualTransaction models.Transaction
+	err = json.Unmarshal(dataBytes, &actualTransaction)
+	assert.NoError(t, err)
+	assert.Equal(t, updated, actualTransaction)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateHandler_CategoryRevalidated(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("CheckCategory", mock.Anything, 1, 3).Return(false, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/transaction/?id=1", bytes.NewReader([]byte(`{"category_id": 3}`)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.UpdateHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var actualResp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&actualResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "category does not exist or access denied", actualResp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateHandler_InvalidAmount(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	req := httptest.NewRequest(http.MethodPatch, "/transaction/?id=1", bytes.NewReader([]byte(`{"amount": -5}`)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.UpdateHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var actualResp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&actualResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "amount must be greater than 0", actualResp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateHandler_NotFound(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("UpdateTransaction", mock.Anything, 1, 42, mock.Anything).Return(models.Transaction{}, db.ErrNotFound)
+
+	req := httptest.NewRequest(http.MethodPatch, "/transaction/?id=42", bytes.NewReader([]byte(`{"note": "typo fixed"}`)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.UpdateHandler(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	var actualResp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&actualResp)
+	assert.NoError(t, err)
+	assert.Equal(t, "transaction with id 42 not found or access denied", actualResp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateHandler_TransferTransaction(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	mockDB.On("UpdateTransaction", mock.Anything, 1, 8, mock.Anything).
+		Return(models.Transaction{}, db.ErrTransferTransaction)
+
+	req := httptest.NewRequest(http.MethodPatch, "/transaction/?id=8", bytes.NewReader([]byte(`{"amount": 120}`)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.DeleteGetHandler(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateHandler_SplitMismatch(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	// Сумма не меняется — сверка разбивки с ней остаётся за БД
+	mockDB.On("CheckCategory", mock.Anything, 1, 2).Return(true, nil)
+	mockDB.On("CheckCategory", mock.Anything, 1, 3).Return(true, nil)
+	mockDB.On("UpdateTransaction", mock.Anything, 1, 8, mock.MatchedBy(func(u *models.TransactionUpdate) bool {
+		return u.Amount == nil && u.Splits != nil && len(*u.Splits) == 2
+	})).Return(models.Transaction{}, db.ErrSplitMismatch)
+
+	req := httptest.NewRequest(http.MethodPatch, "/transaction/?id=8", bytes.NewReader([]byte(
+		`{"splits": [{"category_id": 2, "amount": 10}, {"category_id": 3, "amount": 15}]}`)))
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	ctx := context.WithValue(req.Context(), api.UserContextKey, claims)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	s.DeleteGetHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var actualResp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&actualResp)
+	assert.NoError(t, err)
+	assert.Equal(t, db.ErrSplitMismatch.Error(), actualResp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateHandler_OccurredAt(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	passwordService := auth.NewPasswordService()
+	s := api.NewServer(mockDB, jwtService, passwordService)
+
+	claims := &auth.Claims{UserID: 1, Email: "test@example.com"}
+	occurredAt := time.Date(2025, 7, 18, 0, 0, 0, 0, time.UTC)
+
+	mockDB.On("UpdateTransaction", mock.Anything, 1, 1, mock.MatchedBy(func(u *models.TransactionUpdate) bool {
+		return u.OccurredAt != nil && u.OccurredAt.Equal(occurredAt)
+	})).Return(models.Transaction{ID: 1, UserID: 1, OccurredAt: models.DateTime{Time: occurredAt}}, nil).Twice()
+
+	// Дата без времени и устаревшее поле created_at меняют дату операции
+	for _, body := range []string{`{"occurred_at": "2025-07-18"}`, `{"created_at": "2025-07-18T00:00:00Z"}`} {
+		req := httptest.NewRequest(http.MethodPatch, "/transaction/?id=1", bytes.NewReader([]byte(body)))
+		req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+		rr := httptest.NewRecorder()
+
+		s.DeleteGetHandler(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code, body)
+	}
+
+	req := httptest.NewRequest(http.MethodPatch, "/transaction/?id=1", bytes.NewReader([]byte(`{"occurred_at": "18.07.2025"}`)))
+	req = req.WithContext(context.WithValue(req.Context(), api.UserContextKey, claims))
+	rr := httptest.NewRecorder()
+
+	s.DeleteGetHandler(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var resp models.ErrorResponse
+	err := json.NewDecoder(rr.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "time must be a string in YYYY-MM-DD or RFC 3339 format", resp.Message)
+
+	mockDB.AssertExpectations(t)
+}
+
+func TestUpdateHandler_PathID(t *testing.T) {
+	mockDB := new(mocks.DB)
+	jwtService := auth.NewJWTService("test-secret")
+	s := api.NewServer(mockDB, jwtService, auth.NewPasswordService())
+	routes := s.InitRoutes()
+
+	token, err := jwtService.GenerateToken(1, "test@example.com")
+	assert.NoError(t, err)
+	mockDB.On("IsTokenRevoked", mock.Anything, mock.Anything).Return(false, nil)
+	mockDB.On("UpdateTransaction", mock.Anything, 1, 5, mock.Anything).Return(models.Transaction{ID: 5, UserID: 1, Amount: 12000}, nil)
+	mockDB.On("GetTransactionByID", mock.Anything, 1, 5).Return(models.Transaction{ID: 5, UserID: 1, Amount: 12000}, nil)
+
+	// id берётся из пути, прежняя форма ?id= тоже работает
+	for _, tc := range []struct{ method, target string }{
+		{http.MethodPatch, "/transaction/5"},
+		{http.MethodPut, "/transaction/5"},
+		{http.MethodPatch, "/transaction/?id=5"},
+		{http.MethodGet, "/transaction/5"},
+	} {
+		req := httptest.NewRequest(tc.method, tc.target, bytes.NewReader([]byte(`{"amount": 120.00}`)))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code, tc.method+" "+tc.target)
+	}
+
+	req := httptest.NewRequest(http.MethodPatch, "/transaction/abc", bytes.NewReader([]byte(`{"amount": 120.00}`)))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	routes.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockDB.AssertNumberOfCalls(t, "UpdateTransaction", 3)
+}
@@ -2,35 +2,35 @@ package api
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
 	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/ViktorOHJ/expense-tracker/pkg/db"
 )
 
 type contextKey string
 
 const UserContextKey contextKey = "user"
 
+// AuthMiddleware пропускает только запросы с access-токеном сессии. Им закрыты
+// управление аккаунтом и API-ключами: ключ не может выпустить сам себя.
 func (s *Server) AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			JsonError(w, http.StatusUnauthorized, "authorization header required")
+		token, ok := bearerToken(w, r)
+		if !ok {
 			return
 		}
-
-		// Проверяем формат "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			JsonError(w, http.StatusUnauthorized, "invalid authorization format")
+		if auth.IsAPIKey(token) {
+			JsonError(w, http.StatusForbidden, "api keys cannot access this endpoint")
 			return
 		}
 
-		token := parts[1]
-		claims, err := s.jwtService.ValidateToken(token)
-		if err != nil {
-			JsonError(w, http.StatusUnauthorized, "invalid token")
+		claims, ok := s.authenticateJWT(w, r, token)
+		if !ok {
 			return
 		}
 
@@ -40,6 +40,94 @@ func (s *Server) AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// ScopeMiddleware пропускает access-токен сессии без ограничений, а API-ключ —
+// только с областью доступа к resource: для GET достаточно чтения, для
+// остальных методов нужна запись
+func (s *Server) ScopeMiddleware(resource string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(w, r)
+		if !ok {
+			return
+		}
+
+		var claims *auth.Claims
+		if auth.IsAPIKey(token) {
+			key, err := s.db.UseAPIKey(r.Context(), auth.HashToken(token))
+			if errors.Is(err, db.ErrNotFound) {
+				JsonError(w, http.StatusUnauthorized, "invalid api key")
+				return
+			}
+			if errors.Is(err, db.ErrTokenExpired) {
+				JsonError(w, http.StatusUnauthorized, "api key has expired")
+				return
+			}
+			if err != nil {
+				JsonError(w, http.StatusInternalServerError, "error checking api key")
+				return
+			}
+
+			access := models.ScopeWrite
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				access = models.ScopeRead
+			}
+			if !hasScope(key.Scopes, resource, access) {
+				JsonError(w, http.StatusForbidden, fmt.Sprintf("api key lacks scope %s:%s", resource, access))
+				return
+			}
+			claims = &auth.Claims{UserID: key.UserID}
+		} else {
+			claims, ok = s.authenticateJWT(w, r, token)
+			if !ok {
+				return
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), UserContextKey, claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+}
+
+// bearerToken достаёт значение из заголовка "Authorization: Bearer <token>"
+func bearerToken(w http.ResponseWriter, r *http.Request) (string, bool) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		JsonError(w, http.StatusUnauthorized, "authorization header required")
+		return "", false
+	}
+
+	// Проверяем формат "Bearer <token>"
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		JsonError(w, http.StatusUnauthorized, "invalid authorization format")
+		return "", false
+	}
+	return parts[1], true
+}
+
+func (s *Server) authenticateJWT(w http.ResponseWriter, r *http.Request, token string) (*auth.Claims, bool) {
+	claims, err := s.jwtService.ValidateToken(token)
+	if err != nil {
+		JsonError(w, http.StatusUnauthorized, "invalid token")
+		return nil, false
+	}
+
+	// Токен без jti нельзя отозвать, такие токены не принимаются
+	if claims.ID == "" {
+		JsonError(w, http.StatusUnauthorized, "invalid token")
+		return nil, false
+	}
+	revoked, err := s.db.IsTokenRevoked(r.Context(), claims.ID)
+	if err != nil {
+		JsonError(w, http.StatusInternalServerError, "error checking token")
+		return nil, false
+	}
+	if revoked {
+		JsonError(w, http.StatusUnauthorized, "token has been revoked")
+		return nil, false
+	}
+	return claims, true
+}
+
 // Вспомогательная функция для получения пользователя из контекста
 func GetUserFromContext(ctx context.Context) *auth.Claims {
 	if claims, ok := ctx.Value(UserContextKey).(*auth.Claims); ok {
@@ -0,0 +1,25 @@
+package auth
+
+import "strings"
+
+// APIKeyPrefix отличает API-ключ от JWT в заголовке Authorization
+const APIKeyPrefix = "et_"
+
+// apiKeyVisibleChars — сколько символов ключа после префикса хранится открыто,
+// чтобы пользователь узнавал ключ в списке
+const apiKeyVisibleChars = 8
+
+// GenerateAPIKey возвращает новый API-ключ, его открытое начало для списка ключей и хеш для хранения
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	token, _, err := GenerateToken()
+	if err != nil {
+		return "", "", "", err
+	}
+	key = APIKeyPrefix + token
+	return key, key[:len(APIKeyPrefix)+apiKeyVisibleChars], HashToken(key), nil
+}
+
+// IsAPIKey сообщает, что значение из заголовка Authorization — API-ключ, а не JWT
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
@@ -1,14 +1,54 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	DefaultAccessTTL  = 15 * time.Minute
+	DefaultRefreshTTL = 30 * 24 * time.Hour
+	ChallengeTTL      = 5 * time.Minute
+
+	// challengeAudience отличает токен второго шага входа от access-токена
+	challengeAudience = "2fa-challenge"
+)
+
+// JWTService выпускает и проверяет JWT. Без асимметричных ключей токены подписываются
+// HS256 секретом; с ключами (WithSigningKeys) подписывает RS256/EdDSA ключ, а HS256
+// токены, выданные до перехода, принимаются, пока задан секрет и не прошёл legacyUntil.
 type JWTService struct {
-	secretKey []byte
+	secretKey   []byte
+	keys        []*SigningKey
+	legacyUntil time.Time
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+}
+
+// JWTOption настраивает необязательные параметры JWTService
+type JWTOption func(*JWTService)
+
+// WithSigningKeys задаёт асимметричные ключи. Подписывает ключ с закрытой частью,
+// активированный последним; остальные только проверяют подпись по kid и публикуются в JWKS.
+func WithSigningKeys(keys ...*SigningKey) JWTOption {
+	return func(j *JWTService) {
+		j.keys = append(j.keys, keys...)
+	}
+}
+
+// WithLegacyUntil ограничивает окно перехода: после until HS256 токены не принимаются,
+// даже если секрет ещё задан. Действует только вместе с WithSigningKeys.
+func WithLegacyUntil(until time.Time) JWTOption {
+	return func(j *JWTService) {
+		j.legacyUntil = until
+	}
 }
 
 type Claims struct {
@@ -17,33 +57,101 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-func NewJWTService(secretKey string) *JWTService {
-	return &JWTService{
-		secretKey: []byte(secretKey),
+func NewJWTService(secretKey string, opts ...JWTOption) *JWTService {
+	return NewJWTServiceWithTTL(secretKey, DefaultAccessTTL, DefaultRefreshTTL, opts...)
+}
+
+// NewJWTServiceWithTTL задаёт время жизни access- и refresh-токенов
+func NewJWTServiceWithTTL(secretKey string, accessTTL, refreshTTL time.Duration, opts ...JWTOption) *JWTService {
+	j := &JWTService{
+		secretKey:  []byte(secretKey),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+	for _, opt := range opts {
+		opt(j)
 	}
+	return j
+}
+
+func (j *JWTService) AccessTTL() time.Duration {
+	return j.accessTTL
+}
+
+func (j *JWTService) RefreshTTL() time.Duration {
+	return j.refreshTTL
 }
 
 func (j *JWTService) GenerateToken(userID int, email string) (string, error) {
+	jti, err := NewTokenID()
+	if err != nil {
+		return "", err
+	}
+	token, _, err := j.IssueAccessToken(userID, email, jti)
+	return token, err
+}
+
+// IssueAccessToken подписывает короткоживущий access-токен с идентификатором jti,
+// по которому токен можно отозвать до истечения срока
+func (j *JWTService) IssueAccessToken(userID int, email, jti string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(j.accessTTL)
 	claims := Claims{
 		UserID: userID,
 		Email:  email,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secretKey)
+	signed, err := j.sign(claims)
+	return signed, expiresAt, err
 }
 
 func (j *JWTService) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return j.secretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, j.verificationKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Токен второго шага входа не даёт доступа к API
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && !hasAudience(claims, challengeAudience) {
+		return claims, nil
+	}
+
+	return nil, fmt.Errorf("invalid token")
+}
+
+// IssueChallengeToken подписывает токен первого шага входа при включённой 2FA:
+// он подтверждает только пароль и обменивается на сессию через /auth/2fa/verify
+func (j *JWTService) IssueChallengeToken(userID int, email string) (string, time.Time, error) {
+	jti, err := NewTokenID()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	now := time.Now()
+	expiresAt := now.Add(ChallengeTTL)
+	claims := Claims{
+		UserID: userID,
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Audience:  jwt.ClaimStrings{challengeAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	signed, err := j.sign(claims)
+	return signed, expiresAt, err
+}
+
+// ValidateChallengeToken принимает только токены, выданные IssueChallengeToken
+func (j *JWTService) ValidateChallengeToken(tokenString string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, j.verificationKey, jwt.WithAudience(challengeAudience))
 
 	if err != nil {
 		return nil, err
@@ -55,3 +163,119 @@ func (j *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 
 	return nil, fmt.Errorf("invalid token")
 }
+
+// signingKey возвращает ключ, которым подписываются новые токены: из ключей с закрытой
+// частью — активированный последним к моменту now. Ключи с будущим ActiveFrom уже
+// опубликованы в JWKS, но начнут подписывать только в свой срок.
+func (j *JWTService) signingKey(now time.Time) *SigningKey {
+	var current *SigningKey
+	for _, k := range j.keys {
+		if k.Private == nil || k.ActiveFrom.After(now) {
+			continue
+		}
+		if current == nil || !k.ActiveFrom.Before(current.ActiveFrom) {
+			current = k
+		}
+	}
+	return current
+}
+
+// sign подписывает токен текущим ключом. Пока ни один ключ не активирован, подписывает
+// HS256 секрет: так переход можно запланировать заранее, опубликовав ключ в JWKS.
+func (j *JWTService) sign(claims Claims) (string, error) {
+	key := j.signingKey(time.Now())
+	if key == nil {
+		if len(j.keys) > 0 && len(j.secretKey) == 0 {
+			return "", errors.New("no active signing key")
+		}
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(j.secretKey)
+	}
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// verificationKey выбирает ключ проверки по kid. Алгоритм должен совпадать с ключом:
+// иначе открытый ключ можно было бы подсунуть как HMAC-секрет.
+func (j *JWTService) verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if !j.acceptsHS256(time.Now()) {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return j.secretKey, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	for _, k := range j.keys {
+		if k.ID != kid {
+			continue
+		}
+		if k.Method.Alg() != token.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return k.Public, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// acceptsHS256 — без асимметричных ключей HS256 единственный алгоритм, с ними —
+// только на время перехода
+func (j *JWTService) acceptsHS256(now time.Time) bool {
+	if len(j.keys) == 0 {
+		return true
+	}
+	if len(j.secretKey) == 0 {
+		return false
+	}
+	return j.legacyUntil.IsZero() || now.Before(j.legacyUntil)
+}
+
+// JWKS возвращает открытые ключи для проверки токенов другими сервисами; HS256
+// секрет не публикуется
+func (j *JWTService) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for _, k := range j.keys {
+		jwks.Keys = append(jwks.Keys, k.JWK())
+	}
+	return jwks
+}
+
+func hasAudience(claims *Claims, audience string) bool {
+	for _, a := range claims.Audience {
+		if a == audience {
+			return true
+		}
+	}
+	return false
+}
+
+// NewTokenID возвращает случайный идентификатор для jti и семейства refresh-токенов
+func NewTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %v", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// GenerateRefreshToken возвращает непрозрачный refresh-токен для клиента и его хеш для БД
+func GenerateRefreshToken() (token string, hash string, err error) {
+	return GenerateToken()
+}
+
+// GenerateToken возвращает случайный непрозрачный токен (refresh-токен, токен из письма)
+// и его хеш для БД
+func GenerateToken() (token string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %v", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken хеширует токен для хранения; токены случайные, поэтому соль не нужна
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,159 @@
+package auth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/ViktorOHJ/expense-tracker/pkg/auth"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func pemBlock(t *testing.T, typ string, der []byte, err error) []byte {
+	t.Helper()
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+}
+
+func newEd25519Key(t *testing.T, activeFrom time.Time) *auth.SigningKey {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	key, err := auth.ParseSigningKey(pemBlock(t, "PRIVATE KEY", der, err))
+	assert.NoError(t, err)
+	key.ActiveFrom = activeFrom
+	return key
+}
+
+func tokenKID(t *testing.T, token string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &auth.Claims{})
+	assert.NoError(t, err)
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestParseSigningKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	key, err := auth.ParseSigningKey(pemBlock(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "RS256", key.Method.Alg())
+	assert.NotNil(t, key.Private)
+
+	// Открытая часть того же ключа только проверяет подпись, kid тот же
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	public, err := auth.ParseSigningKey(pemBlock(t, "PUBLIC KEY", der, err))
+	assert.NoError(t, err)
+	assert.Nil(t, public.Private)
+	assert.Equal(t, key.ID, public.ID)
+
+	jwk := public.JWK()
+	assert.Equal(t, "RSA", jwk.Kty)
+	assert.Equal(t, "AQAB", jwk.E)
+	assert.Equal(t, key.ID, jwk.Kid)
+
+	ed := newEd25519Key(t, time.Time{})
+	assert.Equal(t, "EdDSA", ed.Method.Alg())
+	assert.Equal(t, "OKP", ed.JWK().Kty)
+	assert.Equal(t, "Ed25519", ed.JWK().Crv)
+
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+	_, err = auth.ParseSigningKey(pemBlock(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(weak), nil))
+	assert.Error(t, err)
+
+	_, err = auth.ParseSigningKey([]byte("not a key"))
+	assert.Error(t, err)
+}
+
+func TestJWTService_KeyRotation(t *testing.T) {
+	current := newEd25519Key(t, time.Now().Add(-24*time.Hour))
+	next := newEd25519Key(t, time.Now().Add(24*time.Hour))
+	j := auth.NewJWTService("", auth.WithSigningKeys(current, next))
+
+	// Следующий ключ уже опубликован, но подписывает пока текущий
+	token, err := j.GenerateToken(1, "test@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, current.ID, tokenKID(t, token))
+	assert.Len(t, j.JWKS().Keys, 2)
+
+	// После ротации старые токены проверяются, пока ключ в наборе
+	next.ActiveFrom = time.Now().Add(-time.Minute)
+	rotated, err := j.GenerateToken(1, "test@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, next.ID, tokenKID(t, rotated))
+	for _, tok := range []string{token, rotated} {
+		claims, err := j.ValidateToken(tok)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, claims.UserID)
+	}
+
+	// Токен неизвестного ключа не принимается
+	other := auth.NewJWTService("", auth.WithSigningKeys(newEd25519Key(t, time.Time{})))
+	foreign, err := other.GenerateToken(1, "test@example.com")
+	assert.NoError(t, err)
+	_, err = j.ValidateToken(foreign)
+	assert.Error(t, err)
+}
+
+func TestJWTService_HS256Migration(t *testing.T) {
+	legacy, err := auth.NewJWTService("old-secret").GenerateToken(1, "test@example.com")
+	assert.NoError(t, err)
+	key := newEd25519Key(t, time.Time{})
+
+	// Пока задан секрет, старые HS256 токены принимаются, а новые подписываются ключом
+	migrating := auth.NewJWTService("old-secret", auth.WithSigningKeys(key))
+	_, err = migrating.ValidateToken(legacy)
+	assert.NoError(t, err)
+	token, err := migrating.GenerateToken(1, "test@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, key.ID, tokenKID(t, token))
+
+	expired := auth.NewJWTService("old-secret", auth.WithSigningKeys(key), auth.WithLegacyUntil(time.Now().Add(-time.Minute)))
+	_, err = expired.ValidateToken(legacy)
+	assert.Error(t, err)
+
+	withoutSecret := auth.NewJWTService("", auth.WithSigningKeys(key))
+	_, err = withoutSecret.ValidateToken(legacy)
+	assert.Error(t, err)
+
+	// Ключ, запланированный на будущее, ещё не подписывает: до срока работает секрет
+	scheduled := auth.NewJWTService("old-secret", auth.WithSigningKeys(newEd25519Key(t, time.Now().Add(time.Hour))))
+	token, err = scheduled.GenerateToken(1, "test@example.com")
+	assert.NoError(t, err)
+	assert.Empty(t, tokenKID(t, token))
+	_, err = migrating.ValidateToken(token)
+	assert.NoError(t, err)
+}
+
+func TestJWTService_RejectsAlgorithmMismatch(t *testing.T) {
+	key := newEd25519Key(t, time.Time{})
+	j := auth.NewJWTService("", auth.WithSigningKeys(key))
+
+	// Токен с kid нашего ключа, но подписанный HMAC с открытым ключом в роли секрета
+	jwk := key.JWK()
+	jti, err := auth.NewTokenID()
+	assert.NoError(t, err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{
+		ID: jti, ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}})
+	forged.Header["kid"] = key.ID
+	signed, err := forged.SignedString([]byte(jwk.X))
+	assert.NoError(t, err)
+	_, err = j.ValidateToken(signed)
+	assert.Error(t, err)
+
+	// alg none тоже не проходит
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, auth.Claims{UserID: 1}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	assert.NoError(t, err)
+	_, err = j.ValidateToken(unsigned)
+	assert.Error(t, err)
+}
@@ -1,9 +1,20 @@
 package auth
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 type PasswordService struct{}
 
+// dummyHash — хеш той же стоимости, с которым сравнивается пароль, когда пользователя
+// с таким адресом нет: по времени ответа нельзя понять, зарегистрирован ли адрес
+var dummyHash = sync.OnceValue(func() string {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	return string(hash)
+})
+
 func NewPasswordService() *PasswordService {
 	return &PasswordService{}
 }
@@ -20,3 +31,8 @@ func (p *PasswordService) CheckPassword(hashedPassword, password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 	return err == nil
 }
+
+// DummyHash возвращает хеш для сравнения при входе по неизвестному адресу
+func (p *PasswordService) DummyHash() string {
+	return dummyHash()
+}
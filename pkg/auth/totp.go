@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"fmt"
+	"image/png"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	TOTPIssuer = "Expense Tracker"
+
+	totpPeriod = 30
+	// totpSkew — сколько соседних шагов принимается, чтобы пережить расхождение часов
+	totpSkew = 1
+
+	RecoveryCodeCount = 10
+)
+
+// NewTOTPSecret создаёт секрет TOTP: 160 случайных бит в base32, как рекомендует RFC 4226
+func NewTOTPSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate totp secret: %v", err)
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b), nil
+}
+
+// TOTPURI собирает otpauth:// URI для приложения-аутентификатора (RFC 6238:
+// SHA-1, 6 цифр, шаг 30 секунд)
+func TOTPURI(email, secret string) string {
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", TOTPIssuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", "6")
+	q.Set("period", strconv.Itoa(totpPeriod))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + TOTPIssuer + ":" + email,
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
+
+// TOTPQRCode рисует QR-код otpauth:// URI в PNG
+func TOTPQRCode(uri string, size int) ([]byte, error) {
+	key, err := otp.NewKeyFromURL(uri)
+	if err != nil {
+		return nil, err
+	}
+	img, err := key.Image(size, size)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ValidateTOTP проверяет код и возвращает номер шага, которому он соответствует.
+// Шаг сохраняется в БД, чтобы тот же код нельзя было предъявить повторно.
+func ValidateTOTP(secret, code string, now time.Time) (step int64, ok bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != 6 {
+		return 0, false
+	}
+	current := now.Unix() / totpPeriod
+	for skew := int64(-totpSkew); skew <= totpSkew; skew++ {
+		s := current + skew
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(s*totpPeriod, 0), totp.ValidateOpts{
+			Period:    totpPeriod,
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return s, true
+		}
+	}
+	return 0, false
+}
+
+// GenerateRecoveryCodes возвращает одноразовые коды восстановления вида
+// xxxx-xxxx-xxxx-xxxx (80 случайных бит) для пользователя и их хеши для БД
+func GenerateRecoveryCodes() (codes, hashes []string, err error) {
+	for i := 0; i < RecoveryCodeCount; i++ {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery codes: %v", err)
+		}
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+		code := raw[0:4] + "-" + raw[4:8] + "-" + raw[8:12] + "-" + raw[12:16]
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode хеширует код восстановления без учёта регистра, дефисов и пробелов
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	return HashToken(code)
+}
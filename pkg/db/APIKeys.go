@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+)
+
+// apiKeyTouchInterval — last_used_at обновляется не чаще, чтобы частые запросы
+// скрипта не писали в БД на каждый вызов
+const apiKeyTouchInterval = time.Minute
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, created_at`
+
+func (db *PostgresDB) AddAPIKey(parentCtx context.Context, userID int, k *models.APIKey) (models.APIKey, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var key models.APIKey
+	err := db.pool.QueryRow(ctx, `INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+	                              VALUES ($1, $2, $3, $4, $5, $6) RETURNING `+apiKeyColumns,
+		userID, k.Name, k.Prefix, k.KeyHash, ruleTags(k.Scopes), k.ExpiresAt).
+		Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, &key.Scopes, &key.ExpiresAt, &key.LastUsedAt, &key.CreatedAt)
+	if err != nil {
+		log.Printf("failed to add api key: %v", err)
+		return models.APIKey{}, fmt.Errorf("failed to add api key: %v", err)
+	}
+	return key, nil
+}
+
+func (db *PostgresDB) GetAPIKeys(parentCtx context.Context, userID int) ([]models.APIKey, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	rows, err := db.pool.Query(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE user_id = $1 ORDER BY created_at, id`, userID)
+	if err != nil {
+		log.Printf("failed to query api keys: %v", err)
+		return nil, fmt.Errorf("failed to query api keys: %v", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		var k models.APIKey
+		if err := rows.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.KeyHash, &k.Scopes, &k.ExpiresAt, &k.LastUsedAt, &k.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %v", err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// DeleteAPIKey отзывает ключ; чужой или неизвестный ключ — ErrNotFound
+func (db *PostgresDB) DeleteAPIKey(parentCtx context.Context, userID, keyID int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tag, err := db.pool.Exec(ctx, `DELETE FROM api_keys WHERE id = $1 AND user_id = $2`, keyID, userID)
+	if err != nil {
+		log.Printf("failed to delete api key: %v", err)
+		return fmt.Errorf("failed to delete api key: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UseAPIKey находит ключ по хешу для аутентификации запроса и отмечает его использование.
+// Неизвестный ключ — ErrNotFound, просроченный — ErrTokenExpired.
+func (db *PostgresDB) UseAPIKey(parentCtx context.Context, keyHash string) (models.APIKey, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var k models.APIKey
+	err := db.pool.QueryRow(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = $1`, keyHash).
+		Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.KeyHash, &k.Scopes, &k.ExpiresAt, &k.LastUsedAt, &k.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.APIKey{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve api key: %v", err)
+		return models.APIKey{}, fmt.Errorf("failed to retrieve api key: %v", err)
+	}
+
+	now := time.Now()
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		return models.APIKey{}, ErrTokenExpired
+	}
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= apiKeyTouchInterval {
+		if _, err := db.pool.Exec(ctx, `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`, k.ID, now); err != nil {
+			log.Printf("failed to update api key usage: %v", err)
+			return models.APIKey{}, fmt.Errorf("failed to update api key usage: %v", err)
+		}
+		k.LastUsedAt = &now
+	}
+	return k, nil
+}
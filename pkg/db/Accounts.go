@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+)
+
+// accountSelect считает текущий остаток: начальный плюс доходы минус расходы по счёту
+const accountSelect = `
+	SELECT a.id, a.user_id, a.name, a.type, a.currency, a.opening_balance,
+	       a.opening_balance + COALESCE(SUM(CASE WHEN t.is_income THEN t.amount ELSE -t.amount END), 0),
+	       a.created_at
+	FROM accounts a
+	LEFT JOIN transactions t ON t.account_id = a.id`
+
+func scanAccount(row pgx.Row, a *models.Account) error {
+	return row.Scan(&a.ID, &a.UserID, &a.Name, &a.Type, &a.Currency, &a.OpeningBalance, &a.Balance, &a.CreatedAt)
+}
+
+func (db *PostgresDB) AddAccount(parentCtx context.Context, userID int, a *models.Account) (models.Account, error) {
+	query := `INSERT INTO accounts (user_id, name, type, currency, opening_balance)
+	          VALUES ($1, $2, $3, COALESCE(NULLIF($4, ''), (SELECT base_currency FROM users WHERE id = $1)), $5)
+	          RETURNING id, user_id, name, type, currency, opening_balance, opening_balance, created_at`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var account models.Account
+	err := scanAccount(db.pool.QueryRow(ctx, query, userID, a.Name, a.Type, a.Currency, a.OpeningBalance), &account)
+	if isUniqueViolation(err) {
+		return models.Account{}, ErrAlreadyExists
+	}
+	if err != nil {
+		log.Printf("failed to insert account: %v", err)
+		return models.Account{}, fmt.Errorf("failed to insert account: %v", err)
+	}
+	return account, nil
+}
+
+func (db *PostgresDB) GetAccounts(parentCtx context.Context, userID int) ([]models.Account, error) {
+	query := accountSelect + ` WHERE a.user_id = $1 GROUP BY a.id ORDER BY a.name`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	rows, err := db.pool.Query(ctx, query, userID)
+	if err != nil {
+		log.Printf("failed to retrieve accounts: %v", err)
+		return nil, fmt.Errorf("failed to retrieve accounts: %v", err)
+	}
+	defer rows.Close()
+
+	accounts := []models.Account{}
+	for rows.Next() {
+		var a models.Account
+		if err := scanAccount(rows, &a); err != nil {
+			log.Printf("failed to scan account: %v", err)
+			return nil, fmt.Errorf("failed to scan account: %v", err)
+		}
+		accounts = append(accounts, a)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating over rows: %v", err)
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (db *PostgresDB) GetAccountByID(parentCtx context.Context, userID int, accountID int) (models.Account, error) {
+	query := accountSelect + ` WHERE a.id = $1 AND a.user_id = $2 GROUP BY a.id`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var account models.Account
+	err := scanAccount(db.pool.QueryRow(ctx, query, accountID, userID), &account)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Account{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve account: %v", err)
+		return models.Account{}, fmt.Errorf("failed to retrieve account: %v", err)
+	}
+	return account, nil
+}
+
+func (db *PostgresDB) UpdateAccount(parentCtx context.Context, userID int, accountID int, u *models.AccountUpdate) (models.Account, error) {
+	set := updateSet{}
+	if u.Name != nil {
+		set.add("name", *u.Name)
+	}
+	if u.Type != nil {
+		set.add("type", *u.Type)
+	}
+	if u.OpeningBalance != nil {
+		set.add("opening_balance", *u.OpeningBalance)
+	}
+	if len(set.sets) == 0 {
+		return db.GetAccountByID(parentCtx, userID, accountID)
+	}
+
+	query := `UPDATE accounts SET ` + set.where(accountID, userID)
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tag, err := db.pool.Exec(ctx, query, set.args...)
+	if isUniqueViolation(err) {
+		return models.Account{}, ErrAlreadyExists
+	}
+	if err != nil {
+		log.Printf("failed to update account: %v", err)
+		return models.Account{}, fmt.Errorf("failed to update account: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.Account{}, ErrNotFound
+	}
+	return db.GetAccountByID(parentCtx, userID, accountID)
+}
+
+// DeleteAccount удаляет счёт без транзакций и переводов; иначе возвращает ErrAccountInUse
+func (db *PostgresDB) DeleteAccount(parentCtx context.Context, userID int, accountID int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tag, err := db.pool.Exec(ctx, `DELETE FROM accounts WHERE id = $1 AND user_id = $2`, accountID, userID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrAccountInUse
+		}
+		log.Printf("failed to delete account: %v", err)
+		return fmt.Errorf("failed to delete account: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AddTransfer атомарно записывает перевод и две его транзакции: расход со счёта
+// FromAccountID в его валюте и доход на счёт ToAccountID в валюте получателя.
+// Если какой-то из счетов не принадлежит пользователю, возвращается ErrNotFound.
+func (db *PostgresDB) AddTransfer(parentCtx context.Context, userID int, t *models.Transfer) (models.Transfer, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return models.Transfer{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	transfer := *t
+	transfer.UserID = userID
+	err = tx.QueryRow(ctx, `INSERT INTO transfers (user_id, from_account_id, to_account_id, amount, to_amount, note)
+	                        SELECT $1::int, $2::int, $3::int, $4::numeric, $5::numeric, $6::text
+	                        WHERE (SELECT COUNT(*) FROM accounts WHERE user_id = $1 AND id IN ($2, $3)) = 2
+	                        RETURNING id, created_at`,
+		userID, t.FromAccountID, t.ToAccountID, t.Amount, t.ToAmount, t.Note).Scan(&transfer.ID, &transfer.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Transfer{}, ErrNotFound
+		}
+		log.Printf("failed to insert transfer: %v", err)
+		return models.Transfer{}, fmt.Errorf("failed to insert transfer: %v", err)
+	}
+
+	leg := `INSERT INTO transactions (is_income, amount, user_id, note, currency, account_id, transfer_id, occurred_at)
+	        SELECT $1::boolean, $2::numeric, $3::int, $4::text, currency, id, $6::int, $7::timestamptz
+	        FROM accounts WHERE id = $5
+	        RETURNING id`
+	err = tx.QueryRow(ctx, leg, false, t.Amount, userID, t.Note, t.FromAccountID, transfer.ID, transfer.CreatedAt).
+		Scan(&transfer.DebitID)
+	if err != nil {
+		log.Printf("failed to insert transfer debit: %v", err)
+		return models.Transfer{}, fmt.Errorf("failed to insert transfer debit: %v", err)
+	}
+	err = tx.QueryRow(ctx, leg, true, t.ToAmount, userID, t.Note, t.ToAccountID, transfer.ID, transfer.CreatedAt).
+		Scan(&transfer.CreditID)
+	if err != nil {
+		log.Printf("failed to insert transfer credit: %v", err)
+		return models.Transfer{}, fmt.Errorf("failed to insert transfer credit: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Transfer{}, fmt.Errorf("failed to commit transfer: %v", err)
+	}
+	return transfer, nil
+}
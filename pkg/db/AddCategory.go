@@ -10,16 +10,19 @@ import (
 )
 
 func (db *PostgresDB) AddCategory(parentContext context.Context, userID int, c *models.Category) (models.Category, error) {
-	query := `INSERT INTO categories (name, description, user_id)
-	          VALUES ($1, $2, $3) RETURNING *`
+	query := `INSERT INTO categories (name, description, user_id, parent_id)
+	          VALUES ($1, $2, $3, $4) RETURNING id, name, COALESCE(description, ''), user_id, parent_id, archived`
 
 	ctx, cancel := context.WithTimeout(parentContext, 5*time.Second)
 	defer cancel()
 
 	category := models.Category{}
-	err := db.pool.QueryRow(ctx, query, c.Name, c.Description, userID).
-		Scan(&category.ID, &category.Name, &category.Description, &category.UserID)
+	err := db.pool.QueryRow(ctx, query, c.Name, c.Description, userID, c.ParentID).
+		Scan(&category.ID, &category.Name, &category.Description, &category.UserID, &category.ParentID, &category.Archived)
 
+	if isUniqueViolation(err) {
+		return models.Category{}, ErrAlreadyExists
+	}
 	if err != nil {
 		log.Printf("failed to insert category: %v", err)
 		return models.Category{}, fmt.Errorf("failed to insert category: %v", err)
@@ -4,27 +4,62 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"time"
 
 	models "github.com/ViktorOHJ/expense-tracker/pkg"
 )
 
 func (db *PostgresDB) AddTransaction(parentCtx context.Context, userID int, t *models.Transaction) (models.Transaction, error) {
-	query := `INSERT INTO transactions (is_income, amount, category_id, user_id, note)
-	          VALUES ($1, $2, $3, $4, $5) RETURNING *`
+	// Без явной валюты транзакция записывается в валюте счёта, а без счёта — в базовой валюте пользователя;
+	// без даты операции — текущим временем
+	query := `INSERT INTO transactions (is_income, amount, category_id, user_id, note, currency, account_id, occurred_at)
+	          VALUES ($1, $2, $3, $4, $5,
+	                  COALESCE(NULLIF($6, ''),
+	                           (SELECT currency FROM accounts WHERE id = $7),
+	                           (SELECT base_currency FROM users WHERE id = $4)),
+	                  $7, COALESCE(` + occurredAt(t.OccurredAt, "$8", "$4") + `, CURRENT_TIMESTAMP))
+	          RETURNING ` + transactionColumns
 
 	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
 	defer cancel()
 
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
 	transaction := models.Transaction{}
-	err := db.pool.QueryRow(ctx, query, t.IsIncome, t.Amount, t.CategoryID, userID, t.Note).
-		Scan(&transaction.ID, &transaction.IsIncome, &transaction.Amount,
-			&transaction.CategoryID, &transaction.UserID, &transaction.Note, &transaction.CreatedAt)
+	var at *time.Time
+	if !t.OccurredAt.IsZero() {
+		at = &t.OccurredAt.Time
+	}
+	err = scanTransaction(tx.QueryRow(ctx, query, t.IsIncome, t.Amount, t.CategoryID, userID, t.Note, t.Currency, t.AccountID,
+		at), &transaction)
 
 	if err != nil {
 		log.Printf("failed to insert transaction: %v", err)
 		return models.Transaction{}, fmt.Errorf("failed to insert transaction: %v", err)
 	}
+
+	if len(t.Tags) > 0 {
+		if err := setTransactionTags(ctx, tx, userID, transaction.ID, t.Tags); err != nil {
+			return models.Transaction{}, err
+		}
+		transaction.Tags = append([]string(nil), t.Tags...)
+		sort.Strings(transaction.Tags)
+	}
+	if len(t.Splits) > 0 {
+		if err := setTransactionSplits(ctx, tx, transaction.ID, t.Splits); err != nil {
+			return models.Transaction{}, err
+		}
+		transaction.Splits = t.Splits
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Transaction{}, fmt.Errorf("failed to commit transaction: %v", err)
+	}
 	return transaction, nil
 }
 
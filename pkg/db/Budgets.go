@@ -0,0 +1,229 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+)
+
+const budgetColumns = `id, user_id, category_id, amount, period, start_date, end_date, rollover, created_at`
+
+func scanBudget(row pgx.Row, b *models.Budget) error {
+	return row.Scan(&b.ID, &b.UserID, &b.CategoryID, &b.Amount, &b.Period,
+		&b.StartDate, &b.EndDate, &b.Rollover, &b.CreatedAt)
+}
+
+func (db *PostgresDB) AddBudget(parentCtx context.Context, userID int, b *models.Budget) (models.Budget, error) {
+	query := `INSERT INTO budgets (user_id, category_id, amount, period, start_date, end_date, rollover)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING ` + budgetColumns
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var budget models.Budget
+	err := scanBudget(db.pool.QueryRow(ctx, query, userID, b.CategoryID, b.Amount, b.Period,
+		b.StartDate, b.EndDate, b.Rollover), &budget)
+	if err != nil {
+		log.Printf("failed to insert budget: %v", err)
+		return models.Budget{}, fmt.Errorf("failed to insert budget: %v", err)
+	}
+	return budget, nil
+}
+
+func (db *PostgresDB) GetBudgetByID(parentCtx context.Context, userID int, budgetID int) (models.Budget, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var budget models.Budget
+	err := scanBudget(db.pool.QueryRow(ctx, `SELECT `+budgetColumns+` FROM budgets WHERE id = $1 AND user_id = $2`, budgetID, userID), &budget)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Budget{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve budget: %v", err)
+		return models.Budget{}, fmt.Errorf("failed to retrieve budget: %v", err)
+	}
+	return budget, nil
+}
+
+func (db *PostgresDB) UpdateBudget(parentCtx context.Context, userID int, budgetID int, u *models.BudgetUpdate) (models.Budget, error) {
+	set := updateSet{}
+	if u.Amount != nil {
+		set.add("amount", *u.Amount)
+	}
+	if u.EndDate != nil {
+		set.add("end_date", *u.EndDate)
+	}
+	if u.Rollover != nil {
+		set.add("rollover", *u.Rollover)
+	}
+	if len(set.sets) == 0 {
+		return models.Budget{}, fmt.Errorf("nothing to update")
+	}
+
+	query := `UPDATE budgets SET ` + set.where(budgetID, userID) + ` RETURNING ` + budgetColumns
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var budget models.Budget
+	err := scanBudget(db.pool.QueryRow(ctx, query, set.args...), &budget)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Budget{}, ErrNotFound
+		}
+		log.Printf("failed to update budget: %v", err)
+		return models.Budget{}, fmt.Errorf("failed to update budget: %v", err)
+	}
+	return budget, nil
+}
+
+func (db *PostgresDB) DeleteBudget(parentCtx context.Context, userID int, budgetID int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tag, err := db.pool.Exec(ctx, `DELETE FROM budgets WHERE id = $1 AND user_id = $2`, budgetID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete budget: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetBudgetStatuses возвращает состояние бюджетов пользователя на дату on:
+// месячные бюджеты — за месяц, содержащий on, произвольные — за их собственный период.
+func (db *PostgresDB) GetBudgetStatuses(parentCtx context.Context, userID int, on time.Time) ([]models.BudgetStatus, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets
+	          WHERE user_id = $1
+	            AND (period = 'custom' OR (start_date <= $2 AND (end_date IS NULL OR end_date >= date_trunc('month', $2::date))))
+	          ORDER BY id`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 10*time.Second)
+	defer cancel()
+
+	rows, err := db.pool.Query(ctx, query, userID, on)
+	if err != nil {
+		log.Printf("failed to retrieve budgets: %v", err)
+		return nil, fmt.Errorf("failed to retrieve budgets: %v", err)
+	}
+	budgets, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Budget, error) {
+		var b models.Budget
+		err := scanBudget(row, &b)
+		return b, err
+	})
+	if err != nil {
+		log.Printf("failed to scan budget: %v", err)
+		return nil, fmt.Errorf("failed to scan budget: %v", err)
+	}
+
+	statuses := make([]models.BudgetStatus, 0, len(budgets))
+	for _, b := range budgets {
+		status, err := budgetStatus(ctx, b, on, db.budgetSpending)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// budgetSpendingFunc — budgetSpending конкретной БД
+type budgetSpendingFunc func(ctx context.Context, b models.Budget, from, to time.Time) (map[time.Time]models.Money, error)
+
+// budgetStatus считает состояние бюджета b на дату on по расходам из spending
+func budgetStatus(ctx context.Context, b models.Budget, on time.Time, spending budgetSpendingFunc) (models.BudgetStatus, error) {
+	status := models.BudgetStatus{Budget: b}
+
+	if b.Period == models.BudgetCustom {
+		status.PeriodStart = b.StartDate
+		status.PeriodEnd = *b.EndDate
+		spent, err := spending(ctx, b, b.StartDate.Time, b.EndDate.AddDate(0, 0, 1))
+		if err != nil {
+			return status, err
+		}
+		for _, v := range spent {
+			status.Spent += v
+		}
+	} else {
+		monthStart := time.Date(on.Year(), on.Month(), 1, 0, 0, 0, 0, time.UTC)
+		monthEnd := monthStart.AddDate(0, 1, 0)
+		status.PeriodStart = models.NewDate(monthStart)
+		status.PeriodEnd = models.NewDate(monthEnd.AddDate(0, 0, -1))
+
+		from := monthStart
+		if b.Rollover {
+			from = time.Date(b.StartDate.Year(), b.StartDate.Month(), 1, 0, 0, 0, 0, time.UTC)
+		}
+		spent, err := spending(ctx, b, from, monthEnd)
+		if err != nil {
+			return status, err
+		}
+		status.CarriedOver = carriedOver(b.Amount, spent, from, monthStart)
+		status.Spent = spent[monthStart]
+	}
+
+	status.Limit = b.Amount + status.CarriedOver
+	status.Remaining = status.Limit - status.Spent
+	status.Overspent = status.Remaining < 0
+	if status.Limit > 0 {
+		status.Percentage = math.Round(float64(status.Spent)/float64(status.Limit)*10000) / 100
+	}
+	return status, nil
+}
+
+// carriedOver считает остаток, перенесённый на месяц current: неизрасходованная часть
+// лимита переходит на следующий месяц, перерасход не уменьшает следующие лимиты
+func carriedOver(amount models.Money, spent map[time.Time]models.Money, from, current time.Time) models.Money {
+	var carry models.Money
+	for month := from; month.Before(current); month = month.AddDate(0, 1, 0) {
+		carry = amount + carry - spent[month]
+		if carry < 0 {
+			carry = 0
+		}
+	}
+	return carry
+}
+
+// budgetSpending возвращает расходы по бюджету в базовой валюте, сгруппированные по месяцам
+func (db *PostgresDB) budgetSpending(ctx context.Context, b models.Budget, from, to time.Time) (map[time.Time]models.Money, error) {
+	// Бюджет категории учитывает и расходы её подкатегорий, а у транзакций с разбивкой — только
+	// строки нужных категорий
+	query := `
+		SELECT date_trunc('month', t.occurred_at AT TIME ZONE u.time_zone)::date AS month,
+		       COALESCE(SUM(ROUND(line.amount * exchange_rate(t.currency, u.base_currency, (t.occurred_at AT TIME ZONE u.time_zone)::date), 2)), 0)
+		FROM transactions t
+		JOIN users u ON u.id = t.user_id
+		CROSS JOIN LATERAL ` + transactionLines + ` line
+		WHERE t.user_id = $1 AND NOT t.is_income AND t.transfer_id IS NULL
+		  AND ($2::int IS NULL OR line.category_id IN ` + categoryTree("$2") + `)
+		  AND t.occurred_at >= ` + localDay("$3", "$1") + ` AND t.occurred_at < ` + localDay("$4", "$1") + `
+		GROUP BY month`
+
+	rows, err := db.pool.Query(ctx, query, b.UserID, b.CategoryID, from, to)
+	if err != nil {
+		log.Printf("failed to retrieve budget spending: %v", err)
+		return nil, fmt.Errorf("failed to retrieve budget spending: %v", err)
+	}
+	defer rows.Close()
+
+	spent := map[time.Time]models.Money{}
+	for rows.Next() {
+		var month time.Time
+		var amount models.Money
+		if err := rows.Scan(&month, &amount); err != nil {
+			return nil, fmt.Errorf("failed to scan budget spending: %v", err)
+		}
+		spent[time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)] = amount
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return spent, nil
+}
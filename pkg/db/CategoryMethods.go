@@ -0,0 +1,232 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+)
+
+const categorySelect = `
+	SELECT c.id, c.name, COALESCE(c.description, ''), c.user_id, c.parent_id, c.archived,
+	       (SELECT COUNT(*) FROM transactions t WHERE t.category_id = c.id) AS transaction_count
+	FROM categories c`
+
+func (db *PostgresDB) GetCategories(parentCtx context.Context, userID int, includeArchived bool) ([]models.Category, error) {
+	query := categorySelect + ` WHERE c.user_id = $1`
+	if !includeArchived {
+		query += ` AND NOT c.archived`
+	}
+	query += ` ORDER BY c.name`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	rows, err := db.pool.Query(ctx, query, userID)
+	if err != nil {
+		log.Printf("failed to retrieve categories: %v", err)
+		return nil, fmt.Errorf("failed to retrieve categories: %v", err)
+	}
+	defer rows.Close()
+
+	categories := []models.Category{}
+	for rows.Next() {
+		var c models.Category
+		err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.UserID, &c.ParentID, &c.Archived, &c.TransactionCount)
+		if err != nil {
+			log.Printf("failed to scan category: %v", err)
+			return nil, fmt.Errorf("failed to scan category: %v", err)
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating over rows: %v", err)
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (db *PostgresDB) GetCategoryByID(parentCtx context.Context, userID int, categoryID int) (models.Category, error) {
+	query := categorySelect + ` WHERE c.id = $1 AND c.user_id = $2`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var c models.Category
+	err := db.pool.QueryRow(ctx, query, categoryID, userID).
+		Scan(&c.ID, &c.Name, &c.Description, &c.UserID, &c.ParentID, &c.Archived, &c.TransactionCount)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Category{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve category: %v", err)
+		return models.Category{}, fmt.Errorf("failed to retrieve category: %v", err)
+	}
+	return c, nil
+}
+
+func (db *PostgresDB) UpdateCategory(parentCtx context.Context, userID int, categoryID int, u *models.CategoryUpdate) (models.Category, error) {
+	set := updateSet{}
+	if u.Name != nil {
+		set.add("name", *u.Name)
+	}
+	if u.Description != nil {
+		set.add("description", *u.Description)
+	}
+	if u.Archived != nil {
+		set.add("archived", *u.Archived)
+	}
+	if u.ParentID != nil {
+		var parentID *int
+		if *u.ParentID != 0 {
+			parentID = u.ParentID
+		}
+		set.add("parent_id", parentID)
+	}
+	if len(set.sets) == 0 {
+		return db.GetCategoryByID(parentCtx, userID, categoryID)
+	}
+
+	query := `UPDATE categories SET ` + set.where(categoryID, userID)
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return models.Category{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if u.ParentID != nil && *u.ParentID != 0 {
+		if err := checkCategoryParent(ctx, tx, userID, categoryID, *u.ParentID); err != nil {
+			return models.Category{}, err
+		}
+	}
+
+	tag, err := tx.Exec(ctx, query, set.args...)
+	if isUniqueViolation(err) {
+		return models.Category{}, ErrAlreadyExists
+	}
+	if err != nil {
+		log.Printf("failed to update category: %v", err)
+		return models.Category{}, fmt.Errorf("failed to update category: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.Category{}, ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Category{}, fmt.Errorf("failed to commit category update: %v", err)
+	}
+	return db.GetCategoryByID(parentCtx, userID, categoryID)
+}
+
+// checkCategoryParent не даёт вложить категорию в неё саму или в её потомка.
+// Изменения иерархии одного пользователя сериализуются блокировкой его строки в users,
+// чтобы два встречных переноса не образовали цикл.
+func checkCategoryParent(ctx context.Context, tx pgx.Tx, userID, categoryID, parentID int) error {
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM users WHERE id = $1 FOR NO KEY UPDATE`, userID); err != nil {
+		return fmt.Errorf("failed to lock category tree: %v", err)
+	}
+
+	query := `WITH RECURSIVE ancestors AS (
+	              SELECT id, parent_id FROM categories WHERE id = $1 AND user_id = $3
+	              UNION ALL
+	              SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+	          )
+	          SELECT COUNT(*) > 0, COALESCE(BOOL_OR(id = $2), FALSE) FROM ancestors`
+
+	var parentExists, cycle bool
+	if err := tx.QueryRow(ctx, query, parentID, categoryID, userID).Scan(&parentExists, &cycle); err != nil {
+		log.Printf("failed to check category parent: %v", err)
+		return fmt.Errorf("failed to check category parent: %v", err)
+	}
+	if !parentExists {
+		return ErrNotFound
+	}
+	if cycle {
+		return ErrCategoryCycle
+	}
+	return nil
+}
+
+// DeleteCategory удаляет категорию. Если reassignTo задан, транзакции и регулярные
+// правила категории сначала переносятся в целевую категорию того же пользователя;
+// иначе, пока на категорию ссылается транзакция, часть разбивки или регулярное
+// правило, возвращается ErrCategoryInUse (срабатывает ON DELETE RESTRICT).
+func (db *PostgresDB) DeleteCategory(parentCtx context.Context, userID int, categoryID int, reassignTo *int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if reassignTo != nil {
+		query := `UPDATE transactions SET category_id = $1
+		          WHERE category_id = $2 AND user_id = $3
+		            AND EXISTS (SELECT 1 FROM categories WHERE id = $1 AND user_id = $3)`
+		_, err = tx.Exec(ctx, query, *reassignTo, categoryID, userID)
+		if err != nil {
+			log.Printf("failed to reassign transactions: %v", err)
+			return fmt.Errorf("failed to reassign transactions: %v", err)
+		}
+		query = `UPDATE transaction_splits SET category_id = $1
+		         WHERE category_id = $2
+		           AND transaction_id IN (SELECT id FROM transactions WHERE user_id = $3)
+		           AND EXISTS (SELECT 1 FROM categories WHERE id = $1 AND user_id = $3)`
+		_, err = tx.Exec(ctx, query, *reassignTo, categoryID, userID)
+		if err != nil {
+			log.Printf("failed to reassign transaction splits: %v", err)
+			return fmt.Errorf("failed to reassign transaction splits: %v", err)
+		}
+		query = `UPDATE category_rules SET category_id = $1
+		         WHERE category_id = $2 AND user_id = $3
+		           AND EXISTS (SELECT 1 FROM categories WHERE id = $1 AND user_id = $3)`
+		_, err = tx.Exec(ctx, query, *reassignTo, categoryID, userID)
+		if err != nil {
+			log.Printf("failed to reassign category rules: %v", err)
+			return fmt.Errorf("failed to reassign category rules: %v", err)
+		}
+		query = `UPDATE recurring_rules SET category_id = $1
+		         WHERE category_id = $2 AND user_id = $3
+		           AND EXISTS (SELECT 1 FROM categories WHERE id = $1 AND user_id = $3)`
+		_, err = tx.Exec(ctx, query, *reassignTo, categoryID, userID)
+		if err != nil {
+			log.Printf("failed to reassign recurring rules: %v", err)
+			return fmt.Errorf("failed to reassign recurring rules: %v", err)
+		}
+	}
+
+	// Подкатегории переходят к родителю удаляемой категории
+	_, err = tx.Exec(ctx, `UPDATE categories SET parent_id = (SELECT parent_id FROM categories WHERE id = $1)
+	                       WHERE parent_id = $1 AND user_id = $2`, categoryID, userID)
+	if err != nil {
+		log.Printf("failed to move subcategories: %v", err)
+		return fmt.Errorf("failed to move subcategories: %v", err)
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM categories WHERE id = $1 AND user_id = $2`, categoryID, userID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrCategoryInUse
+		}
+		log.Printf("failed to delete category: %v", err)
+		return fmt.Errorf("failed to delete category: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit category deletion: %v", err)
+	}
+	log.Printf("Category with id %d deleted successfully for user %d\n", categoryID, userID)
+	return nil
+}
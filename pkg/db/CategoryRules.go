@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+)
+
+const categoryRuleSelect = `SELECT id, user_id, name, priority, COALESCE(note_contains, ''), COALESCE(note_regex, ''),
+	       min_amount, max_amount, is_income, account_id, category_id, tags, created_at
+	FROM category_rules`
+
+const categoryRuleReturning = `RETURNING id, user_id, name, priority, COALESCE(note_contains, ''), COALESCE(note_regex, ''),
+	       min_amount, max_amount, is_income, account_id, category_id, tags, created_at`
+
+func scanCategoryRule(row pgx.Row, r *models.CategoryRule) error {
+	return row.Scan(&r.ID, &r.UserID, &r.Name, &r.Priority, &r.NoteContains, &r.NoteRegex,
+		&r.MinAmount, &r.MaxAmount, &r.IsIncome, &r.AccountID, &r.CategoryID, &r.Tags, &r.CreatedAt)
+}
+
+func (db *PostgresDB) AddCategoryRule(parentCtx context.Context, userID int, r *models.CategoryRule) (models.CategoryRule, error) {
+	query := `INSERT INTO category_rules (user_id, name, priority, note_contains, note_regex, min_amount, max_amount,
+	                                      is_income, account_id, category_id, tags)
+	          VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8, $9, $10, $11)
+	          ` + categoryRuleReturning
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var rule models.CategoryRule
+	err := scanCategoryRule(db.pool.QueryRow(ctx, query, userID, r.Name, r.Priority, r.NoteContains, r.NoteRegex,
+		r.MinAmount, r.MaxAmount, r.IsIncome, r.AccountID, r.CategoryID, ruleTags(r.Tags)), &rule)
+	if err != nil {
+		log.Printf("failed to insert category rule: %v", err)
+		return models.CategoryRule{}, fmt.Errorf("failed to insert category rule: %v", err)
+	}
+	return rule, nil
+}
+
+// GetCategoryRules возвращает правила в порядке применения
+func (db *PostgresDB) GetCategoryRules(parentCtx context.Context, userID int) ([]models.CategoryRule, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	rows, err := db.pool.Query(ctx, categoryRuleSelect+` WHERE user_id = $1 ORDER BY priority, id`, userID)
+	if err != nil {
+		log.Printf("failed to retrieve category rules: %v", err)
+		return nil, fmt.Errorf("failed to retrieve category rules: %v", err)
+	}
+	return collectCategoryRules(rows)
+}
+
+func collectCategoryRules(rows pgx.Rows) ([]models.CategoryRule, error) {
+	defer rows.Close()
+
+	rules := []models.CategoryRule{}
+	for rows.Next() {
+		var r models.CategoryRule
+		if err := scanCategoryRule(rows, &r); err != nil {
+			log.Printf("failed to scan category rule: %v", err)
+			return nil, fmt.Errorf("failed to scan category rule: %v", err)
+		}
+		rules = append(rules, r)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating over rows: %v", err)
+		return nil, err
+	}
+	return rules, nil
+}
+
+// UpdateCategoryRule заменяет правило целиком
+func (db *PostgresDB) UpdateCategoryRule(parentCtx context.Context, userID int, ruleID int, r *models.CategoryRule) (models.CategoryRule, error) {
+	query := `UPDATE category_rules
+	          SET name = $3, priority = $4, note_contains = NULLIF($5, ''), note_regex = NULLIF($6, ''),
+	              min_amount = $7, max_amount = $8, is_income = $9, account_id = $10, category_id = $11, tags = $12
+	          WHERE id = $1 AND user_id = $2
+	          ` + categoryRuleReturning
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var rule models.CategoryRule
+	err := scanCategoryRule(db.pool.QueryRow(ctx, query, ruleID, userID, r.Name, r.Priority, r.NoteContains, r.NoteRegex,
+		r.MinAmount, r.MaxAmount, r.IsIncome, r.AccountID, r.CategoryID, ruleTags(r.Tags)), &rule)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.CategoryRule{}, ErrNotFound
+		}
+		log.Printf("failed to update category rule: %v", err)
+		return models.CategoryRule{}, fmt.Errorf("failed to update category rule: %v", err)
+	}
+	return rule, nil
+}
+
+func (db *PostgresDB) DeleteCategoryRule(parentCtx context.Context, userID int, ruleID int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	res, err := db.pool.Exec(ctx, `DELETE FROM category_rules WHERE id = $1 AND user_id = $2`, ruleID, userID)
+	if err != nil {
+		log.Printf("failed to delete category rule: %v", err)
+		return fmt.Errorf("failed to delete category rule: %v", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ApplyRuleMatches записывает найденные правилами изменения. Переводы и транзакции
+// с разбивкой не меняются; возвращается число изменённых транзакций.
+func (db *PostgresDB) ApplyRuleMatches(parentCtx context.Context, userID int, matches []models.RuleMatch) (int, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 60*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	applied := 0
+	for _, m := range matches {
+		res, err := tx.Exec(ctx, `UPDATE transactions SET category_id = $1, updated_at = CURRENT_TIMESTAMP
+		                          WHERE id = $2 AND user_id = $3 AND transfer_id IS NULL
+		                            AND NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = transactions.id)`,
+			m.CategoryID, m.TransactionID, userID)
+		if err != nil {
+			log.Printf("failed to apply category rule: %v", err)
+			return 0, fmt.Errorf("failed to apply category rule: %v", err)
+		}
+		if res.RowsAffected() == 0 {
+			continue
+		}
+		if len(m.AddedTags) > 0 {
+			if err := setTransactionTags(ctx, tx, userID, m.TransactionID, m.Tags); err != nil {
+				return 0, err
+			}
+		}
+		applied++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	log.Printf("Category rules changed %d transactions for user %d\n", applied, userID)
+	return applied, nil
+}
+
+// ruleTags — пустой набор тегов пишется как '{}', а не NULL
+func ruleTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
@@ -7,18 +7,47 @@ import (
 	"time"
 )
 
-func (db *PostgresDB) DeleteTransaction(parentCtx context.Context, userID int, transactionID int) error {
-	query := `DELETE FROM transactions WHERE id=$1 AND user_id=$2`
+// DeleteTransaction удаляет транзакцию и возвращает ключи файлов её вложений,
+// записи о которых удалены каскадом, — сами файлы удаляет вызывающий
+func (db *PostgresDB) DeleteTransaction(parentCtx context.Context, userID int, transactionID int) ([]string, error) {
 	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
 	defer cancel()
 
-	row, err := db.pool.Exec(ctx, query, transactionID, userID)
+	tx, err := db.pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to delete transaction: %v", err)
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Вложения собираются и со второй половины перевода: она удаляется вместе с переводом
+	var keys []string
+	err = tx.QueryRow(ctx, `SELECT COALESCE(ARRAY_AGG(a.storage_key), '{}')
+	                        FROM attachments a
+	                        JOIN transactions t ON t.id = a.transaction_id
+	                        WHERE t.user_id = $2
+	                          AND (t.id = $1 OR t.transfer_id = (SELECT transfer_id FROM transactions WHERE id = $1 AND user_id = $2))`,
+		transactionID, userID).Scan(&keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attachments: %v", err)
+	}
+
+	// Транзакция перевода удаляется вместе со второй половиной перевода (ON DELETE CASCADE)
+	query := `WITH transfer AS (
+	              DELETE FROM transfers WHERE user_id = $2
+	                 AND id = (SELECT transfer_id FROM transactions WHERE id = $1 AND user_id = $2)
+	          )
+	          DELETE FROM transactions WHERE id=$1 AND user_id=$2`
+	row, err := tx.Exec(ctx, query, transactionID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete transaction: %v", err)
 	}
 	if row.RowsAffected() == 0 {
-		return ErrNotFound
+		return nil, ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
 	}
 	log.Printf("Transaction with id %d deleted successfully for user %d\n", transactionID, userID)
-	return nil
+	return keys, nil
 }
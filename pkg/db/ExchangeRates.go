@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveExchangeRates сохраняет курсы валют, перезаписывая уже известные на ту же дату
+func (db *PostgresDB) SaveExchangeRates(parentCtx context.Context, rates []models.ExchangeRate) (int, error) {
+	query := `INSERT INTO exchange_rates (rate_date, base_currency, quote_currency, rate)
+	          VALUES ($1, $2, $3, $4)
+	          ON CONFLICT (base_currency, quote_currency, rate_date) DO UPDATE SET rate = EXCLUDED.rate`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 30*time.Second)
+	defer cancel()
+
+	batch := &pgx.Batch{}
+	for _, r := range rates {
+		batch.Queue(query, r.Date, strings.ToUpper(r.Base), strings.ToUpper(r.Quote), r.Rate)
+	}
+
+	results := db.pool.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for range rates {
+		if _, err := results.Exec(); err != nil {
+			log.Printf("failed to save exchange rate: %v", err)
+			return 0, fmt.Errorf("failed to save exchange rate: %v", err)
+		}
+	}
+	return len(rates), nil
+}
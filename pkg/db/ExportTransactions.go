@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+// ExportTransactions передаёт в write транзакции пользователя по фильтру в порядке
+// даты, по одной строке из курсора, не загружая всю выборку в память
+func (db *PostgresDB) ExportTransactions(parentCtx context.Context, userID int, filter models.TransactionFilter, write func(models.ExportedTransaction) error) error {
+	where, args := filterClause("t.", filter, []interface{}{userID})
+	query := `SELECT t.id, t.is_income, t.amount, COALESCE(t.category_id, 0), t.user_id, COALESCE(t.note, ''), t.occurred_at, t.created_at, t.updated_at,
+	                 t.currency, t.recurring_rule_id, t.account_id, t.transfer_id, COALESCE(c.name, ''),
+	                 ARRAY(SELECT tg.name FROM transaction_tags tt JOIN tags tg ON tg.id = tt.tag_id
+	                       WHERE tt.transaction_id = t.id ORDER BY tg.name)
+	          FROM transactions t
+	          LEFT JOIN categories c ON c.id = t.category_id
+	          WHERE t.user_id = $1` + where + `
+	          ORDER BY t.occurred_at, t.id`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Minute)
+	defer cancel()
+
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("failed to export transactions: %v", err)
+		return fmt.Errorf("failed to export transactions: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.ExportedTransaction
+		t := &e.Transaction
+		err := rows.Scan(&t.ID, &t.IsIncome, &t.Amount, &t.CategoryID, &t.UserID, &t.Note, &t.OccurredAt, &t.CreatedAt, &t.UpdatedAt,
+			&t.Currency, &t.RecurringRuleID, &t.AccountID, &t.TransferID, &e.CategoryName, &t.Tags)
+		if err != nil {
+			return fmt.Errorf("failed to scan transaction: %v", err)
+		}
+		if err := write(e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
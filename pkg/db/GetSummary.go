@@ -8,26 +8,118 @@ import (
 	models "github.com/ViktorOHJ/expense-tracker/pkg"
 )
 
+// GetSummary считает итоги по каждой валюте и итоги в базовой валюте пользователя.
+// Переводы между счетами не являются доходами или расходами и в сводку не входят.
+// Каждая транзакция пересчитывается по курсу на дату её проведения; суммы в валютах
+// без известного курса в общие итоги не входят и перечисляются в MissingRates.
 func (db *PostgresDB) GetSummary(parentCtx context.Context, userID int, from, to time.Time) (models.Summary, error) {
 	query := `
-		SELECT
-			COALESCE(SUM(CASE WHEN is_income THEN amount ELSE 0 END), 0) AS total_income,
-			COALESCE(SUM(CASE WHEN NOT is_income THEN amount ELSE 0 END), 0) AS total_expense,
-			COALESCE(SUM(CASE WHEN is_income THEN amount ELSE 0 END), 0) -
-			COALESCE(SUM(CASE WHEN NOT is_income THEN amount ELSE 0 END), 0) AS balance
-		FROM transactions
-		WHERE user_id = $1 AND created_at >= $2 AND created_at <= $3`
+		WITH tx AS (
+			SELECT t.currency, t.is_income, t.amount,
+			       ROUND(t.amount * exchange_rate(t.currency, u.base_currency, (t.occurred_at AT TIME ZONE u.time_zone)::date), 2) AS converted
+			FROM transactions t
+			JOIN users u ON u.id = t.user_id
+			WHERE t.user_id = $1 AND t.occurred_at >= ` + localDay("$2", "$1") + ` AND t.occurred_at < ` + localDay("$3::date + 1", "$1") + `
+			  AND t.transfer_id IS NULL
+		)
+		SELECT currency,
+			COALESCE(SUM(amount) FILTER (WHERE is_income), 0) AS total_income,
+			COALESCE(SUM(amount) FILTER (WHERE NOT is_income), 0) AS total_expense,
+			COALESCE(SUM(converted) FILTER (WHERE is_income), 0) AS converted_income,
+			COALESCE(SUM(converted) FILTER (WHERE NOT is_income), 0) AS converted_expense,
+			BOOL_OR(converted IS NULL) AS missing_rate
+		FROM tx
+		GROUP BY currency
+		ORDER BY currency`
 
 	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
 	defer cancel()
 
 	var summary models.Summary
-	err := db.pool.QueryRow(ctx, query, userID, from, to).
-		Scan(&summary.TotalIncome, &summary.TotalExpense, &summary.Balance)
+	err := db.pool.QueryRow(ctx, `SELECT base_currency FROM users WHERE id = $1`, userID).Scan(&summary.Currency)
+	if err != nil {
+		log.Printf("failed to retrieve base currency: %v", err)
+		return models.Summary{}, err
+	}
+
+	rows, err := db.pool.Query(ctx, query, userID, from, to)
 	if err != nil {
 		log.Printf("failed to retrieve summary: %v", err)
 		return models.Summary{}, err
 	}
+	defer rows.Close()
+
+	summary.ByCurrency = []models.CurrencySummary{}
+	for rows.Next() {
+		var cs models.CurrencySummary
+		var convertedIncome, convertedExpense models.Money
+		var missingRate bool
+		err := rows.Scan(&cs.Currency, &cs.TotalIncome, &cs.TotalExpense,
+			&convertedIncome, &convertedExpense, &missingRate)
+		if err != nil {
+			log.Printf("failed to scan summary: %v", err)
+			return models.Summary{}, err
+		}
+		cs.Balance = cs.TotalIncome - cs.TotalExpense
+		summary.ByCurrency = append(summary.ByCurrency, cs)
+
+		summary.TotalIncome += convertedIncome
+		summary.TotalExpense += convertedExpense
+		if missingRate {
+			summary.MissingRates = append(summary.MissingRates, cs.Currency)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating over rows: %v", err)
+		return models.Summary{}, err
+	}
+	summary.Balance = summary.TotalIncome - summary.TotalExpense
+
+	summary.ByTag, err = db.summaryByTag(ctx, userID, from, to)
+	if err != nil {
+		return models.Summary{}, err
+	}
+
 	log.Printf("Summary retrieved successfully for user %d: %+v", userID, summary)
 	return summary, nil
 }
+
+// summaryByTag считает итоги по тегам в базовой валюте; суммы без курса пропускаются,
+// как и в общих итогах
+func (db *PostgresDB) summaryByTag(ctx context.Context, userID int, from, to time.Time) ([]models.TagSummary, error) {
+	query := `
+		WITH tx AS (
+			SELECT t.id, t.is_income,
+			       ROUND(t.amount * exchange_rate(t.currency, u.base_currency, (t.occurred_at AT TIME ZONE u.time_zone)::date), 2) AS converted
+			FROM transactions t
+			JOIN users u ON u.id = t.user_id
+			WHERE t.user_id = $1 AND t.occurred_at >= ` + localDay("$2", "$1") + ` AND t.occurred_at < ` + localDay("$3::date + 1", "$1") + `
+			  AND t.transfer_id IS NULL
+		)
+		SELECT tg.name,
+			COALESCE(SUM(tx.converted) FILTER (WHERE tx.is_income), 0) AS total_income,
+			COALESCE(SUM(tx.converted) FILTER (WHERE NOT tx.is_income), 0) AS total_expense
+		FROM tx
+		JOIN transaction_tags tt ON tt.transaction_id = tx.id
+		JOIN tags tg ON tg.id = tt.tag_id
+		GROUP BY tg.name
+		ORDER BY tg.name`
+
+	rows, err := db.pool.Query(ctx, query, userID, from, to)
+	if err != nil {
+		log.Printf("failed to retrieve tag summary: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var byTag []models.TagSummary
+	for rows.Next() {
+		var ts models.TagSummary
+		if err := rows.Scan(&ts.Tag, &ts.TotalIncome, &ts.TotalExpense); err != nil {
+			log.Printf("failed to scan tag summary: %v", err)
+			return nil, err
+		}
+		byTag = append(byTag, ts)
+	}
+	return byTag, rows.Err()
+}
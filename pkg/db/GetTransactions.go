@@ -10,33 +10,57 @@ import (
 	models "github.com/ViktorOHJ/expense-tracker/pkg"
 )
 
-func (db *PostgresDB) GetTransactions(parentCtx context.Context, userID int, txType *bool, category_id *int, from, to *time.Time, limit, offset int) ([]*models.Transaction, error) {
-
-	query := `SELECT * FROM transactions WHERE user_id = $1`
-	args := []interface{}{userID}
-	i := 2 // Start with 2 because $1 is already used for userID
+// filterClause дописывает к запросу условия фильтра; prefix — псевдоним или имя таблицы
+// transactions ("t." или "transactions."), он нужен подзапросам по тегам.
+// Аргументы добавляются к args, номера параметров продолжают их; $1 — id пользователя.
+func filterClause(prefix string, filter models.TransactionFilter, args []interface{}) (string, []interface{}) {
+	clause := ""
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		clause += ` AND ` + prefix + condition + ` $` + strconv.Itoa(len(args))
+	}
 
-	if txType != nil {
-		query += ` AND is_income = $` + strconv.Itoa(i)
-		args = append(args, *txType)
-		i++
+	if filter.IsIncome != nil {
+		add("is_income =", *filter.IsIncome)
 	}
-	if category_id != nil {
-		query += ` AND category_id = $` + strconv.Itoa(i)
-		args = append(args, *category_id)
-		i++
+	if filter.CategoryID != nil {
+		// Фильтр по родительской категории включает все её подкатегории; у транзакции
+		// с разбивкой проверяются категории строк разбивки
+		args = append(args, *filter.CategoryID)
+		tree := categoryTree("$" + strconv.Itoa(len(args)))
+		clause += ` AND (EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = ` + prefix + `id
+		                         AND s.category_id IN ` + tree + `)
+		             OR NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = ` + prefix + `id)
+		                AND ` + prefix + `category_id IN ` + tree + `)`
 	}
-	if from != nil {
-		query += ` AND created_at >= $` + strconv.Itoa(i)
-		args = append(args, *from)
-		i++
+	// from и to — календарные дни в часовом поясе пользователя, to включительно
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		clause += ` AND ` + prefix + `occurred_at >= ` + localDay("$"+strconv.Itoa(len(args)), "$1")
 	}
-	if to != nil {
-		query += ` AND created_at <= $` + strconv.Itoa(i)
-		args = append(args, *to)
-		i++
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		clause += ` AND ` + prefix + `occurred_at < ` + localDay("$"+strconv.Itoa(len(args))+"::date + 1", "$1")
+	}
+	if len(filter.Tags) > 0 {
+		args = append(args, filter.Tags)
+		n := strconv.Itoa(len(args))
+		tagged := `SELECT COUNT(*) FROM transaction_tags tt JOIN tags tg ON tg.id = tt.tag_id
+		           WHERE tt.transaction_id = ` + prefix + `id AND tg.name = ANY($` + n + `::text[])`
+		if filter.AllTags {
+			clause += ` AND (` + tagged + `) = cardinality($` + n + `::text[])`
+		} else {
+			clause += ` AND (` + tagged + `) > 0`
+		}
 	}
-	query += fmt.Sprintf(` ORDER BY created_at DESC LIMIT $%d OFFSET $%d`, i, i+1)
+	return clause, args
+}
+
+func (db *PostgresDB) GetTransactions(parentCtx context.Context, userID int, filter models.TransactionFilter, limit, offset int) ([]*models.Transaction, error) {
+
+	where, args := filterClause("transactions.", filter, []interface{}{userID})
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE user_id = $1` + where
+	query += fmt.Sprintf(` ORDER BY occurred_at DESC, id DESC LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
 	args = append(args, limit, offset)
 
 	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
@@ -52,8 +76,7 @@ func (db *PostgresDB) GetTransactions(parentCtx context.Context, userID int, txT
 	var transactions []*models.Transaction
 	for rows.Next() {
 		var transaction models.Transaction
-		err := rows.Scan(&transaction.ID, &transaction.IsIncome, &transaction.Amount,
-			&transaction.CategoryID, &transaction.UserID, &transaction.Note, &transaction.CreatedAt)
+		err := scanTransaction(rows, &transaction)
 		if err != nil {
 			log.Printf("failed to scan transaction: %v", err)
 			return []*models.Transaction{}, err
@@ -0,0 +1,239 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrImportInvalid = fmt.Errorf("import contains invalid rows")
+
+// ImportTransactions применяет к строкам импорта правила пользователя, сопоставляет
+// остальные с категориями по названию, помечает
+// дубликаты — строки, для которых уже есть транзакция с той же датой, суммой и
+// комментарием, — и записывает остальные в одной транзакции БД. При opts.DryRun
+// ничего не сохраняется. Если есть строки с ошибками, импорт не выполняется и
+// возвращается ErrImportInvalid вместе с результатом проверки.
+func (db *PostgresDB) ImportTransactions(parentCtx context.Context, userID int, rows []models.ImportRow, opts models.ImportOptions) (models.ImportResult, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 60*time.Second)
+	defer cancel()
+
+	result := models.ImportResult{DryRun: opts.DryRun, Total: len(rows), Rows: rows}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var baseCurrency string
+	err = tx.QueryRow(ctx, `SELECT base_currency FROM users WHERE id = $1`, userID).Scan(&baseCurrency)
+	if err != nil {
+		log.Printf("failed to retrieve base currency: %v", err)
+		return result, fmt.Errorf("failed to retrieve base currency: %v", err)
+	}
+
+	categories, err := importCategories(ctx, tx, userID)
+	if err != nil {
+		return result, err
+	}
+
+	ruleRows, err := tx.Query(ctx, categoryRuleSelect+` WHERE user_id = $1 ORDER BY priority, id`, userID)
+	if err != nil {
+		log.Printf("failed to retrieve category rules: %v", err)
+		return result, fmt.Errorf("failed to retrieve category rules: %v", err)
+	}
+	rules, err := collectCategoryRules(ruleRows)
+	if err != nil {
+		return result, err
+	}
+
+	classifyImportRows(rows, rules, categories, opts, &result)
+
+	// Дубликаты ищутся только среди уже сохранённых транзакций, поэтому одинаковые
+	// строки внутри одного файла (две покупки кофе за день) импортируются обе
+	batch := &pgx.Batch{}
+	var checked []*models.ImportRow
+	for i := range rows {
+		row := &rows[i]
+		if row.Status != models.ImportRowNew {
+			continue
+		}
+		if row.Transaction.Currency == "" {
+			row.Transaction.Currency = baseCurrency
+		}
+		batch.Queue(`SELECT EXISTS (SELECT 1 FROM transactions
+		                            WHERE user_id = $1 AND (occurred_at AT TIME ZONE user_time_zone($1))::date = $2 AND amount = $3 AND COALESCE(note, '') = $4)`,
+			userID, models.NewDate(row.Transaction.OccurredAt.Time), row.Transaction.Amount, row.Transaction.Note)
+		checked = append(checked, row)
+	}
+	if err := sendBatch(ctx, tx, batch, func(i int, r pgx.BatchResults) error {
+		var exists bool
+		if err := r.QueryRow().Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			checked[i].Status = models.ImportRowDuplicate
+		}
+		return nil
+	}); err != nil {
+		log.Printf("failed to check duplicate transactions: %v", err)
+		return result, fmt.Errorf("failed to check duplicate transactions: %v", err)
+	}
+
+	countImportRows(&result)
+
+	if opts.DryRun {
+		return result, nil
+	}
+	if result.Invalid > 0 {
+		return result, ErrImportInvalid
+	}
+
+	for _, name := range result.CreatedCategories {
+		var id int
+		err := tx.QueryRow(ctx, `INSERT INTO categories (name, user_id) VALUES ($1, $2) RETURNING id`, name, userID).Scan(&id)
+		if err != nil {
+			log.Printf("failed to create category: %v", err)
+			return result, fmt.Errorf("failed to create category %q: %v", name, err)
+		}
+		categories[strings.ToLower(name)] = id
+	}
+
+	// Дата из выписки — календарный день в часовом поясе пользователя
+	insert := `INSERT INTO transactions (is_income, amount, category_id, user_id, note, currency, occurred_at)
+	           VALUES ($1, $2, $3, $4, $5, $6, ` + localDay("$7", "$4") + `)
+	           RETURNING ` + transactionColumns
+	batch = &pgx.Batch{}
+	var inserted []*models.ImportRow
+	var tags [][]string // теги из правил: RETURNING выполняется до их привязки
+	for i := range rows {
+		row := &rows[i]
+		if row.Status != models.ImportRowNew {
+			continue
+		}
+		t := &row.Transaction
+		if t.CategoryID == 0 {
+			t.CategoryID = categories[strings.ToLower(row.CategoryName)]
+		}
+		batch.Queue(insert, t.IsIncome, t.Amount, t.CategoryID, userID, t.Note, t.Currency, t.OccurredAt)
+		inserted = append(inserted, row)
+		tags = append(tags, t.Tags)
+	}
+	if err := sendBatch(ctx, tx, batch, func(i int, r pgx.BatchResults) error {
+		return scanTransaction(r.QueryRow(), &inserted[i].Transaction)
+	}); err != nil {
+		log.Printf("failed to import transactions: %v", err)
+		return result, fmt.Errorf("failed to import transactions: %v", err)
+	}
+	for i, row := range inserted {
+		if len(tags[i]) == 0 {
+			continue
+		}
+		if err := setTransactionTags(ctx, tx, userID, row.Transaction.ID, tags[i]); err != nil {
+			return result, err
+		}
+		row.Transaction.Tags = tags[i]
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return result, fmt.Errorf("failed to commit import: %v", err)
+	}
+	log.Printf("Imported %d transactions for user %d (%d duplicates skipped)", result.Imported, userID, result.Duplicates)
+	return result, nil
+}
+
+// classifyImportRows применяет к строкам правила пользователя и сопоставляет остальные
+// с категориями. Категория правила важнее колонки файла; категории из файла
+// сопоставляются без учёта регистра, недостающие создаются по запросу.
+func classifyImportRows(rows []models.ImportRow, rules []models.CategoryRule, categories map[string]int, opts models.ImportOptions, result *models.ImportResult) {
+	created := map[string]bool{}
+	for i := range rows {
+		row := &rows[i]
+		if row.Status == models.ImportRowInvalid {
+			continue
+		}
+		if rule := models.MatchRule(rules, &row.Transaction); rule != nil {
+			if err := models.ApplyRule(rule, &row.Transaction); err != nil {
+				row.Status, row.Error = models.ImportRowInvalid, err.Error()
+				continue
+			}
+		}
+		if row.Transaction.CategoryID != 0 {
+			continue
+		}
+		if row.CategoryName == "" {
+			row.Status, row.Error = models.ImportRowInvalid, "category is required"
+			continue
+		}
+		key := strings.ToLower(row.CategoryName)
+		if id, ok := categories[key]; ok {
+			row.Transaction.CategoryID = id
+			continue
+		}
+		if !opts.CreateCategories {
+			row.Status, row.Error = models.ImportRowInvalid, fmt.Sprintf("category '%s' does not exist", row.CategoryName)
+			continue
+		}
+		if !created[key] {
+			created[key] = true
+			result.CreatedCategories = append(result.CreatedCategories, row.CategoryName)
+		}
+	}
+}
+
+// countImportRows подсчитывает строки результата по статусам
+func countImportRows(result *models.ImportResult) {
+	for _, row := range result.Rows {
+		switch row.Status {
+		case models.ImportRowNew:
+			result.Imported++
+		case models.ImportRowDuplicate:
+			result.Duplicates++
+		case models.ImportRowInvalid:
+			result.Invalid++
+		}
+	}
+}
+
+func importCategories(ctx context.Context, tx pgx.Tx, userID int) (map[string]int, error) {
+	rows, err := tx.Query(ctx, `SELECT id, name FROM categories WHERE user_id = $1 ORDER BY id`, userID)
+	if err != nil {
+		log.Printf("failed to retrieve categories: %v", err)
+		return nil, fmt.Errorf("failed to retrieve categories: %v", err)
+	}
+	defer rows.Close()
+
+	categories := map[string]int{}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %v", err)
+		}
+		if _, ok := categories[strings.ToLower(name)]; !ok {
+			categories[strings.ToLower(name)] = id
+		}
+	}
+	return categories, rows.Err()
+}
+
+// sendBatch выполняет пакет запросов и передаёт результат каждого в handle
+func sendBatch(ctx context.Context, tx pgx.Tx, batch *pgx.Batch, handle func(int, pgx.BatchResults) error) error {
+	if batch.Len() == 0 {
+		return nil
+	}
+	results := tx.SendBatch(ctx, batch)
+	for i := 0; i < batch.Len(); i++ {
+		if err := handle(i, results); err != nil {
+			results.Close()
+			return err
+		}
+	}
+	return results.Close()
+}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+// loginFailureReasons — исходы, которые считаются подбором: неверный пароль или код 2FA.
+// Отклонённые из-за блокировки попытки её не продлевают.
+const loginFailureReasons = `('` + models.LoginInvalidCredentials + `', '` + models.LoginInvalidCode + `')`
+
+func (db *PostgresDB) RecordLoginAttempt(parentCtx context.Context, a *models.LoginAttempt) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	_, err := db.pool.Exec(ctx, `INSERT INTO login_attempts (user_id, email, ip, success, reason) VALUES ($1, $2, $3, $4, $5)`,
+		a.UserID, a.Email, a.IP, a.Success, a.Reason)
+	if err != nil {
+		log.Printf("failed to record login attempt: %v", err)
+		return fmt.Errorf("failed to record login attempt: %v", err)
+	}
+	return nil
+}
+
+// GetLoginFailures считает неудачные попытки входа начиная с since: для адреса email —
+// только после его последнего успешного входа, для ip — все
+func (db *PostgresDB) GetLoginFailures(parentCtx context.Context, email, ip string, since time.Time) (models.LoginFailures, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var f models.LoginFailures
+	var accountLast, ipLast *time.Time
+	err := db.pool.QueryRow(ctx, `SELECT COUNT(*), MAX(created_at) FROM login_attempts
+	                              WHERE email = $1 AND reason IN `+loginFailureReasons+`
+	                                AND created_at > GREATEST($2, (SELECT MAX(created_at) FROM login_attempts WHERE email = $1 AND success))`,
+		email, since).Scan(&f.Account, &accountLast)
+	if err != nil {
+		log.Printf("failed to count login failures: %v", err)
+		return models.LoginFailures{}, fmt.Errorf("failed to count login failures: %v", err)
+	}
+	err = db.pool.QueryRow(ctx, `SELECT COUNT(*), MAX(created_at) FROM login_attempts
+	                             WHERE ip = $1 AND reason IN `+loginFailureReasons+` AND created_at > $2`,
+		ip, since).Scan(&f.IP, &ipLast)
+	if err != nil {
+		log.Printf("failed to count login failures: %v", err)
+		return models.LoginFailures{}, fmt.Errorf("failed to count login failures: %v", err)
+	}
+
+	if accountLast != nil {
+		f.AccountLast = *accountLast
+	}
+	if ipLast != nil {
+		f.IPLast = *ipLast
+	}
+	return f, nil
+}
+
+// GetMailRequests считает запросы писем со ссылками (LoginMailRequested) начиная с since
+// для адреса email и с адреса ip — так же, как GetLoginFailures считает неудачные входы
+func (db *PostgresDB) GetMailRequests(parentCtx context.Context, email, ip string, since time.Time) (models.LoginFailures, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var f models.LoginFailures
+	var accountLast, ipLast *time.Time
+	err := db.pool.QueryRow(ctx, `SELECT COUNT(*) FILTER (WHERE email = $1), MAX(created_at) FILTER (WHERE email = $1),
+	                                     COUNT(*) FILTER (WHERE ip = $2), MAX(created_at) FILTER (WHERE ip = $2)
+	                              FROM login_attempts
+	                              WHERE (email = $1 OR ip = $2) AND reason = $3 AND created_at > $4`,
+		email, ip, models.LoginMailRequested, since).Scan(&f.Account, &accountLast, &f.IP, &ipLast)
+	if err != nil {
+		log.Printf("failed to count mail requests: %v", err)
+		return models.LoginFailures{}, fmt.Errorf("failed to count mail requests: %v", err)
+	}
+
+	if accountLast != nil {
+		f.AccountLast = *accountLast
+	}
+	if ipLast != nil {
+		f.IPLast = *ipLast
+	}
+	return f, nil
+}
+
+// GetLoginAttempts возвращает попытки входа в аккаунт пользователя, новые первыми
+func (db *PostgresDB) GetLoginAttempts(parentCtx context.Context, userID int, limit, offset int) ([]models.LoginAttempt, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	rows, err := db.pool.Query(ctx, `SELECT id, user_id, email, ip, success, reason, created_at FROM login_attempts
+	                                 WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		log.Printf("failed to query login attempts: %v", err)
+		return nil, fmt.Errorf("failed to query login attempts: %v", err)
+	}
+	defer rows.Close()
+
+	attempts := []models.LoginAttempt{}
+	for rows.Next() {
+		var a models.LoginAttempt
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Email, &a.IP, &a.Success, &a.Reason, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan login attempt: %v", err)
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
@@ -0,0 +1,278 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrEndBeforeStart = fmt.Errorf("end_date must not be before start_date")
+
+const recurringColumns = `id, user_id, is_income, amount, currency, category_id, COALESCE(note, ''), frequency, interval,
+	day_of_month, start_date, end_date, max_occurrences, occurrences, next_date, created_at`
+
+func scanRecurringRule(row pgx.Row, r *models.RecurringRule) error {
+	return row.Scan(&r.ID, &r.UserID, &r.IsIncome, &r.Amount, &r.Currency, &r.CategoryID, &r.Note, &r.Frequency,
+		&r.Interval, &r.DayOfMonth, &r.StartDate, &r.EndDate, &r.MaxOccurrences, &r.Occurrences, &r.NextDate, &r.CreatedAt)
+}
+
+func collectRecurringRules(rows pgx.Rows) ([]models.RecurringRule, error) {
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.RecurringRule, error) {
+		var r models.RecurringRule
+		err := scanRecurringRule(row, &r)
+		return r, err
+	})
+}
+
+// dueRule — правило-кандидат на проведение и часовой пояс его пользователя
+type dueRule struct {
+	models.RecurringRule
+	timeZone string
+}
+
+// zoneRow дочитывает часовой пояс пользователя после колонок правила
+type zoneRow struct {
+	row  pgx.Row
+	zone *string
+}
+
+func (r zoneRow) Scan(dest ...interface{}) error {
+	return r.row.Scan(append(dest, r.zone)...)
+}
+
+func collectDueRules(rows pgx.Rows) ([]dueRule, error) {
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (dueRule, error) {
+		var r dueRule
+		err := scanRecurringRule(zoneRow{row, &r.timeZone}, &r.RecurringRule)
+		return r, err
+	})
+}
+
+// dueCandidatesUntil — последняя дата повторения, которая в момент now может наступить
+// хоть в одном часовом поясе: пояса опережают UTC не больше чем на 14 часов
+func dueCandidatesUntil(now time.Time) models.Date {
+	return models.NewDate(now.UTC().AddDate(0, 0, 1))
+}
+
+// today — текущая дата пользователя правила в момент now
+func (r dueRule) today(now time.Time) (time.Time, error) {
+	loc, err := loadLocation(r.timeZone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time zone %q: %v", r.timeZone, err)
+	}
+	return models.NewDate(now.In(loc)).Time, nil
+}
+
+func (db *PostgresDB) AddRecurringRule(parentCtx context.Context, userID int, r *models.RecurringRule) (models.RecurringRule, error) {
+	// Как и у транзакций, без явной валюты используется базовая валюта пользователя
+	query := `INSERT INTO recurring_rules (user_id, is_income, amount, currency, category_id, note, frequency, interval,
+	                                       day_of_month, start_date, end_date, max_occurrences, next_date)
+	          VALUES ($1, $2, $3, COALESCE(NULLIF($4, ''), (SELECT base_currency FROM users WHERE id = $1)),
+	                  $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	          RETURNING ` + recurringColumns
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var rule models.RecurringRule
+	err := scanRecurringRule(db.pool.QueryRow(ctx, query, userID, r.IsIncome, r.Amount, r.Currency, r.CategoryID, r.Note,
+		r.Frequency, r.Interval, r.DayOfMonth, r.StartDate, r.EndDate, r.MaxOccurrences, r.NextDate), &rule)
+	if err != nil {
+		log.Printf("failed to insert recurring rule: %v", err)
+		return models.RecurringRule{}, fmt.Errorf("failed to insert recurring rule: %v", err)
+	}
+	return rule, nil
+}
+
+func (db *PostgresDB) GetRecurringRules(parentCtx context.Context, userID int) ([]models.RecurringRule, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	rows, err := db.pool.Query(ctx, `SELECT `+recurringColumns+` FROM recurring_rules WHERE user_id = $1 ORDER BY id`, userID)
+	if err != nil {
+		log.Printf("failed to retrieve recurring rules: %v", err)
+		return nil, fmt.Errorf("failed to retrieve recurring rules: %v", err)
+	}
+	rules, err := collectRecurringRules(rows)
+	if err != nil {
+		log.Printf("failed to scan recurring rule: %v", err)
+		return nil, fmt.Errorf("failed to scan recurring rule: %v", err)
+	}
+	return rules, nil
+}
+
+// UpdateRecurringRule меняет правило и пересчитывает дату следующего повторения:
+// изменение end_date или max_occurrences может исчерпать правило или возобновить его
+func (db *PostgresDB) UpdateRecurringRule(parentCtx context.Context, userID int, ruleID int, u *models.RecurringRuleUpdate) (models.RecurringRule, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return models.RecurringRule{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var rule models.RecurringRule
+	err = scanRecurringRule(tx.QueryRow(ctx, `SELECT `+recurringColumns+` FROM recurring_rules
+	                                          WHERE id = $1 AND user_id = $2 FOR UPDATE`, ruleID, userID), &rule)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.RecurringRule{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve recurring rule: %v", err)
+		return models.RecurringRule{}, fmt.Errorf("failed to retrieve recurring rule: %v", err)
+	}
+
+	if u.Amount != nil {
+		rule.Amount = *u.Amount
+	}
+	if u.CategoryID != nil {
+		rule.CategoryID = *u.CategoryID
+	}
+	if u.Note != nil {
+		rule.Note = *u.Note
+	}
+	if u.EndDate != nil {
+		if u.EndDate.Before(rule.StartDate.Time) {
+			return models.RecurringRule{}, ErrEndBeforeStart
+		}
+		rule.EndDate = u.EndDate
+	}
+	if u.MaxOccurrences != nil {
+		rule.MaxOccurrences = u.MaxOccurrences
+	}
+	rule.NextDate = rule.NextOccurrence()
+
+	query := `UPDATE recurring_rules
+	          SET amount = $1, category_id = $2, note = $3, end_date = $4, max_occurrences = $5, next_date = $6
+	          WHERE id = $7 AND user_id = $8
+	          RETURNING ` + recurringColumns
+	err = scanRecurringRule(tx.QueryRow(ctx, query, rule.Amount, rule.CategoryID, rule.Note, rule.EndDate,
+		rule.MaxOccurrences, rule.NextDate, ruleID, userID), &rule)
+	if err != nil {
+		log.Printf("failed to update recurring rule: %v", err)
+		return models.RecurringRule{}, fmt.Errorf("failed to update recurring rule: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.RecurringRule{}, fmt.Errorf("failed to commit recurring rule update: %v", err)
+	}
+	return rule, nil
+}
+
+// DeleteRecurringRule удаляет правило; уже созданные по нему транзакции остаются
+func (db *PostgresDB) DeleteRecurringRule(parentCtx context.Context, userID int, ruleID int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tag, err := db.pool.Exec(ctx, `DELETE FROM recurring_rules WHERE id = $1 AND user_id = $2`, ruleID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recurring rule: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// materializeRule проводит наступившие повторения одного правила в собственной точке
+// сохранения: при ошибке откатываются только его изменения
+func materializeRule(ctx context.Context, tx pgx.Tx, insert string, rule models.RecurringRule, today time.Time) (int, error) {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create savepoint: %v", err)
+	}
+	defer sp.Rollback(ctx)
+
+	created := 0
+	for rule.NextDate != nil && !rule.NextDate.After(today) {
+		occurrence := *rule.NextDate
+		tag, err := sp.Exec(ctx, insert, rule.IsIncome, rule.Amount, rule.CategoryID, rule.UserID, rule.Note,
+			rule.Currency, occurrence.Time, rule.ID, occurrence)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert recurring transaction: %v", err)
+		}
+		created += int(tag.RowsAffected())
+
+		rule.Occurrences++
+		rule.NextDate = rule.NextOccurrence()
+	}
+
+	_, err = sp.Exec(ctx, `UPDATE recurring_rules SET occurrences = $1, next_date = $2 WHERE id = $3`,
+		rule.Occurrences, rule.NextDate, rule.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to advance recurring rule: %v", err)
+	}
+
+	if err := sp.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to release savepoint: %v", err)
+	}
+	return created, nil
+}
+
+// MaterializeRecurring создаёт транзакции по всем правилам, у которых к моменту now
+// наступили даты повторения, включая пропущенные за время простоя. Дата повторения
+// наступает по календарю часового пояса пользователя правила. Правила блокируются
+// через FOR UPDATE SKIP LOCKED, поэтому несколько экземпляров приложения не обрабатывают
+// одно правило одновременно, а уникальный индекс (recurring_rule_id, occurrence_date)
+// исключает повторное проведение одной даты. Правило, которое не удалось провести,
+// пропускается и повторяется при следующем запуске. Возвращает число созданных транзакций.
+func (db *PostgresDB) MaterializeRecurring(parentCtx context.Context, now time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 30*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT `+recurringColumns+`, user_time_zone(user_id) FROM recurring_rules
+	                            WHERE next_date <= $1
+	                            ORDER BY id
+	                            FOR UPDATE SKIP LOCKED`, dueCandidatesUntil(now))
+	if err != nil {
+		log.Printf("failed to retrieve due recurring rules: %v", err)
+		return 0, fmt.Errorf("failed to retrieve due recurring rules: %v", err)
+	}
+	rules, err := collectDueRules(rows)
+	if err != nil {
+		log.Printf("failed to scan recurring rule: %v", err)
+		return 0, fmt.Errorf("failed to scan recurring rule: %v", err)
+	}
+
+	insert := `INSERT INTO transactions (is_income, amount, category_id, user_id, note, currency, occurred_at,
+	                                     recurring_rule_id, occurrence_date)
+	           VALUES ($1, $2, $3, $4, $5, $6, ` + localDay("$7", "$4") + `, $8, $9)
+	           ON CONFLICT (recurring_rule_id, occurrence_date) DO NOTHING`
+
+	created := 0
+	for _, rule := range rules {
+		today, err := rule.today(now)
+		if err != nil {
+			log.Printf("skipping recurring rule %d: %v", rule.ID, err)
+			continue
+		}
+		// Кандидат, дата которого в поясе пользователя ещё не наступила
+		if rule.NextDate.After(today) {
+			continue
+		}
+		n, err := materializeRule(ctx, tx, insert, rule.RecurringRule, today)
+		if err != nil {
+			// Ошибка одного правила не должна останавливать проведение остальных
+			log.Printf("skipping recurring rule %d: %v", rule.ID, err)
+			continue
+		}
+		created += n
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit recurring transactions: %v", err)
+	}
+	return created, nil
+}
@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *PostgresDB) CreateRefreshToken(parentCtx context.Context, t *models.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (user_id, family_id, token_hash, access_jti, access_expires_at, expires_at)
+	          VALUES ($1, $2, $3, $4, $5, $6)`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	_, err := db.pool.Exec(ctx, query, t.UserID, t.FamilyID, t.TokenHash, t.AccessJTI, t.AccessExpiresAt, t.ExpiresAt)
+	if err != nil {
+		log.Printf("failed to insert refresh token: %v", err)
+		return fmt.Errorf("failed to insert refresh token: %v", err)
+	}
+	return nil
+}
+
+// RotateRefreshToken обменивает действующий refresh-токен на next из того же семейства
+// и возвращает владельца. Повторное предъявление уже использованного или отозванного
+// токена означает, что он утёк: всё семейство отзывается и возвращается ErrTokenReused.
+func (db *PostgresDB) RotateRefreshToken(parentCtx context.Context, tokenHash string, next *models.RefreshToken) (models.User, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var current models.RefreshToken
+	err = tx.QueryRow(ctx, `SELECT id, user_id, family_id, expires_at, used_at, revoked_at
+	                        FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE`, tokenHash).
+		Scan(&current.ID, &current.UserID, &current.FamilyID, &current.ExpiresAt, &current.UsedAt, &current.RevokedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.User{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve refresh token: %v", err)
+		return models.User{}, fmt.Errorf("failed to retrieve refresh token: %v", err)
+	}
+
+	if current.UsedAt != nil || current.RevokedAt != nil {
+		log.Printf("refresh token reuse detected for user %d, revoking family %s", current.UserID, current.FamilyID)
+		if err := revokeFamily(ctx, tx, current.FamilyID); err != nil {
+			return models.User{}, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return models.User{}, fmt.Errorf("failed to commit token revocation: %v", err)
+		}
+		return models.User{}, ErrTokenReused
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return models.User{}, ErrTokenExpired
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE refresh_tokens SET used_at = now() WHERE id = $1`, current.ID)
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to mark refresh token as used: %v", err)
+	}
+
+	next.UserID = current.UserID
+	next.FamilyID = current.FamilyID
+	_, err = tx.Exec(ctx, `INSERT INTO refresh_tokens (user_id, family_id, token_hash, access_jti, access_expires_at, expires_at)
+	                       VALUES ($1, $2, $3, $4, $5, $6)`,
+		next.UserID, next.FamilyID, next.TokenHash, next.AccessJTI, next.AccessExpiresAt, next.ExpiresAt)
+	if err != nil {
+		log.Printf("failed to insert refresh token: %v", err)
+		return models.User{}, fmt.Errorf("failed to insert refresh token: %v", err)
+	}
+
+	var user models.User
+	err = scanUser(tx.QueryRow(ctx, `SELECT `+userColumns+`, password FROM users WHERE id = $1`, current.UserID),
+		&user, &user.Password)
+	if err != nil {
+		log.Printf("failed to retrieve user: %v", err)
+		return models.User{}, fmt.Errorf("failed to retrieve user: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.User{}, fmt.Errorf("failed to commit token rotation: %v", err)
+	}
+	return user, nil
+}
+
+// RevokeSession завершает сессию, к которой относится access-токен jti: отзываются
+// сам токен, все refresh-токены его семейства и выданные вместе с ними access-токены
+func (db *PostgresDB) RevokeSession(parentCtx context.Context, userID int, jti string, expiresAt time.Time) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var familyID string
+	err = tx.QueryRow(ctx, `SELECT family_id FROM refresh_tokens WHERE access_jti = $1 AND user_id = $2`, jti, userID).
+		Scan(&familyID)
+	if err != nil && err != pgx.ErrNoRows {
+		log.Printf("failed to retrieve session: %v", err)
+		return fmt.Errorf("failed to retrieve session: %v", err)
+	}
+	if familyID != "" {
+		if err := revokeFamily(ctx, tx, familyID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING`,
+		jti, expiresAt)
+	if err != nil {
+		log.Printf("failed to revoke access token: %v", err)
+		return fmt.Errorf("failed to revoke access token: %v", err)
+	}
+
+	// Истёкшие токены отклоняются и без списка отзыва
+	if _, err := tx.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at < now()`); err != nil {
+		return fmt.Errorf("failed to clean up revoked tokens: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit session revocation: %v", err)
+	}
+	return nil
+}
+
+func revokeFamily(ctx context.Context, tx pgx.Tx, familyID string) error {
+	_, err := tx.Exec(ctx, `INSERT INTO revoked_tokens (jti, expires_at)
+	                        SELECT access_jti, access_expires_at FROM refresh_tokens
+	                        WHERE family_id = $1 AND access_expires_at > now()
+	                        ON CONFLICT (jti) DO NOTHING`, familyID)
+	if err != nil {
+		log.Printf("failed to revoke access tokens: %v", err)
+		return fmt.Errorf("failed to revoke access tokens: %v", err)
+	}
+	_, err = tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = now() WHERE family_id = $1 AND revoked_at IS NULL`, familyID)
+	if err != nil {
+		log.Printf("failed to revoke refresh tokens: %v", err)
+		return fmt.Errorf("failed to revoke refresh tokens: %v", err)
+	}
+	return nil
+}
+
+func (db *PostgresDB) IsTokenRevoked(parentCtx context.Context, jti string) (bool, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var revoked bool
+	err := db.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)`, jti).Scan(&revoked)
+	if err != nil {
+		log.Printf("failed to check token revocation: %v", err)
+		return false, fmt.Errorf("failed to check token revocation: %v", err)
+	}
+	return revoked, nil
+}
@@ -0,0 +1,196 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+// reportTransactions возвращает CTE tx со строками транзакций пользователя по фильтру
+// и их суммами в базовой валюте (converted; NULL, если курса нет), без переводов между
+// счетами. Транзакция с разбивкой даёт по строке на каждую часть, и фильтр по категории
+// применяется к этим строкам. userID — параметр $1.
+func reportTransactions(filter models.TransactionFilter, args []interface{}) (string, []interface{}) {
+	categoryID := filter.CategoryID
+	filter.CategoryID = nil
+	where, args := filterClause("t.", filter, args)
+	if categoryID != nil {
+		args = append(args, *categoryID)
+		where += ` AND line.category_id IN ` + categoryTree("$"+strconv.Itoa(len(args)))
+	}
+	// occurred_at в выборке — местное время пользователя: по нему строятся периоды
+	return `tx AS (
+			SELECT t.id, line.category_id, t.is_income, t.currency, t.occurred_at AT TIME ZONE u.time_zone AS occurred_at,
+			       ROUND(line.amount * exchange_rate(t.currency, u.base_currency, (t.occurred_at AT TIME ZONE u.time_zone)::date), 2) AS converted
+			FROM transactions t
+			JOIN users u ON u.id = t.user_id
+			CROSS JOIN LATERAL ` + transactionLines + ` line
+			WHERE t.user_id = $1 AND t.transfer_id IS NULL` + where + `
+		)`, args
+}
+
+// reportTotals считает итоги отчёта и валюты без курса, не вошедшие в них
+func (db *PostgresDB) reportTotals(ctx context.Context, userID int, filter models.TransactionFilter) (income, expense models.Money, missing []string, err error) {
+	cte, args := reportTransactions(filter, []interface{}{userID})
+	query := `WITH ` + cte + `
+		SELECT COALESCE(SUM(converted) FILTER (WHERE is_income), 0),
+		       COALESCE(SUM(converted) FILTER (WHERE NOT is_income), 0),
+		       COALESCE(ARRAY_AGG(DISTINCT currency::text) FILTER (WHERE converted IS NULL), '{}')
+		FROM tx`
+
+	err = db.pool.QueryRow(ctx, query, args...).Scan(&income, &expense, &missing)
+	if err != nil {
+		log.Printf("failed to retrieve report totals: %v", err)
+		return 0, 0, nil, fmt.Errorf("failed to retrieve report totals: %v", err)
+	}
+	if len(missing) == 0 {
+		missing = nil
+	}
+	return income, expense, missing, nil
+}
+
+// GetCategoryReport считает доходы и расходы по категориям. Суммы подкатегорий входят
+// в итоги всех их предков; при фильтре по категории в отчёт попадает только её поддерево.
+func (db *PostgresDB) GetCategoryReport(parentCtx context.Context, userID int, filter models.TransactionFilter) (models.CategoryReport, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var report models.CategoryReport
+	err := db.pool.QueryRow(ctx, `SELECT base_currency FROM users WHERE id = $1`, userID).Scan(&report.Currency)
+	if err != nil {
+		log.Printf("failed to retrieve base currency: %v", err)
+		return models.CategoryReport{}, fmt.Errorf("failed to retrieve base currency: %v", err)
+	}
+
+	report.TotalIncome, report.TotalExpense, report.MissingRates, err = db.reportTotals(ctx, userID, filter)
+	if err != nil {
+		return models.CategoryReport{}, err
+	}
+
+	cte, args := reportTransactions(filter, []interface{}{userID})
+	subtree := ""
+	if filter.CategoryID != nil {
+		args = append(args, *filter.CategoryID)
+		subtree = ` WHERE c.id IN ` + categoryTree("$"+strconv.Itoa(len(args)))
+	}
+	query := `WITH RECURSIVE ` + cte + `,
+		own AS (
+			SELECT category_id,
+			       COALESCE(SUM(converted) FILTER (WHERE is_income), 0) AS income,
+			       COALESCE(SUM(converted) FILTER (WHERE NOT is_income), 0) AS expense
+			FROM tx
+			GROUP BY category_id
+		),
+		totals AS (
+			SELECT SUM(income) AS income, SUM(expense) AS expense FROM own
+		),
+		tree AS (
+			SELECT id AS root_id, id FROM categories WHERE user_id = $1
+			UNION ALL
+			SELECT tree.root_id, c.id FROM categories c JOIN tree ON c.parent_id = tree.id
+		)
+		SELECT c.id, c.name, c.parent_id, SUM(own.income), SUM(own.expense),
+		       COALESCE(ROUND(100 * SUM(own.income) / NULLIF(totals.income, 0), 2), 0),
+		       COALESCE(ROUND(100 * SUM(own.expense) / NULLIF(totals.expense, 0), 2), 0)
+		FROM categories c
+		JOIN tree ON tree.root_id = c.id
+		JOIN own ON own.category_id = tree.id
+		CROSS JOIN totals` + subtree + `
+		GROUP BY c.id, totals.income, totals.expense
+		ORDER BY SUM(own.expense) DESC, SUM(own.income) DESC, c.name`
+
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("failed to retrieve category report: %v", err)
+		return models.CategoryReport{}, fmt.Errorf("failed to retrieve category report: %v", err)
+	}
+	defer rows.Close()
+
+	report.Categories = []models.CategoryReportRow{}
+	for rows.Next() {
+		var row models.CategoryReportRow
+		err := rows.Scan(&row.CategoryID, &row.Name, &row.ParentID, &row.Income, &row.Expense,
+			&row.IncomeShare, &row.ExpenseShare)
+		if err != nil {
+			log.Printf("failed to scan category report: %v", err)
+			return models.CategoryReport{}, fmt.Errorf("failed to scan category report: %v", err)
+		}
+		report.Categories = append(report.Categories, row)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating over rows: %v", err)
+		return models.CategoryReport{}, err
+	}
+	return report, nil
+}
+
+// GetTimeSeries группирует доходы и расходы по периодам interval между filter.From
+// и filter.To; оба конца диапазона обязательны
+func (db *PostgresDB) GetTimeSeries(parentCtx context.Context, userID int, filter models.TransactionFilter, interval string) (models.TimeSeries, error) {
+	if !models.IsValidInterval(interval) || filter.From == nil || filter.To == nil {
+		return models.TimeSeries{}, fmt.Errorf("time series requires a valid interval and date range")
+	}
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	series := models.TimeSeries{Interval: interval}
+	err := db.pool.QueryRow(ctx, `SELECT base_currency FROM users WHERE id = $1`, userID).Scan(&series.Currency)
+	if err != nil {
+		log.Printf("failed to retrieve base currency: %v", err)
+		return models.TimeSeries{}, fmt.Errorf("failed to retrieve base currency: %v", err)
+	}
+
+	_, _, series.MissingRates, err = db.reportTotals(ctx, userID, filter)
+	if err != nil {
+		return models.TimeSeries{}, err
+	}
+
+	cte, args := reportTransactions(filter, []interface{}{userID})
+	args = append(args, interval, *filter.From, *filter.To)
+	n := len(args)
+	step, from, to := "$"+strconv.Itoa(n-2)+"::text", "$"+strconv.Itoa(n-1)+"::timestamp", "$"+strconv.Itoa(n)+"::timestamp"
+	query := `WITH ` + cte + `,
+		buckets AS (
+			SELECT generate_series(date_trunc(` + step + `, ` + from + `), date_trunc(` + step + `, ` + to + `),
+			                       ('1 ' || ` + step + `)::interval) AS period
+		),
+		agg AS (
+			SELECT date_trunc(` + step + `, occurred_at) AS period,
+			       SUM(converted) FILTER (WHERE is_income) AS income,
+			       SUM(converted) FILTER (WHERE NOT is_income) AS expense
+			FROM tx
+			GROUP BY 1
+		)
+		SELECT b.period::date, COALESCE(a.income, 0), COALESCE(a.expense, 0)
+		FROM buckets b
+		LEFT JOIN agg a ON a.period = b.period
+		ORDER BY b.period`
+
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("failed to retrieve time series: %v", err)
+		return models.TimeSeries{}, fmt.Errorf("failed to retrieve time series: %v", err)
+	}
+	defer rows.Close()
+
+	series.Points = []models.TimeSeriesPoint{}
+	for rows.Next() {
+		var p models.TimeSeriesPoint
+		if err := rows.Scan(&p.Period, &p.Income, &p.Expense); err != nil {
+			log.Printf("failed to scan time series: %v", err)
+			return models.TimeSeries{}, fmt.Errorf("failed to scan time series: %v", err)
+		}
+		p.Balance = p.Income - p.Expense
+		series.Points = append(series.Points, p)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating over rows: %v", err)
+		return models.TimeSeries{}, err
+	}
+	return series, nil
+}
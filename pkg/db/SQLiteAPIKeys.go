@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+func scanSQLiteAPIKey(row sqliteRow, k *models.APIKey) error {
+	return row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.KeyHash, jsonColumn{&k.Scopes}, &k.ExpiresAt, &k.LastUsedAt, &k.CreatedAt)
+}
+
+func (db *SQLiteDB) AddAPIKey(parentCtx context.Context, userID int, k *models.APIKey) (models.APIKey, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var key models.APIKey
+	err := scanSQLiteAPIKey(db.db.QueryRowContext(ctx, `INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at, created_at)
+	                                                    VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7) RETURNING `+apiKeyColumns,
+		userID, k.Name, k.Prefix, k.KeyHash, sqliteJSON(ruleTags(k.Scopes)), sqliteOptionalTime(k.ExpiresAt), sqliteTime(time.Now())), &key)
+	if err != nil {
+		log.Printf("failed to add api key: %v", err)
+		return models.APIKey{}, fmt.Errorf("failed to add api key: %v", err)
+	}
+	return key, nil
+}
+
+func (db *SQLiteDB) GetAPIKeys(parentCtx context.Context, userID int) ([]models.APIKey, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	rows, err := db.db.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE user_id = ?1 ORDER BY created_at, id`, userID)
+	if err != nil {
+		log.Printf("failed to query api keys: %v", err)
+		return nil, fmt.Errorf("failed to query api keys: %v", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		var k models.APIKey
+		if err := scanSQLiteAPIKey(rows, &k); err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %v", err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (db *SQLiteDB) DeleteAPIKey(parentCtx context.Context, userID, keyID int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	res, err := db.db.ExecContext(ctx, `DELETE FROM api_keys WHERE id = ?1 AND user_id = ?2`, keyID, userID)
+	if err != nil {
+		log.Printf("failed to delete api key: %v", err)
+		return fmt.Errorf("failed to delete api key: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (db *SQLiteDB) UseAPIKey(parentCtx context.Context, keyHash string) (models.APIKey, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var k models.APIKey
+	err := scanSQLiteAPIKey(db.db.QueryRowContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = ?1`, keyHash), &k)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.APIKey{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve api key: %v", err)
+		return models.APIKey{}, fmt.Errorf("failed to retrieve api key: %v", err)
+	}
+
+	now := time.Now()
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		return models.APIKey{}, ErrTokenExpired
+	}
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= apiKeyTouchInterval {
+		if _, err := db.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = ?2 WHERE id = ?1`, k.ID, sqliteTime(now)); err != nil {
+			log.Printf("failed to update api key usage: %v", err)
+			return models.APIKey{}, fmt.Errorf("failed to update api key usage: %v", err)
+		}
+		k.LastUsedAt = &now
+	}
+	return k, nil
+}
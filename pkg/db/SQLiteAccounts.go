@@ -0,0 +1,177 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+func (db *SQLiteDB) AddAccount(parentCtx context.Context, userID int, a *models.Account) (models.Account, error) {
+	query := `INSERT INTO accounts (user_id, name, type, currency, opening_balance)
+	          VALUES (?1, ?2, ?3, COALESCE(NULLIF(?4, ''), (SELECT base_currency FROM users WHERE id = ?1)), ?5)
+	          RETURNING id, user_id, name, type, currency, opening_balance, opening_balance, created_at`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var account models.Account
+	err := scanAccount(db.db.QueryRowContext(ctx, query, userID, a.Name, a.Type, a.Currency, int64(a.OpeningBalance)), &account)
+	if isUniqueViolation(err) {
+		return models.Account{}, ErrAlreadyExists
+	}
+	if err != nil {
+		log.Printf("failed to insert account: %v", err)
+		return models.Account{}, fmt.Errorf("failed to insert account: %v", err)
+	}
+	return account, nil
+}
+
+func (db *SQLiteDB) GetAccounts(parentCtx context.Context, userID int) ([]models.Account, error) {
+	query := accountSelect + ` WHERE a.user_id = ?1 GROUP BY a.id ORDER BY a.name`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	rows, err := db.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		log.Printf("failed to retrieve accounts: %v", err)
+		return nil, fmt.Errorf("failed to retrieve accounts: %v", err)
+	}
+	defer rows.Close()
+
+	accounts := []models.Account{}
+	for rows.Next() {
+		var a models.Account
+		if err := scanAccount(rows, &a); err != nil {
+			log.Printf("failed to scan account: %v", err)
+			return nil, fmt.Errorf("failed to scan account: %v", err)
+		}
+		accounts = append(accounts, a)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating over rows: %v", err)
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (db *SQLiteDB) GetAccountByID(parentCtx context.Context, userID int, accountID int) (models.Account, error) {
+	query := accountSelect + ` WHERE a.id = ?1 AND a.user_id = ?2 GROUP BY a.id`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var account models.Account
+	err := scanAccount(db.db.QueryRowContext(ctx, query, accountID, userID), &account)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Account{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve account: %v", err)
+		return models.Account{}, fmt.Errorf("failed to retrieve account: %v", err)
+	}
+	return account, nil
+}
+
+func (db *SQLiteDB) UpdateAccount(parentCtx context.Context, userID int, accountID int, u *models.AccountUpdate) (models.Account, error) {
+	set := updateSet{}
+	if u.Name != nil {
+		set.add("name", *u.Name)
+	}
+	if u.Type != nil {
+		set.add("type", *u.Type)
+	}
+	if u.OpeningBalance != nil {
+		set.add("opening_balance", int64(*u.OpeningBalance))
+	}
+	if len(set.sets) == 0 {
+		return db.GetAccountByID(parentCtx, userID, accountID)
+	}
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	res, err := db.db.ExecContext(ctx, rebind(`UPDATE accounts SET `+set.where(accountID, userID)), set.args...)
+	if isUniqueViolation(err) {
+		return models.Account{}, ErrAlreadyExists
+	}
+	if err != nil {
+		log.Printf("failed to update account: %v", err)
+		return models.Account{}, fmt.Errorf("failed to update account: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return models.Account{}, ErrNotFound
+	}
+	return db.GetAccountByID(parentCtx, userID, accountID)
+}
+
+func (db *SQLiteDB) DeleteAccount(parentCtx context.Context, userID int, accountID int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	res, err := db.db.ExecContext(ctx, `DELETE FROM accounts WHERE id = ?1 AND user_id = ?2`, accountID, userID)
+	if isForeignKeyViolation(err) {
+		return ErrAccountInUse
+	}
+	if err != nil {
+		log.Printf("failed to delete account: %v", err)
+		return fmt.Errorf("failed to delete account: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (db *SQLiteDB) AddTransfer(parentCtx context.Context, userID int, t *models.Transfer) (models.Transfer, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Transfer{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	transfer := *t
+	transfer.UserID = userID
+	err = tx.QueryRowContext(ctx, `INSERT INTO transfers (user_id, from_account_id, to_account_id, amount, to_amount, note)
+	                               SELECT ?1, ?2, ?3, ?4, ?5, ?6
+	                               WHERE (SELECT COUNT(*) FROM accounts WHERE user_id = ?1 AND id IN (?2, ?3)) = 2
+	                               RETURNING id, created_at`,
+		userID, t.FromAccountID, t.ToAccountID, int64(t.Amount), int64(t.ToAmount), t.Note).Scan(&transfer.ID, &transfer.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Transfer{}, ErrNotFound
+		}
+		log.Printf("failed to insert transfer: %v", err)
+		return models.Transfer{}, fmt.Errorf("failed to insert transfer: %v", err)
+	}
+
+	leg := `INSERT INTO transactions (is_income, amount, user_id, note, currency, account_id, transfer_id, occurred_at)
+	        SELECT ?1, ?2, ?3, ?4, currency, id, ?6, ?7
+	        FROM accounts WHERE id = ?5
+	        RETURNING id`
+	occurred := sqliteTime(transfer.CreatedAt)
+	err = tx.QueryRowContext(ctx, leg, false, int64(t.Amount), userID, t.Note, t.FromAccountID, transfer.ID, occurred).
+		Scan(&transfer.DebitID)
+	if err != nil {
+		log.Printf("failed to insert transfer debit: %v", err)
+		return models.Transfer{}, fmt.Errorf("failed to insert transfer debit: %v", err)
+	}
+	err = tx.QueryRowContext(ctx, leg, true, int64(t.ToAmount), userID, t.Note, t.ToAccountID, transfer.ID, occurred).
+		Scan(&transfer.CreditID)
+	if err != nil {
+		log.Printf("failed to insert transfer credit: %v", err)
+		return models.Transfer{}, fmt.Errorf("failed to insert transfer credit: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Transfer{}, fmt.Errorf("failed to commit transfer: %v", err)
+	}
+	return transfer, nil
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+func (db *SQLiteDB) AddAttachment(parentCtx context.Context, userID int, a *models.Attachment) (models.Attachment, error) {
+	query := `INSERT INTO attachments (transaction_id, user_id, file_name, content_type, size, storage_key)
+	          SELECT id, user_id, ?3, ?4, ?5, ?6 FROM transactions WHERE id = ?1 AND user_id = ?2
+	          RETURNING id, transaction_id, user_id, file_name, content_type, size, storage_key, created_at`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var attachment models.Attachment
+	err := scanAttachment(db.db.QueryRowContext(ctx, query, a.TransactionID, userID, a.FileName, a.ContentType, a.Size, a.StorageKey),
+		&attachment)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Attachment{}, ErrNotFound
+		}
+		log.Printf("failed to insert attachment: %v", err)
+		return models.Attachment{}, fmt.Errorf("failed to insert attachment: %v", err)
+	}
+	log.Printf("Attachment %d added to transaction %d for user %d\n", attachment.ID, attachment.TransactionID, userID)
+	return attachment, nil
+}
+
+func (db *SQLiteDB) GetAttachments(parentCtx context.Context, userID int, transactionID int) ([]models.Attachment, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var exists bool
+	err := db.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM transactions WHERE id = ?1 AND user_id = ?2)`,
+		transactionID, userID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check transaction: %v", err)
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	rows, err := db.db.QueryContext(ctx, attachmentSelect+` WHERE transaction_id = ?1 AND user_id = ?2 ORDER BY id`,
+		transactionID, userID)
+	if err != nil {
+		log.Printf("failed to retrieve attachments: %v", err)
+		return nil, fmt.Errorf("failed to retrieve attachments: %v", err)
+	}
+	defer rows.Close()
+
+	attachments := []models.Attachment{}
+	for rows.Next() {
+		var a models.Attachment
+		if err := scanAttachment(rows, &a); err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %v", err)
+		}
+		attachments = append(attachments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate attachments: %v", err)
+	}
+	return attachments, nil
+}
+
+func (db *SQLiteDB) GetAttachment(parentCtx context.Context, userID int, transactionID int, attachmentID int) (models.Attachment, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var a models.Attachment
+	err := scanAttachment(db.db.QueryRowContext(ctx, attachmentSelect+` WHERE id = ?1 AND transaction_id = ?2 AND user_id = ?3`,
+		attachmentID, transactionID, userID), &a)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Attachment{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve attachment: %v", err)
+		return models.Attachment{}, fmt.Errorf("failed to retrieve attachment: %v", err)
+	}
+	return a, nil
+}
+
+func (db *SQLiteDB) DeleteAttachment(parentCtx context.Context, userID int, transactionID int, attachmentID int) (string, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var key string
+	err := db.db.QueryRowContext(ctx, `DELETE FROM attachments WHERE id = ?1 AND transaction_id = ?2 AND user_id = ?3
+	                                   RETURNING storage_key`, attachmentID, transactionID, userID).Scan(&key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrNotFound
+		}
+		log.Printf("failed to delete attachment: %v", err)
+		return "", fmt.Errorf("failed to delete attachment: %v", err)
+	}
+	log.Printf("Attachment %d deleted for user %d\n", attachmentID, userID)
+	return key, nil
+}
@@ -0,0 +1,209 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+func (db *SQLiteDB) AddCategory(parentCtx context.Context, userID int, c *models.Category) (models.Category, error) {
+	query := `INSERT INTO categories (name, description, user_id, parent_id)
+	          VALUES (?1, ?2, ?3, ?4) RETURNING id, name, COALESCE(description, ''), user_id, parent_id, archived`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	category := models.Category{}
+	err := db.db.QueryRowContext(ctx, query, c.Name, c.Description, userID, c.ParentID).
+		Scan(&category.ID, &category.Name, &category.Description, &category.UserID, &category.ParentID, &category.Archived)
+	if isUniqueViolation(err) {
+		return models.Category{}, ErrAlreadyExists
+	}
+	if err != nil {
+		log.Printf("failed to insert category: %v", err)
+		return models.Category{}, fmt.Errorf("failed to insert category: %v", err)
+	}
+	return category, nil
+}
+
+func (db *SQLiteDB) GetCategories(parentCtx context.Context, userID int, includeArchived bool) ([]models.Category, error) {
+	query := categorySelect + ` WHERE c.user_id = ?1`
+	if !includeArchived {
+		query += ` AND NOT c.archived`
+	}
+	query += ` ORDER BY c.name`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	rows, err := db.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		log.Printf("failed to retrieve categories: %v", err)
+		return nil, fmt.Errorf("failed to retrieve categories: %v", err)
+	}
+	defer rows.Close()
+
+	categories := []models.Category{}
+	for rows.Next() {
+		var c models.Category
+		err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.UserID, &c.ParentID, &c.Archived, &c.TransactionCount)
+		if err != nil {
+			log.Printf("failed to scan category: %v", err)
+			return nil, fmt.Errorf("failed to scan category: %v", err)
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating over rows: %v", err)
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (db *SQLiteDB) GetCategoryByID(parentCtx context.Context, userID int, categoryID int) (models.Category, error) {
+	query := categorySelect + ` WHERE c.id = ?1 AND c.user_id = ?2`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var c models.Category
+	err := db.db.QueryRowContext(ctx, query, categoryID, userID).
+		Scan(&c.ID, &c.Name, &c.Description, &c.UserID, &c.ParentID, &c.Archived, &c.TransactionCount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Category{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve category: %v", err)
+		return models.Category{}, fmt.Errorf("failed to retrieve category: %v", err)
+	}
+	return c, nil
+}
+
+func (db *SQLiteDB) UpdateCategory(parentCtx context.Context, userID int, categoryID int, u *models.CategoryUpdate) (models.Category, error) {
+	set := updateSet{}
+	if u.Name != nil {
+		set.add("name", *u.Name)
+	}
+	if u.Description != nil {
+		set.add("description", *u.Description)
+	}
+	if u.Archived != nil {
+		set.add("archived", *u.Archived)
+	}
+	if u.ParentID != nil {
+		var parentID *int
+		if *u.ParentID != 0 {
+			parentID = u.ParentID
+		}
+		set.add("parent_id", parentID)
+	}
+	if len(set.sets) == 0 {
+		return db.GetCategoryByID(parentCtx, userID, categoryID)
+	}
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Category{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// Транзакция уже держит блокировку записи, поэтому встречные переносы не образуют цикл
+	if u.ParentID != nil && *u.ParentID != 0 {
+		query := `WITH RECURSIVE ancestors AS (
+		              SELECT id, parent_id FROM categories WHERE id = ?1 AND user_id = ?3
+		              UNION ALL
+		              SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+		          )
+		          SELECT COUNT(*) > 0, COALESCE(MAX(id = ?2), FALSE) FROM ancestors`
+
+		var parentExists, cycle bool
+		if err := tx.QueryRowContext(ctx, query, *u.ParentID, categoryID, userID).Scan(&parentExists, &cycle); err != nil {
+			log.Printf("failed to check category parent: %v", err)
+			return models.Category{}, fmt.Errorf("failed to check category parent: %v", err)
+		}
+		if !parentExists {
+			return models.Category{}, ErrNotFound
+		}
+		if cycle {
+			return models.Category{}, ErrCategoryCycle
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, rebind(`UPDATE categories SET `+set.where(categoryID, userID)), set.args...)
+	if isUniqueViolation(err) {
+		return models.Category{}, ErrAlreadyExists
+	}
+	if err != nil {
+		log.Printf("failed to update category: %v", err)
+		return models.Category{}, fmt.Errorf("failed to update category: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return models.Category{}, ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Category{}, fmt.Errorf("failed to commit category update: %v", err)
+	}
+	return db.GetCategoryByID(parentCtx, userID, categoryID)
+}
+
+func (db *SQLiteDB) DeleteCategory(parentCtx context.Context, userID int, categoryID int, reassignTo *int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if reassignTo != nil {
+		reassign := []struct{ name, query string }{
+			{"transactions", `UPDATE transactions SET category_id = ?1 WHERE category_id = ?2 AND user_id = ?3`},
+			{"transaction splits", `UPDATE transaction_splits SET category_id = ?1
+			                        WHERE category_id = ?2 AND transaction_id IN (SELECT id FROM transactions WHERE user_id = ?3)`},
+			{"category rules", `UPDATE category_rules SET category_id = ?1 WHERE category_id = ?2 AND user_id = ?3`},
+			{"recurring rules", `UPDATE recurring_rules SET category_id = ?1 WHERE category_id = ?2 AND user_id = ?3`},
+		}
+		for _, r := range reassign {
+			query := r.query + ` AND EXISTS (SELECT 1 FROM categories WHERE id = ?1 AND user_id = ?3)`
+			if _, err := tx.ExecContext(ctx, query, *reassignTo, categoryID, userID); err != nil {
+				log.Printf("failed to reassign %s: %v", r.name, err)
+				return fmt.Errorf("failed to reassign %s: %v", r.name, err)
+			}
+		}
+	}
+
+	// Подкатегории переходят к родителю удаляемой категории
+	_, err = tx.ExecContext(ctx, `UPDATE categories SET parent_id = (SELECT parent_id FROM categories WHERE id = ?1)
+	                              WHERE parent_id = ?1 AND user_id = ?2`, categoryID, userID)
+	if err != nil {
+		log.Printf("failed to move subcategories: %v", err)
+		return fmt.Errorf("failed to move subcategories: %v", err)
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM categories WHERE id = ?1 AND user_id = ?2`, categoryID, userID)
+	if isForeignKeyViolation(err) {
+		return ErrCategoryInUse
+	}
+	if err != nil {
+		log.Printf("failed to delete category: %v", err)
+		return fmt.Errorf("failed to delete category: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit category deletion: %v", err)
+	}
+	log.Printf("Category with id %d deleted successfully for user %d\n", categoryID, userID)
+	return nil
+}
@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+// ImportTransactions — см. PostgresDB.ImportTransactions
+func (db *SQLiteDB) ImportTransactions(parentCtx context.Context, userID int, rows []models.ImportRow, opts models.ImportOptions) (models.ImportResult, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 60*time.Second)
+	defer cancel()
+
+	result := models.ImportResult{DryRun: opts.DryRun, Total: len(rows), Rows: rows}
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var baseCurrency string
+	err = tx.QueryRowContext(ctx, `SELECT base_currency FROM users WHERE id = ?1`, userID).Scan(&baseCurrency)
+	if err != nil {
+		log.Printf("failed to retrieve base currency: %v", err)
+		return result, fmt.Errorf("failed to retrieve base currency: %v", err)
+	}
+
+	categories, err := sqliteImportCategories(ctx, tx, userID)
+	if err != nil {
+		return result, err
+	}
+	rules, err := sqliteCategoryRules(ctx, tx, userID)
+	if err != nil {
+		return result, err
+	}
+	classifyImportRows(rows, rules, categories, opts, &result)
+
+	duplicate, err := tx.PrepareContext(ctx, `SELECT EXISTS (SELECT 1 FROM transactions
+	                                          WHERE user_id = ?1 AND local_date(occurred_at, `+sqliteUserZone("?1")+`) = ?2
+	                                            AND amount = ?3 AND COALESCE(note, '') = ?4)`)
+	if err != nil {
+		return result, fmt.Errorf("failed to check duplicate transactions: %v", err)
+	}
+	defer duplicate.Close()
+	for i := range rows {
+		row := &rows[i]
+		if row.Status != models.ImportRowNew {
+			continue
+		}
+		if row.Transaction.Currency == "" {
+			row.Transaction.Currency = baseCurrency
+		}
+		var exists bool
+		err := duplicate.QueryRowContext(ctx, userID, models.NewDate(row.Transaction.OccurredAt.Time).String(),
+			int64(row.Transaction.Amount), row.Transaction.Note).Scan(&exists)
+		if err != nil {
+			log.Printf("failed to check duplicate transactions: %v", err)
+			return result, fmt.Errorf("failed to check duplicate transactions: %v", err)
+		}
+		if exists {
+			row.Status = models.ImportRowDuplicate
+		}
+	}
+
+	countImportRows(&result)
+
+	if opts.DryRun {
+		return result, nil
+	}
+	if result.Invalid > 0 {
+		return result, ErrImportInvalid
+	}
+
+	for _, name := range result.CreatedCategories {
+		var id int
+		err := tx.QueryRowContext(ctx, `INSERT INTO categories (name, user_id) VALUES (?1, ?2) RETURNING id`, name, userID).Scan(&id)
+		if err != nil {
+			log.Printf("failed to create category: %v", err)
+			return result, fmt.Errorf("failed to create category %q: %v", name, err)
+		}
+		categories[strings.ToLower(name)] = id
+	}
+
+	insert, err := tx.PrepareContext(ctx, `INSERT INTO transactions (is_income, amount, category_id, user_id, note, currency, occurred_at)
+	                                       VALUES (?1, ?2, ?3, ?4, ?5, ?6, `+sqliteLocalDay("?7", "?4")+`)`)
+	if err != nil {
+		return result, fmt.Errorf("failed to import transactions: %v", err)
+	}
+	defer insert.Close()
+	for i := range rows {
+		row := &rows[i]
+		if row.Status != models.ImportRowNew {
+			continue
+		}
+		t := &row.Transaction
+		if t.CategoryID == 0 {
+			t.CategoryID = categories[strings.ToLower(row.CategoryName)]
+		}
+		res, err := insert.ExecContext(ctx, t.IsIncome, int64(t.Amount), t.CategoryID, userID, t.Note, t.Currency,
+			models.NewDate(t.OccurredAt.Time).String())
+		if err != nil {
+			log.Printf("failed to import transactions: %v", err)
+			return result, fmt.Errorf("failed to import transactions: %v", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return result, fmt.Errorf("failed to import transactions: %v", err)
+		}
+		if len(t.Tags) > 0 {
+			if err := sqliteSetTransactionTags(ctx, tx, userID, int(id), t.Tags); err != nil {
+				return result, err
+			}
+		}
+		tags := t.Tags
+		err = scanSQLiteTransaction(tx.QueryRowContext(ctx, `SELECT `+sqliteTransactionColumns+` FROM transactions WHERE id = ?1`, id), t)
+		if err != nil {
+			log.Printf("failed to retrieve transaction: %v", err)
+			return result, fmt.Errorf("failed to retrieve transaction: %v", err)
+		}
+		t.Tags = tags
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("failed to commit import: %v", err)
+	}
+	log.Printf("Imported %d transactions for user %d (%d duplicates skipped)", result.Imported, userID, result.Duplicates)
+	return result, nil
+}
+
+func sqliteImportCategories(ctx context.Context, tx *sql.Tx, userID int) (map[string]int, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT id, name FROM categories WHERE user_id = ?1 ORDER BY id`, userID)
+	if err != nil {
+		log.Printf("failed to retrieve categories: %v", err)
+		return nil, fmt.Errorf("failed to retrieve categories: %v", err)
+	}
+	defer rows.Close()
+
+	categories := map[string]int{}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %v", err)
+		}
+		if _, ok := categories[strings.ToLower(name)]; !ok {
+			categories[strings.ToLower(name)] = id
+		}
+	}
+	return categories, rows.Err()
+}
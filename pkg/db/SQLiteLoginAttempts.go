@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+func (db *SQLiteDB) RecordLoginAttempt(parentCtx context.Context, a *models.LoginAttempt) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	_, err := db.db.ExecContext(ctx, `INSERT INTO login_attempts (user_id, email, ip, success, reason, created_at)
+	                                  VALUES (?1, ?2, ?3, ?4, ?5, ?6)`,
+		a.UserID, a.Email, a.IP, a.Success, a.Reason, sqliteTime(time.Now()))
+	if err != nil {
+		log.Printf("failed to record login attempt: %v", err)
+		return fmt.Errorf("failed to record login attempt: %v", err)
+	}
+	return nil
+}
+
+func (db *SQLiteDB) GetLoginFailures(parentCtx context.Context, email, ip string, since time.Time) (models.LoginFailures, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	// Агрегат MAX теряет тип колонки, поэтому момент читается строкой
+	var f models.LoginFailures
+	var accountLast, ipLast sql.NullString
+	err := db.db.QueryRowContext(ctx, `SELECT COUNT(*), MAX(created_at) FROM login_attempts
+	                                   WHERE email = ?1 AND reason IN `+loginFailureReasons+`
+	                                     AND created_at > ?2
+	                                     AND created_at > COALESCE((SELECT MAX(created_at) FROM login_attempts WHERE email = ?1 AND success), ?2)`,
+		email, sqliteTime(since)).Scan(&f.Account, &accountLast)
+	if err != nil {
+		log.Printf("failed to count login failures: %v", err)
+		return models.LoginFailures{}, fmt.Errorf("failed to count login failures: %v", err)
+	}
+	err = db.db.QueryRowContext(ctx, `SELECT COUNT(*), MAX(created_at) FROM login_attempts
+	                                  WHERE ip = ?1 AND reason IN `+loginFailureReasons+` AND created_at > ?2`,
+		ip, sqliteTime(since)).Scan(&f.IP, &ipLast)
+	if err != nil {
+		log.Printf("failed to count login failures: %v", err)
+		return models.LoginFailures{}, fmt.Errorf("failed to count login failures: %v", err)
+	}
+
+	if accountLast.Valid {
+		f.AccountLast, _ = time.Parse(sqliteTimeFormat, accountLast.String)
+	}
+	if ipLast.Valid {
+		f.IPLast, _ = time.Parse(sqliteTimeFormat, ipLast.String)
+	}
+	return f, nil
+}
+
+func (db *SQLiteDB) GetMailRequests(parentCtx context.Context, email, ip string, since time.Time) (models.LoginFailures, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var f models.LoginFailures
+	var accountLast, ipLast sql.NullString
+	err := db.db.QueryRowContext(ctx, `SELECT COUNT(*) FILTER (WHERE email = ?1), MAX(created_at) FILTER (WHERE email = ?1),
+	                                          COUNT(*) FILTER (WHERE ip = ?2), MAX(created_at) FILTER (WHERE ip = ?2)
+	                                   FROM login_attempts
+	                                   WHERE (email = ?1 OR ip = ?2) AND reason = ?3 AND created_at > ?4`,
+		email, ip, models.LoginMailRequested, sqliteTime(since)).Scan(&f.Account, &accountLast, &f.IP, &ipLast)
+	if err != nil {
+		log.Printf("failed to count mail requests: %v", err)
+		return models.LoginFailures{}, fmt.Errorf("failed to count mail requests: %v", err)
+	}
+
+	if accountLast.Valid {
+		f.AccountLast, _ = time.Parse(sqliteTimeFormat, accountLast.String)
+	}
+	if ipLast.Valid {
+		f.IPLast, _ = time.Parse(sqliteTimeFormat, ipLast.String)
+	}
+	return f, nil
+}
+
+func (db *SQLiteDB) GetLoginAttempts(parentCtx context.Context, userID int, limit, offset int) ([]models.LoginAttempt, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	rows, err := db.db.QueryContext(ctx, `SELECT id, user_id, email, ip, success, reason, created_at FROM login_attempts
+	                                      WHERE user_id = ?1 ORDER BY created_at DESC, id DESC LIMIT ?2 OFFSET ?3`, userID, limit, offset)
+	if err != nil {
+		log.Printf("failed to query login attempts: %v", err)
+		return nil, fmt.Errorf("failed to query login attempts: %v", err)
+	}
+	defer rows.Close()
+
+	attempts := []models.LoginAttempt{}
+	for rows.Next() {
+		var a models.LoginAttempt
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Email, &a.IP, &a.Success, &a.Reason, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan login attempt: %v", err)
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
@@ -0,0 +1,513 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+func (db *SQLiteDB) SaveExchangeRates(parentCtx context.Context, rates []models.ExchangeRate) (int, error) {
+	query := `INSERT INTO exchange_rates (rate_date, base_currency, quote_currency, rate)
+	          VALUES (?1, ?2, ?3, ?4)
+	          ON CONFLICT (base_currency, quote_currency, rate_date) DO UPDATE SET rate = excluded.rate`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 30*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	for _, r := range rates {
+		_, err := tx.ExecContext(ctx, query, r.Date.Format("2006-01-02"), strings.ToUpper(r.Base), strings.ToUpper(r.Quote), r.Rate)
+		if err != nil {
+			log.Printf("failed to save exchange rate: %v", err)
+			return 0, fmt.Errorf("failed to save exchange rate: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit exchange rates: %v", err)
+	}
+	return len(rates), nil
+}
+
+// sqliteSummaryTransactions — CTE tx для сводки: транзакции пользователя ?1 с местной даты
+// ?2 до начала местной даты ?3 без переводов, с суммой в базовой валюте (converted)
+func sqliteSummaryTransactions() string {
+	return `tx AS (
+			SELECT t.id, t.currency, t.is_income, t.amount, ` + sqliteConverted("t.amount") + ` AS converted
+			FROM transactions t
+			JOIN users u ON u.id = t.user_id
+			WHERE t.user_id = ?1 AND t.occurred_at >= ` + sqliteLocalDay("?2", "?1") + ` AND t.occurred_at < ` + sqliteLocalDay("?3", "?1") + `
+			  AND t.transfer_id IS NULL
+		)`
+}
+
+func (db *SQLiteDB) GetSummary(parentCtx context.Context, userID int, from, to time.Time) (models.Summary, error) {
+	query := `WITH ` + sqliteSummaryTransactions() + `
+		SELECT currency,
+			COALESCE(SUM(amount) FILTER (WHERE is_income), 0) AS total_income,
+			COALESCE(SUM(amount) FILTER (WHERE NOT is_income), 0) AS total_expense,
+			COALESCE(SUM(converted) FILTER (WHERE is_income), 0) AS converted_income,
+			COALESCE(SUM(converted) FILTER (WHERE NOT is_income), 0) AS converted_expense,
+			MAX(converted IS NULL) AS missing_rate
+		FROM tx
+		GROUP BY currency
+		ORDER BY currency`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var summary models.Summary
+	err := db.db.QueryRowContext(ctx, `SELECT base_currency FROM users WHERE id = ?1`, userID).Scan(&summary.Currency)
+	if err != nil {
+		log.Printf("failed to retrieve base currency: %v", err)
+		return models.Summary{}, err
+	}
+
+	fromDay, toDay := from.Format("2006-01-02"), to.AddDate(0, 0, 1).Format("2006-01-02")
+	rows, err := db.db.QueryContext(ctx, query, userID, fromDay, toDay)
+	if err != nil {
+		log.Printf("failed to retrieve summary: %v", err)
+		return models.Summary{}, err
+	}
+	defer rows.Close()
+
+	summary.ByCurrency = []models.CurrencySummary{}
+	for rows.Next() {
+		var cs models.CurrencySummary
+		var convertedIncome, convertedExpense models.Money
+		var missingRate bool
+		err := rows.Scan(&cs.Currency, &cs.TotalIncome, &cs.TotalExpense,
+			&convertedIncome, &convertedExpense, &missingRate)
+		if err != nil {
+			log.Printf("failed to scan summary: %v", err)
+			return models.Summary{}, err
+		}
+		cs.Balance = cs.TotalIncome - cs.TotalExpense
+		summary.ByCurrency = append(summary.ByCurrency, cs)
+
+		summary.TotalIncome += convertedIncome
+		summary.TotalExpense += convertedExpense
+		if missingRate {
+			summary.MissingRates = append(summary.MissingRates, cs.Currency)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating over rows: %v", err)
+		return models.Summary{}, err
+	}
+	summary.Balance = summary.TotalIncome - summary.TotalExpense
+
+	summary.ByTag, err = db.summaryByTag(ctx, userID, fromDay, toDay)
+	if err != nil {
+		return models.Summary{}, err
+	}
+	return summary, nil
+}
+
+func (db *SQLiteDB) summaryByTag(ctx context.Context, userID int, fromDay, toDay string) ([]models.TagSummary, error) {
+	query := `WITH ` + sqliteSummaryTransactions() + `
+		SELECT tg.name,
+			COALESCE(SUM(tx.converted) FILTER (WHERE tx.is_income), 0) AS total_income,
+			COALESCE(SUM(tx.converted) FILTER (WHERE NOT tx.is_income), 0) AS total_expense
+		FROM tx
+		JOIN transaction_tags tt ON tt.transaction_id = tx.id
+		JOIN tags tg ON tg.id = tt.tag_id
+		GROUP BY tg.name
+		ORDER BY tg.name`
+
+	rows, err := db.db.QueryContext(ctx, query, userID, fromDay, toDay)
+	if err != nil {
+		log.Printf("failed to retrieve tag summary: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var byTag []models.TagSummary
+	for rows.Next() {
+		var ts models.TagSummary
+		if err := rows.Scan(&ts.Tag, &ts.TotalIncome, &ts.TotalExpense); err != nil {
+			log.Printf("failed to scan tag summary: %v", err)
+			return nil, err
+		}
+		byTag = append(byTag, ts)
+	}
+	return byTag, rows.Err()
+}
+
+// sqliteReportTransactions — reportTransactions для SQLite; вместо местного времени
+// операции в выборке её местная дата occurred_on
+func sqliteReportTransactions(filter models.TransactionFilter, args []interface{}) (string, []interface{}) {
+	categoryID := filter.CategoryID
+	filter.CategoryID = nil
+	where, args := sqliteFilterClause("t.", filter, args)
+	if categoryID != nil {
+		args = append(args, *categoryID)
+		where += ` AND line.category_id IN ` + categoryTree("?"+strconv.Itoa(len(args)))
+	}
+	return `tx AS (
+			SELECT t.id, line.category_id, t.is_income, t.currency, local_date(t.occurred_at, u.time_zone) AS occurred_on,
+			       ` + sqliteConverted("line.amount") + ` AS converted
+			FROM transactions t
+			JOIN users u ON u.id = t.user_id
+			JOIN transaction_lines line ON line.transaction_id = t.id
+			WHERE t.user_id = ?1 AND t.transfer_id IS NULL` + where + `
+		)`, args
+}
+
+func (db *SQLiteDB) reportTotals(ctx context.Context, userID int, filter models.TransactionFilter) (income, expense models.Money, missing []string, err error) {
+	cte, args := sqliteReportTransactions(filter, []interface{}{userID})
+	query := `WITH ` + cte + `
+		SELECT COALESCE(SUM(converted) FILTER (WHERE is_income), 0),
+		       COALESCE(SUM(converted) FILTER (WHERE NOT is_income), 0),
+		       (SELECT json_group_array(currency) FROM (SELECT DISTINCT currency FROM tx WHERE converted IS NULL ORDER BY currency))
+		FROM tx`
+
+	err = db.db.QueryRowContext(ctx, query, args...).Scan(&income, &expense, jsonColumn{&missing})
+	if err != nil {
+		log.Printf("failed to retrieve report totals: %v", err)
+		return 0, 0, nil, fmt.Errorf("failed to retrieve report totals: %v", err)
+	}
+	if len(missing) == 0 {
+		missing = nil
+	}
+	return income, expense, missing, nil
+}
+
+func (db *SQLiteDB) GetCategoryReport(parentCtx context.Context, userID int, filter models.TransactionFilter) (models.CategoryReport, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var report models.CategoryReport
+	err := db.db.QueryRowContext(ctx, `SELECT base_currency FROM users WHERE id = ?1`, userID).Scan(&report.Currency)
+	if err != nil {
+		log.Printf("failed to retrieve base currency: %v", err)
+		return models.CategoryReport{}, fmt.Errorf("failed to retrieve base currency: %v", err)
+	}
+
+	report.TotalIncome, report.TotalExpense, report.MissingRates, err = db.reportTotals(ctx, userID, filter)
+	if err != nil {
+		return models.CategoryReport{}, err
+	}
+
+	cte, args := sqliteReportTransactions(filter, []interface{}{userID})
+	subtree := ""
+	if filter.CategoryID != nil {
+		args = append(args, *filter.CategoryID)
+		subtree = ` WHERE c.id IN ` + categoryTree("?"+strconv.Itoa(len(args)))
+	}
+	query := `WITH RECURSIVE ` + cte + `,
+		own AS (
+			SELECT category_id,
+			       COALESCE(SUM(converted) FILTER (WHERE is_income), 0) AS income,
+			       COALESCE(SUM(converted) FILTER (WHERE NOT is_income), 0) AS expense
+			FROM tx
+			GROUP BY category_id
+		),
+		totals AS (
+			SELECT SUM(income) AS income, SUM(expense) AS expense FROM own
+		),
+		tree AS (
+			SELECT id AS root_id, id FROM categories WHERE user_id = ?1
+			UNION ALL
+			SELECT tree.root_id, c.id FROM categories c JOIN tree ON c.parent_id = tree.id
+		)
+		SELECT c.id, c.name, c.parent_id, SUM(own.income), SUM(own.expense),
+		       COALESCE(ROUND(100.0 * SUM(own.income) / NULLIF(totals.income, 0), 2), 0),
+		       COALESCE(ROUND(100.0 * SUM(own.expense) / NULLIF(totals.expense, 0), 2), 0)
+		FROM categories c
+		JOIN tree ON tree.root_id = c.id
+		JOIN own ON own.category_id = tree.id
+		CROSS JOIN totals` + subtree + `
+		GROUP BY c.id, totals.income, totals.expense
+		ORDER BY SUM(own.expense) DESC, SUM(own.income) DESC, c.name`
+
+	rows, err := db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("failed to retrieve category report: %v", err)
+		return models.CategoryReport{}, fmt.Errorf("failed to retrieve category report: %v", err)
+	}
+	defer rows.Close()
+
+	report.Categories = []models.CategoryReportRow{}
+	for rows.Next() {
+		var row models.CategoryReportRow
+		err := rows.Scan(&row.CategoryID, &row.Name, &row.ParentID, &row.Income, &row.Expense,
+			&row.IncomeShare, &row.ExpenseShare)
+		if err != nil {
+			log.Printf("failed to scan category report: %v", err)
+			return models.CategoryReport{}, fmt.Errorf("failed to scan category report: %v", err)
+		}
+		report.Categories = append(report.Categories, row)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating over rows: %v", err)
+		return models.CategoryReport{}, err
+	}
+	return report, nil
+}
+
+// sqlitePeriods — начало периода для местной даты occurred_on, как date_trunc в PostgreSQL
+var sqlitePeriods = map[string]string{
+	models.IntervalDay:   `occurred_on`,
+	models.IntervalWeek:  `date(occurred_on, '-6 days', 'weekday 1')`,
+	models.IntervalMonth: `strftime('%Y-%m-01', occurred_on)`,
+	models.IntervalYear:  `strftime('%Y-01-01', occurred_on)`,
+}
+
+// periodStart возвращает начало периода interval, содержащего день d; недели начинаются с понедельника
+func periodStart(interval string, d time.Time) time.Time {
+	d = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case models.IntervalWeek:
+		return d.AddDate(0, 0, -(int(d.Weekday())+6)%7)
+	case models.IntervalMonth:
+		return d.AddDate(0, 0, 1-d.Day())
+	case models.IntervalYear:
+		return time.Date(d.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return d
+}
+
+// nextPeriod возвращает начало периода, следующего за периодом с началом start
+func nextPeriod(interval string, start time.Time) time.Time {
+	switch interval {
+	case models.IntervalWeek:
+		return start.AddDate(0, 0, 7)
+	case models.IntervalMonth:
+		return start.AddDate(0, 1, 0)
+	case models.IntervalYear:
+		return start.AddDate(1, 0, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// GetTimeSeries — см. PostgresDB.GetTimeSeries; ряд периодов строится в Go, так как
+// в SQLite нет generate_series
+func (db *SQLiteDB) GetTimeSeries(parentCtx context.Context, userID int, filter models.TransactionFilter, interval string) (models.TimeSeries, error) {
+	if !models.IsValidInterval(interval) || filter.From == nil || filter.To == nil {
+		return models.TimeSeries{}, fmt.Errorf("time series requires a valid interval and date range")
+	}
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	series := models.TimeSeries{Interval: interval}
+	err := db.db.QueryRowContext(ctx, `SELECT base_currency FROM users WHERE id = ?1`, userID).Scan(&series.Currency)
+	if err != nil {
+		log.Printf("failed to retrieve base currency: %v", err)
+		return models.TimeSeries{}, fmt.Errorf("failed to retrieve base currency: %v", err)
+	}
+
+	_, _, series.MissingRates, err = db.reportTotals(ctx, userID, filter)
+	if err != nil {
+		return models.TimeSeries{}, err
+	}
+
+	cte, args := sqliteReportTransactions(filter, []interface{}{userID})
+	query := `WITH ` + cte + `
+		SELECT ` + sqlitePeriods[interval] + ` AS period,
+		       COALESCE(SUM(converted) FILTER (WHERE is_income), 0),
+		       COALESCE(SUM(converted) FILTER (WHERE NOT is_income), 0)
+		FROM tx
+		GROUP BY 1`
+
+	rows, err := db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("failed to retrieve time series: %v", err)
+		return models.TimeSeries{}, fmt.Errorf("failed to retrieve time series: %v", err)
+	}
+	defer rows.Close()
+
+	points := map[string]models.TimeSeriesPoint{}
+	for rows.Next() {
+		var period string
+		var p models.TimeSeriesPoint
+		if err := rows.Scan(&period, &p.Income, &p.Expense); err != nil {
+			log.Printf("failed to scan time series: %v", err)
+			return models.TimeSeries{}, fmt.Errorf("failed to scan time series: %v", err)
+		}
+		points[period] = p
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating over rows: %v", err)
+		return models.TimeSeries{}, err
+	}
+
+	series.Points = []models.TimeSeriesPoint{}
+	last := periodStart(interval, *filter.To)
+	for start := periodStart(interval, *filter.From); !start.After(last); start = nextPeriod(interval, start) {
+		p := points[start.Format("2006-01-02")]
+		p.Period = models.NewDate(start)
+		p.Balance = p.Income - p.Expense
+		series.Points = append(series.Points, p)
+	}
+	return series, nil
+}
+
+func (db *SQLiteDB) AddBudget(parentCtx context.Context, userID int, b *models.Budget) (models.Budget, error) {
+	query := `INSERT INTO budgets (user_id, category_id, amount, period, start_date, end_date, rollover)
+	          VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7) RETURNING ` + budgetColumns
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var budget models.Budget
+	err := scanBudget(db.db.QueryRowContext(ctx, query, userID, b.CategoryID, int64(b.Amount), b.Period,
+		b.StartDate.String(), sqliteDate(b.EndDate), b.Rollover), &budget)
+	if err != nil {
+		log.Printf("failed to insert budget: %v", err)
+		return models.Budget{}, fmt.Errorf("failed to insert budget: %v", err)
+	}
+	return budget, nil
+}
+
+func (db *SQLiteDB) GetBudgetByID(parentCtx context.Context, userID int, budgetID int) (models.Budget, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var budget models.Budget
+	err := scanBudget(db.db.QueryRowContext(ctx, `SELECT `+budgetColumns+` FROM budgets WHERE id = ?1 AND user_id = ?2`, budgetID, userID), &budget)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Budget{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve budget: %v", err)
+		return models.Budget{}, fmt.Errorf("failed to retrieve budget: %v", err)
+	}
+	return budget, nil
+}
+
+func (db *SQLiteDB) UpdateBudget(parentCtx context.Context, userID int, budgetID int, u *models.BudgetUpdate) (models.Budget, error) {
+	set := updateSet{}
+	if u.Amount != nil {
+		set.add("amount", int64(*u.Amount))
+	}
+	if u.EndDate != nil {
+		set.add("end_date", u.EndDate.String())
+	}
+	if u.Rollover != nil {
+		set.add("rollover", *u.Rollover)
+	}
+	if len(set.sets) == 0 {
+		return models.Budget{}, fmt.Errorf("nothing to update")
+	}
+
+	query := rebind(`UPDATE budgets SET ` + set.where(budgetID, userID) + ` RETURNING ` + budgetColumns)
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var budget models.Budget
+	err := scanBudget(db.db.QueryRowContext(ctx, query, set.args...), &budget)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Budget{}, ErrNotFound
+		}
+		log.Printf("failed to update budget: %v", err)
+		return models.Budget{}, fmt.Errorf("failed to update budget: %v", err)
+	}
+	return budget, nil
+}
+
+func (db *SQLiteDB) DeleteBudget(parentCtx context.Context, userID int, budgetID int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	res, err := db.db.ExecContext(ctx, `DELETE FROM budgets WHERE id = ?1 AND user_id = ?2`, budgetID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete budget: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (db *SQLiteDB) GetBudgetStatuses(parentCtx context.Context, userID int, on time.Time) ([]models.BudgetStatus, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets
+	          WHERE user_id = ?1
+	            AND (period = 'custom' OR (start_date <= ?2 AND (end_date IS NULL OR end_date >= ?3)))
+	          ORDER BY id`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 10*time.Second)
+	defer cancel()
+
+	monthStart := time.Date(on.Year(), on.Month(), 1, 0, 0, 0, 0, time.UTC)
+	rows, err := db.db.QueryContext(ctx, query, userID, on.Format("2006-01-02"), monthStart.Format("2006-01-02"))
+	if err != nil {
+		log.Printf("failed to retrieve budgets: %v", err)
+		return nil, fmt.Errorf("failed to retrieve budgets: %v", err)
+	}
+	var budgets []models.Budget
+	for rows.Next() {
+		var b models.Budget
+		if err := scanBudget(rows, &b); err != nil {
+			rows.Close()
+			log.Printf("failed to scan budget: %v", err)
+			return nil, fmt.Errorf("failed to scan budget: %v", err)
+		}
+		budgets = append(budgets, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]models.BudgetStatus, 0, len(budgets))
+	for _, b := range budgets {
+		status, err := budgetStatus(ctx, b, on, db.budgetSpending)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (db *SQLiteDB) budgetSpending(ctx context.Context, b models.Budget, from, to time.Time) (map[time.Time]models.Money, error) {
+	query := `
+		SELECT substr(local_date(t.occurred_at, u.time_zone), 1, 7) AS month,
+		       COALESCE(SUM(` + sqliteConverted("line.amount") + `), 0)
+		FROM transactions t
+		JOIN users u ON u.id = t.user_id
+		JOIN transaction_lines line ON line.transaction_id = t.id
+		WHERE t.user_id = ?1 AND NOT t.is_income AND t.transfer_id IS NULL
+		  AND (?2 IS NULL OR line.category_id IN ` + categoryTree("?2") + `)
+		  AND t.occurred_at >= ` + sqliteLocalDay("?3", "?1") + ` AND t.occurred_at < ` + sqliteLocalDay("?4", "?1") + `
+		GROUP BY month`
+
+	rows, err := db.db.QueryContext(ctx, query, b.UserID, b.CategoryID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		log.Printf("failed to retrieve budget spending: %v", err)
+		return nil, fmt.Errorf("failed to retrieve budget spending: %v", err)
+	}
+	defer rows.Close()
+
+	spent := map[time.Time]models.Money{}
+	for rows.Next() {
+		var month string
+		var amount models.Money
+		if err := rows.Scan(&month, &amount); err != nil {
+			return nil, fmt.Errorf("failed to scan budget spending: %v", err)
+		}
+		m, err := time.Parse("2006-01", month)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan budget spending: %v", err)
+		}
+		spent[m] = amount
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return spent, nil
+}
@@ -0,0 +1,372 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+func (db *SQLiteDB) AddRecurringRule(parentCtx context.Context, userID int, r *models.RecurringRule) (models.RecurringRule, error) {
+	query := `INSERT INTO recurring_rules (user_id, is_income, amount, currency, category_id, note, frequency, interval,
+	                                       day_of_month, start_date, end_date, max_occurrences, next_date)
+	          VALUES (?1, ?2, ?3, COALESCE(NULLIF(?4, ''), (SELECT base_currency FROM users WHERE id = ?1)),
+	                  ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12, ?13)
+	          RETURNING ` + recurringColumns
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var rule models.RecurringRule
+	err := scanRecurringRule(db.db.QueryRowContext(ctx, query, userID, r.IsIncome, int64(r.Amount), r.Currency, r.CategoryID, r.Note,
+		r.Frequency, r.Interval, r.DayOfMonth, r.StartDate.String(), sqliteDate(r.EndDate), r.MaxOccurrences, sqliteDate(r.NextDate)), &rule)
+	if err != nil {
+		log.Printf("failed to insert recurring rule: %v", err)
+		return models.RecurringRule{}, fmt.Errorf("failed to insert recurring rule: %v", err)
+	}
+	return rule, nil
+}
+
+func (db *SQLiteDB) GetRecurringRules(parentCtx context.Context, userID int) ([]models.RecurringRule, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	rows, err := db.db.QueryContext(ctx, `SELECT `+recurringColumns+` FROM recurring_rules WHERE user_id = ?1 ORDER BY id`, userID)
+	if err != nil {
+		log.Printf("failed to retrieve recurring rules: %v", err)
+		return nil, fmt.Errorf("failed to retrieve recurring rules: %v", err)
+	}
+	rules, err := collectSQLiteRecurringRules(rows)
+	if err != nil {
+		log.Printf("failed to scan recurring rule: %v", err)
+		return nil, fmt.Errorf("failed to scan recurring rule: %v", err)
+	}
+	return rules, nil
+}
+
+func collectSQLiteRecurringRules(rows *sql.Rows) ([]models.RecurringRule, error) {
+	defer rows.Close()
+
+	rules := []models.RecurringRule{}
+	for rows.Next() {
+		var r models.RecurringRule
+		if err := scanRecurringRule(rows, &r); err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+func collectSQLiteDueRules(rows *sql.Rows) ([]dueRule, error) {
+	defer rows.Close()
+
+	rules := []dueRule{}
+	for rows.Next() {
+		var r dueRule
+		if err := scanRecurringRule(zoneRow{rows, &r.timeZone}, &r.RecurringRule); err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+func (db *SQLiteDB) UpdateRecurringRule(parentCtx context.Context, userID int, ruleID int, u *models.RecurringRuleUpdate) (models.RecurringRule, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.RecurringRule{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var rule models.RecurringRule
+	err = scanRecurringRule(tx.QueryRowContext(ctx, `SELECT `+recurringColumns+` FROM recurring_rules
+	                                                 WHERE id = ?1 AND user_id = ?2`, ruleID, userID), &rule)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.RecurringRule{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve recurring rule: %v", err)
+		return models.RecurringRule{}, fmt.Errorf("failed to retrieve recurring rule: %v", err)
+	}
+
+	if u.Amount != nil {
+		rule.Amount = *u.Amount
+	}
+	if u.CategoryID != nil {
+		rule.CategoryID = *u.CategoryID
+	}
+	if u.Note != nil {
+		rule.Note = *u.Note
+	}
+	if u.EndDate != nil {
+		if u.EndDate.Before(rule.StartDate.Time) {
+			return models.RecurringRule{}, ErrEndBeforeStart
+		}
+		rule.EndDate = u.EndDate
+	}
+	if u.MaxOccurrences != nil {
+		rule.MaxOccurrences = u.MaxOccurrences
+	}
+	rule.NextDate = rule.NextOccurrence()
+
+	query := `UPDATE recurring_rules
+	          SET amount = ?1, category_id = ?2, note = ?3, end_date = ?4, max_occurrences = ?5, next_date = ?6
+	          WHERE id = ?7 AND user_id = ?8
+	          RETURNING ` + recurringColumns
+	err = scanRecurringRule(tx.QueryRowContext(ctx, query, int64(rule.Amount), rule.CategoryID, rule.Note, sqliteDate(rule.EndDate),
+		rule.MaxOccurrences, sqliteDate(rule.NextDate), ruleID, userID), &rule)
+	if err != nil {
+		log.Printf("failed to update recurring rule: %v", err)
+		return models.RecurringRule{}, fmt.Errorf("failed to update recurring rule: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.RecurringRule{}, fmt.Errorf("failed to commit recurring rule update: %v", err)
+	}
+	return rule, nil
+}
+
+func (db *SQLiteDB) DeleteRecurringRule(parentCtx context.Context, userID int, ruleID int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	res, err := db.db.ExecContext(ctx, `DELETE FROM recurring_rules WHERE id = ?1 AND user_id = ?2`, ruleID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recurring rule: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// materializeSQLiteRule — см. materializeRule
+func materializeSQLiteRule(ctx context.Context, tx *sql.Tx, insert string, rule models.RecurringRule, today time.Time) (created int, err error) {
+	if _, err := tx.ExecContext(ctx, `SAVEPOINT recurring_rule`); err != nil {
+		return 0, fmt.Errorf("failed to create savepoint: %v", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.ExecContext(ctx, `ROLLBACK TO recurring_rule`)
+		}
+		tx.ExecContext(ctx, `RELEASE recurring_rule`)
+	}()
+
+	for rule.NextDate != nil && !rule.NextDate.After(today) {
+		res, err := tx.ExecContext(ctx, insert, rule.IsIncome, int64(rule.Amount), rule.CategoryID, rule.UserID, rule.Note,
+			rule.Currency, rule.NextDate.String(), rule.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert recurring transaction: %v", err)
+		}
+		n, _ := res.RowsAffected()
+		created += int(n)
+
+		rule.Occurrences++
+		rule.NextDate = rule.NextOccurrence()
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE recurring_rules SET occurrences = ?1, next_date = ?2 WHERE id = ?3`,
+		rule.Occurrences, sqliteDate(rule.NextDate), rule.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to advance recurring rule: %v", err)
+	}
+	return created, nil
+}
+
+// MaterializeRecurring — см. PostgresDB.MaterializeRecurring. Транзакция SQLite держит
+// блокировку записи всей базы, поэтому правила не блокируются по отдельности.
+func (db *SQLiteDB) MaterializeRecurring(parentCtx context.Context, now time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 30*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT `+recurringColumns+`, `+sqliteUserZone("recurring_rules.user_id")+`
+	                                   FROM recurring_rules
+	                                   WHERE next_date <= ?1
+	                                   ORDER BY id`, dueCandidatesUntil(now).String())
+	if err != nil {
+		log.Printf("failed to retrieve due recurring rules: %v", err)
+		return 0, fmt.Errorf("failed to retrieve due recurring rules: %v", err)
+	}
+	rules, err := collectSQLiteDueRules(rows)
+	if err != nil {
+		log.Printf("failed to scan recurring rule: %v", err)
+		return 0, fmt.Errorf("failed to scan recurring rule: %v", err)
+	}
+
+	insert := `INSERT INTO transactions (is_income, amount, category_id, user_id, note, currency, occurred_at,
+	                                     recurring_rule_id, occurrence_date)
+	           VALUES (?1, ?2, ?3, ?4, ?5, ?6, ` + sqliteLocalDay("?7", "?4") + `, ?8, ?7)
+	           ON CONFLICT (recurring_rule_id, occurrence_date) DO NOTHING`
+
+	created := 0
+	for _, rule := range rules {
+		today, err := rule.today(now)
+		if err != nil {
+			log.Printf("skipping recurring rule %d: %v", rule.ID, err)
+			continue
+		}
+		// Кандидат, дата которого в поясе пользователя ещё не наступила
+		if rule.NextDate.After(today) {
+			continue
+		}
+		n, err := materializeSQLiteRule(ctx, tx, insert, rule.RecurringRule, today)
+		if err != nil {
+			// Ошибка одного правила не должна останавливать проведение остальных
+			log.Printf("skipping recurring rule %d: %v", rule.ID, err)
+			continue
+		}
+		created += n
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit recurring transactions: %v", err)
+	}
+	return created, nil
+}
+
+func scanSQLiteCategoryRule(row sqliteRow, r *models.CategoryRule) error {
+	return row.Scan(&r.ID, &r.UserID, &r.Name, &r.Priority, &r.NoteContains, &r.NoteRegex,
+		&r.MinAmount, &r.MaxAmount, &r.IsIncome, &r.AccountID, &r.CategoryID, jsonColumn{&r.Tags}, &r.CreatedAt)
+}
+
+func (db *SQLiteDB) AddCategoryRule(parentCtx context.Context, userID int, r *models.CategoryRule) (models.CategoryRule, error) {
+	query := `INSERT INTO category_rules (user_id, name, priority, note_contains, note_regex, min_amount, max_amount,
+	                                      is_income, account_id, category_id, tags)
+	          VALUES (?1, ?2, ?3, NULLIF(?4, ''), NULLIF(?5, ''), ?6, ?7, ?8, ?9, ?10, ?11)
+	          ` + categoryRuleReturning
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var rule models.CategoryRule
+	err := scanSQLiteCategoryRule(db.db.QueryRowContext(ctx, query, userID, r.Name, r.Priority, r.NoteContains, r.NoteRegex,
+		sqliteMoney(r.MinAmount), sqliteMoney(r.MaxAmount), r.IsIncome, r.AccountID, r.CategoryID, sqliteJSON(ruleTags(r.Tags))), &rule)
+	if err != nil {
+		log.Printf("failed to insert category rule: %v", err)
+		return models.CategoryRule{}, fmt.Errorf("failed to insert category rule: %v", err)
+	}
+	return rule, nil
+}
+
+func (db *SQLiteDB) GetCategoryRules(parentCtx context.Context, userID int) ([]models.CategoryRule, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	return sqliteCategoryRules(ctx, db.db, userID)
+}
+
+// sqliteCategoryRules читает правила пользователя в порядке применения; q — база или транзакция
+func sqliteCategoryRules(ctx context.Context, q interface {
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+}, userID int) ([]models.CategoryRule, error) {
+	rows, err := q.QueryContext(ctx, categoryRuleSelect+` WHERE user_id = ?1 ORDER BY priority, id`, userID)
+	if err != nil {
+		log.Printf("failed to retrieve category rules: %v", err)
+		return nil, fmt.Errorf("failed to retrieve category rules: %v", err)
+	}
+	defer rows.Close()
+
+	rules := []models.CategoryRule{}
+	for rows.Next() {
+		var r models.CategoryRule
+		if err := scanSQLiteCategoryRule(rows, &r); err != nil {
+			log.Printf("failed to scan category rule: %v", err)
+			return nil, fmt.Errorf("failed to scan category rule: %v", err)
+		}
+		rules = append(rules, r)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating over rows: %v", err)
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (db *SQLiteDB) UpdateCategoryRule(parentCtx context.Context, userID int, ruleID int, r *models.CategoryRule) (models.CategoryRule, error) {
+	query := `UPDATE category_rules
+	          SET name = ?3, priority = ?4, note_contains = NULLIF(?5, ''), note_regex = NULLIF(?6, ''),
+	              min_amount = ?7, max_amount = ?8, is_income = ?9, account_id = ?10, category_id = ?11, tags = ?12
+	          WHERE id = ?1 AND user_id = ?2
+	          ` + categoryRuleReturning
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var rule models.CategoryRule
+	err := scanSQLiteCategoryRule(db.db.QueryRowContext(ctx, query, ruleID, userID, r.Name, r.Priority, r.NoteContains, r.NoteRegex,
+		sqliteMoney(r.MinAmount), sqliteMoney(r.MaxAmount), r.IsIncome, r.AccountID, r.CategoryID, sqliteJSON(ruleTags(r.Tags))), &rule)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.CategoryRule{}, ErrNotFound
+		}
+		log.Printf("failed to update category rule: %v", err)
+		return models.CategoryRule{}, fmt.Errorf("failed to update category rule: %v", err)
+	}
+	return rule, nil
+}
+
+func (db *SQLiteDB) DeleteCategoryRule(parentCtx context.Context, userID int, ruleID int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	res, err := db.db.ExecContext(ctx, `DELETE FROM category_rules WHERE id = ?1 AND user_id = ?2`, ruleID, userID)
+	if err != nil {
+		log.Printf("failed to delete category rule: %v", err)
+		return fmt.Errorf("failed to delete category rule: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (db *SQLiteDB) ApplyRuleMatches(parentCtx context.Context, userID int, matches []models.RuleMatch) (int, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 60*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	applied := 0
+	for _, m := range matches {
+		res, err := tx.ExecContext(ctx, `UPDATE transactions SET category_id = ?1, updated_at = ?4
+		                                 WHERE id = ?2 AND user_id = ?3 AND transfer_id IS NULL
+		                                   AND NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = transactions.id)`,
+			m.CategoryID, m.TransactionID, userID, sqliteTime(time.Now()))
+		if err != nil {
+			log.Printf("failed to apply category rule: %v", err)
+			return 0, fmt.Errorf("failed to apply category rule: %v", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			continue
+		}
+		if len(m.AddedTags) > 0 {
+			if err := sqliteSetTransactionTags(ctx, tx, userID, m.TransactionID, m.Tags); err != nil {
+				return 0, err
+			}
+		}
+		applied++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	log.Printf("Category rules changed %d transactions for user %d\n", applied, userID)
+	return applied, nil
+}
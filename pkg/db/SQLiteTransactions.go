@@ -0,0 +1,468 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+// sqliteTransactionColumns — transactionColumns для SQLite: теги и строки разбивки
+// возвращаются JSON-массивами, суммы строк — строками "25.50"
+const sqliteTransactionColumns = `id, is_income, amount, COALESCE(category_id, 0), user_id, COALESCE(note, ''), occurred_at, created_at, updated_at, currency,
+	recurring_rule_id, account_id, transfer_id,
+	(SELECT json_group_array(tg.name ORDER BY tg.name) FROM transaction_tags tt JOIN tags tg ON tg.id = tt.tag_id
+	  WHERE tt.transaction_id = transactions.id),
+	(SELECT json_group_array(json_object('category_id', s.category_id, 'amount', printf('%d.%02d', s.amount / 100, s.amount % 100),
+	                                     'note', s.note) ORDER BY s.id)
+	   FROM transaction_splits s WHERE s.transaction_id = transactions.id)`
+
+func scanSQLiteTransaction(row sqliteRow, t *models.Transaction) error {
+	return row.Scan(&t.ID, &t.IsIncome, &t.Amount, &t.CategoryID, &t.UserID, &t.Note, &t.OccurredAt, &t.CreatedAt, &t.UpdatedAt, &t.Currency,
+		&t.RecurringRuleID, &t.AccountID, &t.TransferID, jsonColumn{&t.Tags}, jsonColumn{&t.Splits})
+}
+
+// sqliteFilterClause — filterClause для SQLite; ?1 — id пользователя
+func sqliteFilterClause(prefix string, filter models.TransactionFilter, args []interface{}) (string, []interface{}) {
+	clause := ""
+	param := func(value interface{}) string {
+		args = append(args, value)
+		return "?" + strconv.Itoa(len(args))
+	}
+
+	if filter.IsIncome != nil {
+		clause += ` AND ` + prefix + `is_income = ` + param(*filter.IsIncome)
+	}
+	if filter.CategoryID != nil {
+		tree := categoryTree(param(*filter.CategoryID))
+		clause += ` AND (EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = ` + prefix + `id
+		                         AND s.category_id IN ` + tree + `)
+		             OR NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = ` + prefix + `id)
+		                AND ` + prefix + `category_id IN ` + tree + `)`
+	}
+	if filter.From != nil {
+		clause += ` AND ` + prefix + `occurred_at >= ` + sqliteLocalDay(param(filter.From.Format("2006-01-02")), "?1")
+	}
+	if filter.To != nil {
+		clause += ` AND ` + prefix + `occurred_at < ` + sqliteLocalDay(param(filter.To.AddDate(0, 0, 1).Format("2006-01-02")), "?1")
+	}
+	if len(filter.Tags) > 0 {
+		n := param(sqliteJSON(filter.Tags))
+		tagged := `SELECT COUNT(*) FROM transaction_tags tt JOIN tags tg ON tg.id = tt.tag_id
+		           WHERE tt.transaction_id = ` + prefix + `id AND tg.name IN (SELECT value FROM json_each(` + n + `))`
+		if filter.AllTags {
+			clause += ` AND (` + tagged + `) = json_array_length(` + n + `)`
+		} else {
+			clause += ` AND (` + tagged + `) > 0`
+		}
+	}
+	return clause, args
+}
+
+func (db *SQLiteDB) AddTransaction(parentCtx context.Context, userID int, t *models.Transaction) (models.Transaction, error) {
+	at := t.OccurredAt
+	if at.IsZero() {
+		at = models.DateTime{Time: time.Now()}
+	}
+	occurred, occurredArg := sqliteOccurredAt(at, "?8", "?4")
+	query := `INSERT INTO transactions (is_income, amount, category_id, user_id, note, currency, account_id, occurred_at)
+	          VALUES (?1, ?2, ?3, ?4, ?5,
+	                  COALESCE(NULLIF(?6, ''),
+	                           (SELECT currency FROM accounts WHERE id = ?7),
+	                           (SELECT base_currency FROM users WHERE id = ?4)),
+	                  ?7, ` + occurred + `)`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, query, t.IsIncome, int64(t.Amount), t.CategoryID, userID, t.Note, t.Currency, t.AccountID,
+		occurredArg)
+	if err != nil {
+		log.Printf("failed to insert transaction: %v", err)
+		return models.Transaction{}, fmt.Errorf("failed to insert transaction: %v", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("failed to insert transaction: %v", err)
+	}
+
+	if len(t.Tags) > 0 {
+		if err := sqliteSetTransactionTags(ctx, tx, userID, int(id), t.Tags); err != nil {
+			return models.Transaction{}, err
+		}
+	}
+	if len(t.Splits) > 0 {
+		if err := sqliteSetTransactionSplits(ctx, tx, int(id), t.Splits); err != nil {
+			return models.Transaction{}, err
+		}
+	}
+
+	var transaction models.Transaction
+	err = scanSQLiteTransaction(tx.QueryRowContext(ctx, `SELECT `+sqliteTransactionColumns+` FROM transactions WHERE id = ?1`, id),
+		&transaction)
+	if err != nil {
+		log.Printf("failed to retrieve transaction: %v", err)
+		return models.Transaction{}, fmt.Errorf("failed to retrieve transaction: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Transaction{}, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return transaction, nil
+}
+
+func (db *SQLiteDB) CheckCategory(parentCtx context.Context, userID int, categoryID int) (exists bool, err error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	err = db.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM categories WHERE id = ?1 AND user_id = ?2)`,
+		categoryID, userID).Scan(&exists)
+	if err != nil {
+		log.Printf("database error during category check: %v", err)
+		return false, fmt.Errorf("database error during category check: %v", err)
+	}
+	return exists, nil
+}
+
+func (db *SQLiteDB) GetTransactions(parentCtx context.Context, userID int, filter models.TransactionFilter, limit, offset int) ([]*models.Transaction, error) {
+	where, args := sqliteFilterClause("transactions.", filter, []interface{}{userID})
+	query := `SELECT ` + sqliteTransactionColumns + ` FROM transactions WHERE user_id = ?1` + where
+	query += fmt.Sprintf(` ORDER BY occurred_at DESC, id DESC LIMIT ?%d OFFSET ?%d`, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	rows, err := db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("failed to retrieve transactions: %v", err)
+		return []*models.Transaction{}, err
+	}
+	defer rows.Close()
+
+	var transactions []*models.Transaction
+	for rows.Next() {
+		var transaction models.Transaction
+		if err := scanSQLiteTransaction(rows, &transaction); err != nil {
+			log.Printf("failed to scan transaction: %v", err)
+			return []*models.Transaction{}, err
+		}
+		transactions = append(transactions, &transaction)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("error iterating over rows: %v", err)
+		return []*models.Transaction{}, err
+	}
+	return transactions, nil
+}
+
+func (db *SQLiteDB) GetTransactionByID(parentCtx context.Context, userID int, transactionID int) (models.Transaction, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var transaction models.Transaction
+	err := scanSQLiteTransaction(db.db.QueryRowContext(ctx,
+		`SELECT `+sqliteTransactionColumns+` FROM transactions WHERE id = ?1 AND user_id = ?2`, transactionID, userID), &transaction)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Transaction{}, ErrNotFound
+		}
+		log.Printf("failed to scan: %v", err)
+		return models.Transaction{}, fmt.Errorf("failed to retrieve transaction: %v", err)
+	}
+	return transaction, nil
+}
+
+func (db *SQLiteDB) UpdateTransaction(parentCtx context.Context, userID int, transactionID int, u *models.TransactionUpdate) (models.Transaction, error) {
+	set := updateSet{}
+	if u.IsIncome != nil {
+		set.add("is_income", *u.IsIncome)
+	}
+	if u.Amount != nil {
+		set.add("amount", int64(*u.Amount))
+	}
+	if u.CategoryID != nil {
+		set.add("category_id", *u.CategoryID)
+	}
+	if u.Note != nil {
+		set.add("note", *u.Note)
+	}
+	if u.OccurredAt != nil {
+		occurred, arg := sqliteOccurredAt(*u.OccurredAt, "$"+strconv.Itoa(len(set.args)+1), "user_id")
+		set.args = append(set.args, arg)
+		set.sets = append(set.sets, "occurred_at = "+occurred)
+	}
+	if u.Currency != nil {
+		set.add("currency", *u.Currency)
+	}
+	if u.AccountID != nil {
+		var accountID *int
+		if *u.AccountID != 0 {
+			accountID = u.AccountID
+		}
+		set.add("account_id", accountID)
+	}
+	if len(set.sets) == 0 && u.Tags == nil && u.Splits == nil {
+		return db.GetTransactionByID(parentCtx, userID, transactionID)
+	}
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var transferID *int
+	err = tx.QueryRowContext(ctx, `SELECT transfer_id FROM transactions WHERE id = ?1 AND user_id = ?2`,
+		transactionID, userID).Scan(&transferID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Transaction{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve transaction: %v", err)
+		return models.Transaction{}, fmt.Errorf("failed to retrieve transaction: %v", err)
+	}
+	if transferID != nil {
+		return models.Transaction{}, ErrTransferTransaction
+	}
+
+	set.add("updated_at", sqliteTime(time.Now()))
+	if _, err := tx.ExecContext(ctx, rebind(`UPDATE transactions SET `+set.where(transactionID, userID)), set.args...); err != nil {
+		log.Printf("failed to update transaction: %v", err)
+		return models.Transaction{}, fmt.Errorf("failed to update transaction: %v", err)
+	}
+
+	if u.Splits != nil {
+		if err := sqliteSetTransactionSplits(ctx, tx, transactionID, *u.Splits); err != nil {
+			return models.Transaction{}, err
+		}
+	}
+
+	var splitMismatch bool
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE((SELECT SUM(amount) FROM transaction_splits WHERE transaction_id = ?1), amount) <> amount
+	                               FROM transactions WHERE id = ?1`, transactionID).Scan(&splitMismatch)
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("failed to check transaction splits: %v", err)
+	}
+	if splitMismatch {
+		return models.Transaction{}, ErrSplitMismatch
+	}
+
+	var mismatch bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM transactions t JOIN accounts a ON a.id = t.account_id
+	                                              WHERE t.id = ?1 AND a.currency <> t.currency)`, transactionID).Scan(&mismatch)
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("failed to check account currency: %v", err)
+	}
+	if mismatch {
+		return models.Transaction{}, ErrCurrencyMismatch
+	}
+
+	if u.Tags != nil {
+		if err := sqliteSetTransactionTags(ctx, tx, userID, transactionID, *u.Tags); err != nil {
+			return models.Transaction{}, err
+		}
+	}
+
+	var transaction models.Transaction
+	err = scanSQLiteTransaction(tx.QueryRowContext(ctx, `SELECT `+sqliteTransactionColumns+` FROM transactions WHERE id = ?1`,
+		transactionID), &transaction)
+	if err != nil {
+		log.Printf("failed to retrieve transaction: %v", err)
+		return models.Transaction{}, fmt.Errorf("failed to retrieve transaction: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Transaction{}, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	log.Printf("Transaction with id %d updated successfully for user %d\n", transactionID, userID)
+	return transaction, nil
+}
+
+// DeleteTransaction удаляет транзакцию (а транзакцию перевода — вместе с переводом
+// и второй его половиной) и возвращает ключи файлов удалённых вложений
+func (db *SQLiteDB) DeleteTransaction(parentCtx context.Context, userID int, transactionID int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var transferID *int
+	err = tx.QueryRowContext(ctx, `SELECT transfer_id FROM transactions WHERE id = ?1 AND user_id = ?2`,
+		transactionID, userID).Scan(&transferID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to delete transaction: %v", err)
+	}
+
+	var keys []string
+	err = tx.QueryRowContext(ctx, `SELECT json_group_array(a.storage_key)
+	                               FROM attachments a
+	                               JOIN transactions t ON t.id = a.transaction_id
+	                               WHERE t.user_id = ?2 AND (t.id = ?1 OR t.transfer_id = ?3)`,
+		transactionID, userID, transferID).Scan(jsonColumn{&keys})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attachments: %v", err)
+	}
+
+	// Транзакции перевода удаляются каскадом вместе с переводом
+	if transferID != nil {
+		_, err = tx.ExecContext(ctx, `DELETE FROM transfers WHERE id = ?1 AND user_id = ?2`, *transferID, userID)
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM transactions WHERE id = ?1 AND user_id = ?2`, transactionID, userID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete transaction: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	log.Printf("Transaction with id %d deleted successfully for user %d\n", transactionID, userID)
+	return keys, nil
+}
+
+func (db *SQLiteDB) ExportTransactions(parentCtx context.Context, userID int, filter models.TransactionFilter, write func(models.ExportedTransaction) error) error {
+	where, args := sqliteFilterClause("t.", filter, []interface{}{userID})
+	query := `SELECT t.id, t.is_income, t.amount, COALESCE(t.category_id, 0), t.user_id, COALESCE(t.note, ''), t.occurred_at, t.created_at, t.updated_at,
+	                 t.currency, t.recurring_rule_id, t.account_id, t.transfer_id, COALESCE(c.name, ''),
+	                 (SELECT json_group_array(tg.name ORDER BY tg.name) FROM transaction_tags tt JOIN tags tg ON tg.id = tt.tag_id
+	                   WHERE tt.transaction_id = t.id)
+	          FROM transactions t
+	          LEFT JOIN categories c ON c.id = t.category_id
+	          WHERE t.user_id = ?1` + where + `
+	          ORDER BY t.occurred_at, t.id`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Minute)
+	defer cancel()
+
+	rows, err := db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("failed to export transactions: %v", err)
+		return fmt.Errorf("failed to export transactions: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.ExportedTransaction
+		t := &e.Transaction
+		err := rows.Scan(&t.ID, &t.IsIncome, &t.Amount, &t.CategoryID, &t.UserID, &t.Note, &t.OccurredAt, &t.CreatedAt, &t.UpdatedAt,
+			&t.Currency, &t.RecurringRuleID, &t.AccountID, &t.TransferID, &e.CategoryName, jsonColumn{&t.Tags})
+		if err != nil {
+			return fmt.Errorf("failed to scan transaction: %v", err)
+		}
+		if err := write(e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// sqliteSetTransactionTags — setTransactionTags для SQLite
+func sqliteSetTransactionTags(ctx context.Context, tx *sql.Tx, userID, transactionID int, tags []string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM transaction_tags WHERE transaction_id = ?1`, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to clear transaction tags: %v", err)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	names := sqliteJSON(tags)
+	_, err = tx.ExecContext(ctx, `INSERT INTO tags (user_id, name) SELECT ?1, value FROM json_each(?2) WHERE true
+	                              ON CONFLICT (user_id, name) DO NOTHING`, userID, names)
+	if err != nil {
+		log.Printf("failed to insert tags: %v", err)
+		return fmt.Errorf("failed to insert tags: %v", err)
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO transaction_tags (transaction_id, tag_id)
+	                              SELECT ?1, id FROM tags WHERE user_id = ?2 AND name IN (SELECT value FROM json_each(?3))`,
+		transactionID, userID, names)
+	if err != nil {
+		log.Printf("failed to link tags: %v", err)
+		return fmt.Errorf("failed to link tags: %v", err)
+	}
+	return nil
+}
+
+// sqliteSetTransactionSplits — setTransactionSplits для SQLite
+func sqliteSetTransactionSplits(ctx context.Context, tx *sql.Tx, transactionID int, splits []models.Split) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM transaction_splits WHERE transaction_id = ?1`, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to clear transaction splits: %v", err)
+	}
+	for _, s := range splits {
+		_, err = tx.ExecContext(ctx, `INSERT INTO transaction_splits (transaction_id, category_id, amount, note)
+		                              VALUES (?1, ?2, ?3, NULLIF(?4, ''))`, transactionID, s.CategoryID, int64(s.Amount), s.Note)
+		if err != nil {
+			log.Printf("failed to insert transaction splits: %v", err)
+			return fmt.Errorf("failed to insert transaction splits: %v", err)
+		}
+	}
+	return nil
+}
+
+// GetTags возвращает теги пользователя с числом помеченных ими транзакций
+func (db *SQLiteDB) GetTags(parentCtx context.Context, userID int) ([]models.Tag, error) {
+	query := `SELECT tg.id, tg.user_id, tg.name, COUNT(tt.transaction_id), tg.created_at
+	          FROM tags tg
+	          LEFT JOIN transaction_tags tt ON tt.tag_id = tg.id
+	          WHERE tg.user_id = ?1
+	          GROUP BY tg.id
+	          ORDER BY tg.name`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	rows, err := db.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		log.Printf("failed to retrieve tags: %v", err)
+		return nil, fmt.Errorf("failed to retrieve tags: %v", err)
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var t models.Tag
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Name, &t.TransactionCount, &t.CreatedAt); err != nil {
+			log.Printf("failed to scan tag: %v", err)
+			return nil, fmt.Errorf("failed to scan tag: %v", err)
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+func (db *SQLiteDB) DeleteTag(parentCtx context.Context, userID int, tagID int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	res, err := db.db.ExecContext(ctx, `DELETE FROM tags WHERE id = ?1 AND user_id = ?2`, tagID, userID)
+	if err != nil {
+		log.Printf("failed to delete tag: %v", err)
+		return fmt.Errorf("failed to delete tag: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
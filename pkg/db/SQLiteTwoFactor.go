@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+func (db *SQLiteDB) GetTwoFactor(parentCtx context.Context, userID int) (models.TwoFactor, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var tf models.TwoFactor
+	err := db.db.QueryRowContext(ctx, `SELECT COALESCE(totp_secret, ''), totp_enabled_at IS NOT NULL FROM users WHERE id = ?1`, userID).
+		Scan(&tf.Secret, &tf.Enabled)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.TwoFactor{}, ErrNotFound
+		}
+		log.Printf("failed to get two-factor settings: %v", err)
+		return models.TwoFactor{}, fmt.Errorf("failed to get two-factor settings: %v", err)
+	}
+	return tf, nil
+}
+
+func (db *SQLiteDB) SetTOTPSecret(parentCtx context.Context, userID int, secret string) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	res, err := db.db.ExecContext(ctx, `UPDATE users SET totp_secret = ?2 WHERE id = ?1 AND totp_enabled_at IS NULL`, userID, secret)
+	if err != nil {
+		log.Printf("failed to set totp secret: %v", err)
+		return fmt.Errorf("failed to set totp secret: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrAlreadyExists
+	}
+	return nil
+}
+
+func (db *SQLiteDB) EnableTOTP(parentCtx context.Context, userID int, step int64, codeHashes []string) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE users SET totp_enabled_at = ?3, totp_last_step = ?2
+	                                 WHERE id = ?1 AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL`,
+		userID, step, sqliteTime(time.Now()))
+	if err != nil {
+		log.Printf("failed to enable totp: %v", err)
+		return fmt.Errorf("failed to enable totp: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	if err := sqliteReplaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit totp enabling: %v", err)
+	}
+	return nil
+}
+
+func (db *SQLiteDB) DisableTOTP(parentCtx context.Context, userID int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL WHERE id = ?1`, userID)
+	if err != nil {
+		log.Printf("failed to disable totp: %v", err)
+		return fmt.Errorf("failed to disable totp: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = ?1`, userID); err != nil {
+		log.Printf("failed to delete recovery codes: %v", err)
+		return fmt.Errorf("failed to delete recovery codes: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit totp disabling: %v", err)
+	}
+	return nil
+}
+
+func (db *SQLiteDB) UseTOTPStep(parentCtx context.Context, userID int, step int64) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	res, err := db.db.ExecContext(ctx, `UPDATE users SET totp_last_step = ?2
+	                                    WHERE id = ?1 AND (totp_last_step IS NULL OR totp_last_step < ?2)`, userID, step)
+	if err != nil {
+		log.Printf("failed to use totp code: %v", err)
+		return fmt.Errorf("failed to use totp code: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrTokenReused
+	}
+	return nil
+}
+
+func (db *SQLiteDB) UseRecoveryCode(parentCtx context.Context, userID int, codeHash string) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	res, err := db.db.ExecContext(ctx, `UPDATE recovery_codes SET used_at = ?3
+	                                    WHERE user_id = ?1 AND code_hash = ?2 AND used_at IS NULL`,
+		userID, codeHash, sqliteTime(time.Now()))
+	if err != nil {
+		log.Printf("failed to use recovery code: %v", err)
+		return fmt.Errorf("failed to use recovery code: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (db *SQLiteDB) ReplaceRecoveryCodes(parentCtx context.Context, userID int, codeHashes []string) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := sqliteReplaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit recovery codes: %v", err)
+	}
+	return nil
+}
+
+func sqliteReplaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID int, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = ?1`, userID); err != nil {
+		log.Printf("failed to delete recovery codes: %v", err)
+		return fmt.Errorf("failed to delete recovery codes: %v", err)
+	}
+	for _, hash := range codeHashes {
+		_, err := tx.ExecContext(ctx, `INSERT INTO recovery_codes (user_id, code_hash) VALUES (?1, ?2)`, userID, hash)
+		if err != nil {
+			log.Printf("failed to insert recovery code: %v", err)
+			return fmt.Errorf("failed to insert recovery code: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,380 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+func (db *SQLiteDB) CreateUser(parentCtx context.Context, user *models.User) (models.User, error) {
+	query := `INSERT INTO users (email, password, base_currency, time_zone)
+	          VALUES (?1, ?2, COALESCE(NULLIF(?3, ''), 'USD'), COALESCE(NULLIF(?4, ''), 'UTC'))
+	          RETURNING ` + userColumns
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var newUser models.User
+	err := scanUser(db.db.QueryRowContext(ctx, query, user.Email, user.Password, user.BaseCurrency, user.TimeZone), &newUser)
+	if err != nil {
+		log.Printf("failed to create user: %v", err)
+		return models.User{}, fmt.Errorf("failed to create user: %v", err)
+	}
+	return newUser, nil
+}
+
+func (db *SQLiteDB) GetUserByEmail(parentCtx context.Context, email string) (models.User, error) {
+	query := `SELECT ` + userColumns + `, password FROM users WHERE email = ?1`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var user models.User
+	err := scanUser(db.db.QueryRowContext(ctx, query, email), &user, &user.Password)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.User{}, ErrNotFound
+		}
+		return models.User{}, fmt.Errorf("failed to get user: %v", err)
+	}
+	return user, nil
+}
+
+func (db *SQLiteDB) GetUserByID(parentCtx context.Context, id int) (models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ?1`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var user models.User
+	err := scanUser(db.db.QueryRowContext(ctx, query, id), &user)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.User{}, ErrNotFound
+		}
+		return models.User{}, fmt.Errorf("failed to get user: %v", err)
+	}
+	return user, nil
+}
+
+func (db *SQLiteDB) UpdateUserSettings(parentCtx context.Context, userID int, settings *models.UserSettings) (models.User, error) {
+	set := updateSet{}
+	if settings.BaseCurrency != nil {
+		set.add("base_currency", *settings.BaseCurrency)
+	}
+	if settings.TimeZone != nil {
+		set.add("time_zone", *settings.TimeZone)
+	}
+	if len(set.sets) == 0 {
+		return db.GetUserByID(parentCtx, userID)
+	}
+
+	set.args = append(set.args, userID)
+	query := `UPDATE users SET ` + strings.Join(set.sets, ", ") +
+		fmt.Sprintf(` WHERE id = $%d RETURNING `, len(set.args)) + userColumns
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var user models.User
+	err := scanUser(db.db.QueryRowContext(ctx, rebind(query), set.args...), &user)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.User{}, ErrNotFound
+		}
+		log.Printf("failed to update user settings: %v", err)
+		return models.User{}, fmt.Errorf("failed to update user settings: %v", err)
+	}
+	return user, nil
+}
+
+func (db *SQLiteDB) CreateRefreshToken(parentCtx context.Context, t *models.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (user_id, family_id, token_hash, access_jti, access_expires_at, expires_at)
+	          VALUES (?1, ?2, ?3, ?4, ?5, ?6)`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	_, err := db.db.ExecContext(ctx, query, t.UserID, t.FamilyID, t.TokenHash, t.AccessJTI,
+		sqliteTime(t.AccessExpiresAt), sqliteTime(t.ExpiresAt))
+	if err != nil {
+		log.Printf("failed to insert refresh token: %v", err)
+		return fmt.Errorf("failed to insert refresh token: %v", err)
+	}
+	return nil
+}
+
+// RotateRefreshToken — см. PostgresDB.RotateRefreshToken
+func (db *SQLiteDB) RotateRefreshToken(parentCtx context.Context, tokenHash string, next *models.RefreshToken) (models.User, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var current models.RefreshToken
+	err = tx.QueryRowContext(ctx, `SELECT id, user_id, family_id, expires_at, used_at, revoked_at
+	                               FROM refresh_tokens WHERE token_hash = ?1`, tokenHash).
+		Scan(&current.ID, &current.UserID, &current.FamilyID, &current.ExpiresAt, &current.UsedAt, &current.RevokedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.User{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve refresh token: %v", err)
+		return models.User{}, fmt.Errorf("failed to retrieve refresh token: %v", err)
+	}
+
+	now := time.Now()
+	if current.UsedAt != nil || current.RevokedAt != nil {
+		log.Printf("refresh token reuse detected for user %d, revoking family %s", current.UserID, current.FamilyID)
+		if err := sqliteRevokeFamily(ctx, tx, current.FamilyID, now); err != nil {
+			return models.User{}, err
+		}
+		if err := tx.Commit(); err != nil {
+			return models.User{}, fmt.Errorf("failed to commit token revocation: %v", err)
+		}
+		return models.User{}, ErrTokenReused
+	}
+	if now.After(current.ExpiresAt) {
+		return models.User{}, ErrTokenExpired
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE refresh_tokens SET used_at = ?1 WHERE id = ?2`, sqliteTime(now), current.ID)
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to mark refresh token as used: %v", err)
+	}
+
+	next.UserID = current.UserID
+	next.FamilyID = current.FamilyID
+	_, err = tx.ExecContext(ctx, `INSERT INTO refresh_tokens (user_id, family_id, token_hash, access_jti, access_expires_at, expires_at)
+	                              VALUES (?1, ?2, ?3, ?4, ?5, ?6)`,
+		next.UserID, next.FamilyID, next.TokenHash, next.AccessJTI, sqliteTime(next.AccessExpiresAt), sqliteTime(next.ExpiresAt))
+	if err != nil {
+		log.Printf("failed to insert refresh token: %v", err)
+		return models.User{}, fmt.Errorf("failed to insert refresh token: %v", err)
+	}
+
+	var user models.User
+	err = scanUser(tx.QueryRowContext(ctx, `SELECT `+userColumns+`, password FROM users WHERE id = ?1`, current.UserID),
+		&user, &user.Password)
+	if err != nil {
+		log.Printf("failed to retrieve user: %v", err)
+		return models.User{}, fmt.Errorf("failed to retrieve user: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.User{}, fmt.Errorf("failed to commit token rotation: %v", err)
+	}
+	return user, nil
+}
+
+func (db *SQLiteDB) RevokeSession(parentCtx context.Context, userID int, jti string, expiresAt time.Time) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var familyID string
+	err = tx.QueryRowContext(ctx, `SELECT family_id FROM refresh_tokens WHERE access_jti = ?1 AND user_id = ?2`, jti, userID).
+		Scan(&familyID)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("failed to retrieve session: %v", err)
+		return fmt.Errorf("failed to retrieve session: %v", err)
+	}
+	if familyID != "" {
+		if err := sqliteRevokeFamily(ctx, tx, familyID, now); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO revoked_tokens (jti, expires_at) VALUES (?1, ?2) ON CONFLICT (jti) DO NOTHING`,
+		jti, sqliteTime(expiresAt))
+	if err != nil {
+		log.Printf("failed to revoke access token: %v", err)
+		return fmt.Errorf("failed to revoke access token: %v", err)
+	}
+
+	// Истёкшие токены отклоняются и без списка отзыва
+	if _, err := tx.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE expires_at < ?1`, sqliteTime(now)); err != nil {
+		return fmt.Errorf("failed to clean up revoked tokens: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit session revocation: %v", err)
+	}
+	return nil
+}
+
+func sqliteRevokeFamily(ctx context.Context, tx *sql.Tx, familyID string, now time.Time) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO revoked_tokens (jti, expires_at)
+	                               SELECT access_jti, access_expires_at FROM refresh_tokens
+	                               WHERE family_id = ?1 AND access_expires_at > ?2
+	                               ON CONFLICT (jti) DO NOTHING`, familyID, sqliteTime(now))
+	if err != nil {
+		log.Printf("failed to revoke access tokens: %v", err)
+		return fmt.Errorf("failed to revoke access tokens: %v", err)
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE refresh_tokens SET revoked_at = ?1 WHERE family_id = ?2 AND revoked_at IS NULL`,
+		sqliteTime(now), familyID)
+	if err != nil {
+		log.Printf("failed to revoke refresh tokens: %v", err)
+		return fmt.Errorf("failed to revoke refresh tokens: %v", err)
+	}
+	return nil
+}
+
+func (db *SQLiteDB) IsTokenRevoked(parentCtx context.Context, jti string) (bool, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var revoked bool
+	err := db.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = ?1)`, jti).Scan(&revoked)
+	if err != nil {
+		log.Printf("failed to check token revocation: %v", err)
+		return false, fmt.Errorf("failed to check token revocation: %v", err)
+	}
+	return revoked, nil
+}
+
+func (db *SQLiteDB) CreateUserToken(parentCtx context.Context, t *models.UserToken) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_tokens WHERE user_id = ?1 AND purpose = ?2 AND used_at IS NULL`, t.UserID, t.Purpose)
+	if err != nil {
+		log.Printf("failed to cancel user tokens: %v", err)
+		return fmt.Errorf("failed to cancel user tokens: %v", err)
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at) VALUES (?1, ?2, ?3, ?4)`,
+		t.UserID, t.Purpose, t.TokenHash, sqliteTime(t.ExpiresAt))
+	if err != nil {
+		log.Printf("failed to insert user token: %v", err)
+		return fmt.Errorf("failed to insert user token: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit user token: %v", err)
+	}
+	return nil
+}
+
+// sqliteConsumeUserToken — см. consumeUserToken
+func sqliteConsumeUserToken(ctx context.Context, tx *sql.Tx, purpose, tokenHash string, now time.Time) (int, error) {
+	var t models.UserToken
+	err := tx.QueryRowContext(ctx, `SELECT id, user_id, expires_at, used_at FROM user_tokens
+	                                WHERE token_hash = ?1 AND purpose = ?2`, tokenHash, purpose).
+		Scan(&t.ID, &t.UserID, &t.ExpiresAt, &t.UsedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrNotFound
+		}
+		log.Printf("failed to retrieve user token: %v", err)
+		return 0, fmt.Errorf("failed to retrieve user token: %v", err)
+	}
+	if t.UsedAt != nil {
+		return 0, ErrNotFound
+	}
+	if now.After(t.ExpiresAt) {
+		return 0, ErrTokenExpired
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE user_tokens SET used_at = ?1 WHERE id = ?2`, sqliteTime(now), t.ID); err != nil {
+		return 0, fmt.Errorf("failed to mark user token as used: %v", err)
+	}
+	return t.UserID, nil
+}
+
+func (db *SQLiteDB) VerifyEmail(parentCtx context.Context, tokenHash string) (models.User, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	userID, err := sqliteConsumeUserToken(ctx, tx, models.TokenVerifyEmail, tokenHash, now)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var user models.User
+	err = scanUser(tx.QueryRowContext(ctx, `UPDATE users SET email_verified_at = COALESCE(email_verified_at, ?1)
+	                                        WHERE id = ?2 RETURNING `+userColumns, sqliteTime(now), userID), &user)
+	if err != nil {
+		log.Printf("failed to verify email: %v", err)
+		return models.User{}, fmt.Errorf("failed to verify email: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.User{}, fmt.Errorf("failed to commit email verification: %v", err)
+	}
+	return user, nil
+}
+
+func (db *SQLiteDB) ResetPassword(parentCtx context.Context, tokenHash, passwordHash string) (models.User, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	userID, err := sqliteConsumeUserToken(ctx, tx, models.TokenResetPassword, tokenHash, now)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var user models.User
+	err = scanUser(tx.QueryRowContext(ctx, `UPDATE users SET password = ?1, email_verified_at = COALESCE(email_verified_at, ?2)
+	                                        WHERE id = ?3 RETURNING `+userColumns, passwordHash, sqliteTime(now), userID), &user)
+	if err != nil {
+		log.Printf("failed to reset password: %v", err)
+		return models.User{}, fmt.Errorf("failed to reset password: %v", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO revoked_tokens (jti, expires_at)
+	                              SELECT access_jti, access_expires_at FROM refresh_tokens
+	                              WHERE user_id = ?1 AND revoked_at IS NULL AND access_expires_at > ?2
+	                              ON CONFLICT (jti) DO NOTHING`, userID, sqliteTime(now))
+	if err != nil {
+		log.Printf("failed to revoke access tokens: %v", err)
+		return models.User{}, fmt.Errorf("failed to revoke access tokens: %v", err)
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE refresh_tokens SET revoked_at = ?1 WHERE user_id = ?2 AND revoked_at IS NULL`,
+		sqliteTime(now), userID)
+	if err != nil {
+		log.Printf("failed to revoke refresh tokens: %v", err)
+		return models.User{}, fmt.Errorf("failed to revoke refresh tokens: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.User{}, fmt.Errorf("failed to commit password reset: %v", err)
+	}
+	log.Printf("Password reset for user %d, all sessions revoked", userID)
+	return user, nil
+}
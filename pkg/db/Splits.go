@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+)
+
+// setTransactionSplits заменяет строки разбивки транзакции; пустой splits убирает разбивку
+func setTransactionSplits(ctx context.Context, tx pgx.Tx, transactionID int, splits []models.Split) error {
+	_, err := tx.Exec(ctx, `DELETE FROM transaction_splits WHERE transaction_id = $1`, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to clear transaction splits: %v", err)
+	}
+	if len(splits) == 0 {
+		return nil
+	}
+
+	categories := make([]int, len(splits))
+	amounts := make([]string, len(splits))
+	notes := make([]string, len(splits))
+	for i, s := range splits {
+		categories[i] = s.CategoryID
+		amounts[i] = s.Amount.String()
+		notes[i] = s.Note
+	}
+	_, err = tx.Exec(ctx, `INSERT INTO transaction_splits (transaction_id, category_id, amount, note)
+	                       SELECT $1, category_id, amount, NULLIF(note, '')
+	                       FROM unnest($2::int[], $3::numeric[], $4::text[]) AS s(category_id, amount, note)`,
+		transactionID, categories, amounts, notes)
+	if err != nil {
+		log.Printf("failed to insert transaction splits: %v", err)
+		return fmt.Errorf("failed to insert transaction splits: %v", err)
+	}
+	return nil
+}
+
+// transactionLines — подзапрос для LATERAL: строки разбивки транзакции t или,
+// если разбивки нет, одна строка с её категорией и суммой
+const transactionLines = `(
+	SELECT s.category_id, s.amount FROM transaction_splits s WHERE s.transaction_id = t.id
+	UNION ALL
+	SELECT t.category_id, t.amount
+	WHERE NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id)
+)`
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+)
+
+// setTransactionTags заменяет теги транзакции на tags, создавая недостающие теги пользователя
+func setTransactionTags(ctx context.Context, tx pgx.Tx, userID, transactionID int, tags []string) error {
+	_, err := tx.Exec(ctx, `DELETE FROM transaction_tags WHERE transaction_id = $1`, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to clear transaction tags: %v", err)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO tags (user_id, name) SELECT $1, unnest($2::text[])
+	                       ON CONFLICT (user_id, name) DO NOTHING`, userID, tags)
+	if err != nil {
+		log.Printf("failed to insert tags: %v", err)
+		return fmt.Errorf("failed to insert tags: %v", err)
+	}
+	_, err = tx.Exec(ctx, `INSERT INTO transaction_tags (transaction_id, tag_id)
+	                       SELECT $1, id FROM tags WHERE user_id = $2 AND name = ANY($3::text[])`,
+		transactionID, userID, tags)
+	if err != nil {
+		log.Printf("failed to link tags: %v", err)
+		return fmt.Errorf("failed to link tags: %v", err)
+	}
+	return nil
+}
+
+// GetTags возвращает теги пользователя с числом помеченных ими транзакций
+func (db *PostgresDB) GetTags(parentCtx context.Context, userID int) ([]models.Tag, error) {
+	query := `SELECT tg.id, tg.user_id, tg.name, COUNT(tt.transaction_id), tg.created_at
+	          FROM tags tg
+	          LEFT JOIN transaction_tags tt ON tt.tag_id = tg.id
+	          WHERE tg.user_id = $1
+	          GROUP BY tg.id
+	          ORDER BY tg.name`
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	rows, err := db.pool.Query(ctx, query, userID)
+	if err != nil {
+		log.Printf("failed to retrieve tags: %v", err)
+		return nil, fmt.Errorf("failed to retrieve tags: %v", err)
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var t models.Tag
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Name, &t.TransactionCount, &t.CreatedAt); err != nil {
+			log.Printf("failed to scan tag: %v", err)
+			return nil, fmt.Errorf("failed to scan tag: %v", err)
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// DeleteTag удаляет тег; транзакции остаются, с них снимается только этот тег
+func (db *PostgresDB) DeleteTag(parentCtx context.Context, userID int, tagID int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	result, err := db.pool.Exec(ctx, `DELETE FROM tags WHERE id = $1 AND user_id = $2`, tagID, userID)
+	if err != nil {
+		log.Printf("failed to delete tag: %v", err)
+		return fmt.Errorf("failed to delete tag: %v", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
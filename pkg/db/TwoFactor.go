@@ -0,0 +1,174 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *PostgresDB) GetTwoFactor(parentCtx context.Context, userID int) (models.TwoFactor, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var tf models.TwoFactor
+	err := db.pool.QueryRow(ctx, `SELECT COALESCE(totp_secret, ''), totp_enabled_at IS NOT NULL FROM users WHERE id = $1`, userID).
+		Scan(&tf.Secret, &tf.Enabled)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.TwoFactor{}, ErrNotFound
+		}
+		log.Printf("failed to get two-factor settings: %v", err)
+		return models.TwoFactor{}, fmt.Errorf("failed to get two-factor settings: %v", err)
+	}
+	return tf, nil
+}
+
+// SetTOTPSecret сохраняет секрет новой настройки 2FA, заменяя неподтверждённый.
+// Если 2FA уже включена — ErrAlreadyExists: сначала её нужно отключить.
+func (db *PostgresDB) SetTOTPSecret(parentCtx context.Context, userID int, secret string) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tag, err := db.pool.Exec(ctx, `UPDATE users SET totp_secret = $2 WHERE id = $1 AND totp_enabled_at IS NULL`, userID, secret)
+	if err != nil {
+		log.Printf("failed to set totp secret: %v", err)
+		return fmt.Errorf("failed to set totp secret: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAlreadyExists
+	}
+	return nil
+}
+
+// EnableTOTP включает 2FA после проверки первого кода (step — его шаг) и сохраняет
+// хеши кодов восстановления. Без неподтверждённой настройки — ErrNotFound.
+func (db *PostgresDB) EnableTOTP(parentCtx context.Context, userID int, step int64, codeHashes []string) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE users SET totp_enabled_at = now(), totp_last_step = $2
+	                          WHERE id = $1 AND totp_secret IS NOT NULL AND totp_enabled_at IS NULL`, userID, step)
+	if err != nil {
+		log.Printf("failed to enable totp: %v", err)
+		return fmt.Errorf("failed to enable totp: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit totp enabling: %v", err)
+	}
+	return nil
+}
+
+// DisableTOTP отключает 2FA и удаляет секрет и коды восстановления
+func (db *PostgresDB) DisableTOTP(parentCtx context.Context, userID int) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL WHERE id = $1`, userID)
+	if err != nil {
+		log.Printf("failed to disable totp: %v", err)
+		return fmt.Errorf("failed to disable totp: %v", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		log.Printf("failed to delete recovery codes: %v", err)
+		return fmt.Errorf("failed to delete recovery codes: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit totp disabling: %v", err)
+	}
+	return nil
+}
+
+// UseTOTPStep принимает код шага step, только если он позже последнего принятого;
+// иначе код уже использован — ErrTokenReused
+func (db *PostgresDB) UseTOTPStep(parentCtx context.Context, userID int, step int64) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tag, err := db.pool.Exec(ctx, `UPDATE users SET totp_last_step = $2
+	                               WHERE id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2)`, userID, step)
+	if err != nil {
+		log.Printf("failed to use totp code: %v", err)
+		return fmt.Errorf("failed to use totp code: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTokenReused
+	}
+	return nil
+}
+
+// UseRecoveryCode гасит код восстановления; неизвестный или использованный — ErrNotFound
+func (db *PostgresDB) UseRecoveryCode(parentCtx context.Context, userID int, codeHash string) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tag, err := db.pool.Exec(ctx, `UPDATE recovery_codes SET used_at = now()
+	                               WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`, userID, codeHash)
+	if err != nil {
+		log.Printf("failed to use recovery code: %v", err)
+		return fmt.Errorf("failed to use recovery code: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes заменяет все коды восстановления пользователя новыми
+func (db *PostgresDB) ReplaceRecoveryCodes(parentCtx context.Context, userID int, codeHashes []string) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit recovery codes: %v", err)
+	}
+	return nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx pgx.Tx, userID int, codeHashes []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		log.Printf("failed to delete recovery codes: %v", err)
+		return fmt.Errorf("failed to delete recovery codes: %v", err)
+	}
+	for _, hash := range codeHashes {
+		_, err := tx.Exec(ctx, `INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash)
+		if err != nil {
+			log.Printf("failed to insert recovery code: %v", err)
+			return fmt.Errorf("failed to insert recovery code: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *PostgresDB) UpdateTransaction(parentCtx context.Context, userID int, transactionID int, u *models.TransactionUpdate) (models.Transaction, error) {
+	set := updateSet{}
+	if u.IsIncome != nil {
+		set.add("is_income", *u.IsIncome)
+	}
+	if u.Amount != nil {
+		set.add("amount", *u.Amount)
+	}
+	if u.CategoryID != nil {
+		set.add("category_id", *u.CategoryID)
+	}
+	if u.Note != nil {
+		set.add("note", *u.Note)
+	}
+	if u.OccurredAt != nil {
+		set.args = append(set.args, u.OccurredAt.Time)
+		set.sets = append(set.sets, "occurred_at = "+occurredAt(*u.OccurredAt, "$"+strconv.Itoa(len(set.args)), "user_id"))
+	}
+	if u.Currency != nil {
+		set.add("currency", *u.Currency)
+	}
+	if u.AccountID != nil {
+		var accountID *int
+		if *u.AccountID != 0 {
+			accountID = u.AccountID
+		}
+		set.add("account_id", accountID)
+	}
+	if len(set.sets) == 0 && u.Tags == nil && u.Splits == nil {
+		return db.GetTransactionByID(parentCtx, userID, transactionID)
+	}
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var transferID *int
+	err = tx.QueryRow(ctx, `SELECT transfer_id FROM transactions WHERE id = $1 AND user_id = $2 FOR UPDATE`,
+		transactionID, userID).Scan(&transferID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Transaction{}, ErrNotFound
+		}
+		log.Printf("failed to retrieve transaction: %v", err)
+		return models.Transaction{}, fmt.Errorf("failed to retrieve transaction: %v", err)
+	}
+	if transferID != nil {
+		return models.Transaction{}, ErrTransferTransaction
+	}
+
+	// updated_at меняется и тогда, когда меняются только теги или разбивка
+	set.sets = append(set.sets, "updated_at = CURRENT_TIMESTAMP")
+	if _, err := tx.Exec(ctx, `UPDATE transactions SET `+set.where(transactionID, userID), set.args...); err != nil {
+		log.Printf("failed to update transaction: %v", err)
+		return models.Transaction{}, fmt.Errorf("failed to update transaction: %v", err)
+	}
+
+	if u.Splits != nil {
+		if err := setTransactionSplits(ctx, tx, transactionID, *u.Splits); err != nil {
+			return models.Transaction{}, err
+		}
+	}
+
+	// Разбивка должна покрывать сумму транзакции и после изменения суммы
+	var splitMismatch bool
+	err = tx.QueryRow(ctx, `SELECT COALESCE((SELECT SUM(amount) FROM transaction_splits WHERE transaction_id = $1), amount) <> amount
+	                        FROM transactions WHERE id = $1`, transactionID).Scan(&splitMismatch)
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("failed to check transaction splits: %v", err)
+	}
+	if splitMismatch {
+		return models.Transaction{}, ErrSplitMismatch
+	}
+
+	// Остаток счёта считается в его валюте, поэтому транзакция счёта должна быть в ней же
+	var mismatch bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM transactions t JOIN accounts a ON a.id = t.account_id
+	                                       WHERE t.id = $1 AND a.currency <> t.currency)`, transactionID).Scan(&mismatch)
+	if err != nil {
+		return models.Transaction{}, fmt.Errorf("failed to check account currency: %v", err)
+	}
+	if mismatch {
+		return models.Transaction{}, ErrCurrencyMismatch
+	}
+
+	if u.Tags != nil {
+		if err := setTransactionTags(ctx, tx, userID, transactionID, *u.Tags); err != nil {
+			return models.Transaction{}, err
+		}
+	}
+
+	var transaction models.Transaction
+	err = scanTransaction(tx.QueryRow(ctx, `SELECT `+transactionColumns+` FROM transactions WHERE id = $1`, transactionID),
+		&transaction)
+	if err != nil {
+		log.Printf("failed to retrieve transaction: %v", err)
+		return models.Transaction{}, fmt.Errorf("failed to retrieve transaction: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Transaction{}, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	log.Printf("Transaction with id %d updated successfully for user %d\n", transactionID, userID)
+	return transaction, nil
+}
@@ -4,21 +4,32 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	models "github.com/ViktorOHJ/expense-tracker/pkg"
 	"github.com/jackc/pgx/v5"
 )
 
+// userColumns — колонки пользователя без пароля, порядок совпадает с scanUser
+const userColumns = `id, email, base_currency, time_zone, email_verified_at IS NOT NULL, totp_enabled_at IS NOT NULL, created_at`
+
+// scanUser читает userColumns и следующие за ними колонки extra, например пароль
+func scanUser(row pgx.Row, u *models.User, extra ...interface{}) error {
+	dest := []interface{}{&u.ID, &u.Email, &u.BaseCurrency, &u.TimeZone, &u.EmailVerified, &u.TwoFactorEnabled, &u.CreatedAt}
+	return row.Scan(append(dest, extra...)...)
+}
+
 func (db *PostgresDB) CreateUser(parentCtx context.Context, user *models.User) (models.User, error) {
-	query := `INSERT INTO users (email, password) VALUES ($1, $2) RETURNING id, email, created_at`
+	query := `INSERT INTO users (email, password, base_currency, time_zone)
+	          VALUES ($1, $2, COALESCE(NULLIF($3, ''), 'USD'), COALESCE(NULLIF($4, ''), 'UTC'))
+	          RETURNING ` + userColumns
 
 	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
 	defer cancel()
 
 	var newUser models.User
-	err := db.pool.QueryRow(ctx, query, user.Email, user.Password).
-		Scan(&newUser.ID, &newUser.Email, &newUser.CreatedAt)
+	err := scanUser(db.pool.QueryRow(ctx, query, user.Email, user.Password, user.BaseCurrency, user.TimeZone), &newUser)
 
 	if err != nil {
 		log.Printf("failed to create user: %v", err)
@@ -29,14 +40,13 @@ func (db *PostgresDB) CreateUser(parentCtx context.Context, user *models.User) (
 }
 
 func (db *PostgresDB) GetUserByEmail(parentCtx context.Context, email string) (models.User, error) {
-	query := `SELECT id, email, password, created_at FROM users WHERE email = $1`
+	query := `SELECT ` + userColumns + `, password FROM users WHERE email = $1`
 
 	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
 	defer cancel()
 
 	var user models.User
-	err := db.pool.QueryRow(ctx, query, email).
-		Scan(&user.ID, &user.Email, &user.Password, &user.CreatedAt)
+	err := scanUser(db.pool.QueryRow(ctx, query, email), &user, &user.Password)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -49,14 +59,13 @@ func (db *PostgresDB) GetUserByEmail(parentCtx context.Context, email string) (m
 }
 
 func (db *PostgresDB) GetUserByID(parentCtx context.Context, id int) (models.User, error) {
-	query := `SELECT id, email, created_at FROM users WHERE id = $1`
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
 
 	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
 	defer cancel()
 
 	var user models.User
-	err := db.pool.QueryRow(ctx, query, id).
-		Scan(&user.ID, &user.Email, &user.CreatedAt)
+	err := scanUser(db.pool.QueryRow(ctx, query, id), &user)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -67,3 +76,34 @@ func (db *PostgresDB) GetUserByID(parentCtx context.Context, id int) (models.Use
 
 	return user, nil
 }
+
+func (db *PostgresDB) UpdateUserSettings(parentCtx context.Context, userID int, settings *models.UserSettings) (models.User, error) {
+	set := updateSet{}
+	if settings.BaseCurrency != nil {
+		set.add("base_currency", *settings.BaseCurrency)
+	}
+	if settings.TimeZone != nil {
+		set.add("time_zone", *settings.TimeZone)
+	}
+	if len(set.sets) == 0 {
+		return db.GetUserByID(parentCtx, userID)
+	}
+
+	set.args = append(set.args, userID)
+	query := `UPDATE users SET ` + strings.Join(set.sets, ", ") +
+		fmt.Sprintf(` WHERE id = $%d RETURNING `, len(set.args)) + userColumns
+
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	var user models.User
+	err := scanUser(db.pool.QueryRow(ctx, query, set.args...), &user)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.User{}, ErrNotFound
+		}
+		log.Printf("failed to update user settings: %v", err)
+		return models.User{}, fmt.Errorf("failed to update user settings: %v", err)
+	}
+	return user, nil
+}
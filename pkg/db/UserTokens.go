@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+)
+
+// CreateUserToken сохраняет токен из письма; неиспользованные токены того же назначения
+// отменяются, поэтому действует только ссылка из последнего письма
+func (db *PostgresDB) CreateUserToken(parentCtx context.Context, t *models.UserToken) error {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DELETE FROM user_tokens WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`, t.UserID, t.Purpose)
+	if err != nil {
+		log.Printf("failed to cancel user tokens: %v", err)
+		return fmt.Errorf("failed to cancel user tokens: %v", err)
+	}
+	_, err = tx.Exec(ctx, `INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at) VALUES ($1, $2, $3, $4)`,
+		t.UserID, t.Purpose, t.TokenHash, t.ExpiresAt)
+	if err != nil {
+		log.Printf("failed to insert user token: %v", err)
+		return fmt.Errorf("failed to insert user token: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit user token: %v", err)
+	}
+	return nil
+}
+
+// consumeUserToken помечает токен использованным и возвращает id владельца.
+// Неизвестный или уже использованный токен — ErrNotFound, просроченный — ErrTokenExpired.
+func consumeUserToken(ctx context.Context, tx pgx.Tx, purpose, tokenHash string) (int, error) {
+	var t models.UserToken
+	err := tx.QueryRow(ctx, `SELECT id, user_id, expires_at, used_at FROM user_tokens
+	                         WHERE token_hash = $1 AND purpose = $2 FOR UPDATE`, tokenHash, purpose).
+		Scan(&t.ID, &t.UserID, &t.ExpiresAt, &t.UsedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, ErrNotFound
+		}
+		log.Printf("failed to retrieve user token: %v", err)
+		return 0, fmt.Errorf("failed to retrieve user token: %v", err)
+	}
+	if t.UsedAt != nil {
+		return 0, ErrNotFound
+	}
+	if time.Now().After(t.ExpiresAt) {
+		return 0, ErrTokenExpired
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE user_tokens SET used_at = now() WHERE id = $1`, t.ID); err != nil {
+		return 0, fmt.Errorf("failed to mark user token as used: %v", err)
+	}
+	return t.UserID, nil
+}
+
+// VerifyEmail по токену из письма отмечает адрес владельца подтверждённым
+func (db *PostgresDB) VerifyEmail(parentCtx context.Context, tokenHash string) (models.User, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	userID, err := consumeUserToken(ctx, tx, models.TokenVerifyEmail, tokenHash)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var user models.User
+	err = scanUser(tx.QueryRow(ctx, `UPDATE users SET email_verified_at = COALESCE(email_verified_at, now())
+	                                 WHERE id = $1 RETURNING `+userColumns, userID), &user)
+	if err != nil {
+		log.Printf("failed to verify email: %v", err)
+		return models.User{}, fmt.Errorf("failed to verify email: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.User{}, fmt.Errorf("failed to commit email verification: %v", err)
+	}
+	return user, nil
+}
+
+// ResetPassword по токену из письма меняет пароль владельца на passwordHash и отзывает
+// все его сессии. Письмо дошло до владельца адреса, поэтому адрес заодно подтверждается.
+func (db *PostgresDB) ResetPassword(parentCtx context.Context, tokenHash, passwordHash string) (models.User, error) {
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	userID, err := consumeUserToken(ctx, tx, models.TokenResetPassword, tokenHash)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var user models.User
+	err = scanUser(tx.QueryRow(ctx, `UPDATE users SET password = $1, email_verified_at = COALESCE(email_verified_at, now())
+	                                 WHERE id = $2 RETURNING `+userColumns, passwordHash, userID), &user)
+	if err != nil {
+		log.Printf("failed to reset password: %v", err)
+		return models.User{}, fmt.Errorf("failed to reset password: %v", err)
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO revoked_tokens (jti, expires_at)
+	                       SELECT access_jti, access_expires_at FROM refresh_tokens
+	                       WHERE user_id = $1 AND revoked_at IS NULL AND access_expires_at > now()
+	                       ON CONFLICT (jti) DO NOTHING`, userID)
+	if err != nil {
+		log.Printf("failed to revoke access tokens: %v", err)
+		return models.User{}, fmt.Errorf("failed to revoke access tokens: %v", err)
+	}
+	_, err = tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+	if err != nil {
+		log.Printf("failed to revoke refresh tokens: %v", err)
+		return models.User{}, fmt.Errorf("failed to revoke refresh tokens: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.User{}, fmt.Errorf("failed to commit password reset: %v", err)
+	}
+	log.Printf("Password reset for user %d, all sessions revoked", userID)
+	return user, nil
+}
@@ -2,10 +2,15 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -13,13 +18,73 @@ type DB interface {
 	AddCategory(context.Context, int, *models.Category) (models.Category, error)
 	AddTransaction(context.Context, int, *models.Transaction) (models.Transaction, error)
 	CheckCategory(context.Context, int, int) (bool, error) // userID, categoryID
-	GetTransactions(context.Context, int, *bool, *int, *time.Time, *time.Time, int, int) ([]*models.Transaction, error)
+	GetTransactions(context.Context, int, models.TransactionFilter, int, int) ([]*models.Transaction, error)
 	GetSummary(context.Context, int, time.Time, time.Time) (models.Summary, error)
-	DeleteTransaction(context.Context, int, int) error                        // userID, transactionID
-	GetTransactionByID(context.Context, int, int) (models.Transaction, error) // userID, transactionID
+	UpdateTransaction(context.Context, int, int, *models.TransactionUpdate) (models.Transaction, error) // userID, transactionID
+	DeleteTransaction(context.Context, int, int) ([]string, error)                                      // userID, transactionID; ключи файлов вложений
+	GetTransactionByID(context.Context, int, int) (models.Transaction, error)                           // userID, transactionID
 	CreateUser(context.Context, *models.User) (models.User, error)
 	GetUserByEmail(context.Context, string) (models.User, error)
 	GetUserByID(context.Context, int) (models.User, error)
+	GetCategories(context.Context, int, bool) ([]models.Category, error)                       // userID, includeArchived
+	GetCategoryByID(context.Context, int, int) (models.Category, error)                        // userID, categoryID
+	UpdateCategory(context.Context, int, int, *models.CategoryUpdate) (models.Category, error) // userID, categoryID
+	DeleteCategory(context.Context, int, int, *int) error                                      // userID, categoryID, reassignTo
+	UpdateUserSettings(context.Context, int, *models.UserSettings) (models.User, error)
+	SaveExchangeRates(context.Context, []models.ExchangeRate) (int, error)
+	AddBudget(context.Context, int, *models.Budget) (models.Budget, error)
+	GetBudgetByID(context.Context, int, int) (models.Budget, error)                      // userID, budgetID
+	UpdateBudget(context.Context, int, int, *models.BudgetUpdate) (models.Budget, error) // userID, budgetID
+	DeleteBudget(context.Context, int, int) error                                        // userID, budgetID
+	GetBudgetStatuses(context.Context, int, time.Time) ([]models.BudgetStatus, error)
+	AddRecurringRule(context.Context, int, *models.RecurringRule) (models.RecurringRule, error)
+	GetRecurringRules(context.Context, int) ([]models.RecurringRule, error)
+	UpdateRecurringRule(context.Context, int, int, *models.RecurringRuleUpdate) (models.RecurringRule, error) // userID, ruleID
+	DeleteRecurringRule(context.Context, int, int) error                                                      // userID, ruleID
+	MaterializeRecurring(context.Context, time.Time) (int, error)
+	ImportTransactions(context.Context, int, []models.ImportRow, models.ImportOptions) (models.ImportResult, error)
+	ExportTransactions(context.Context, int, models.TransactionFilter, func(models.ExportedTransaction) error) error
+	CreateRefreshToken(context.Context, *models.RefreshToken) error
+	RotateRefreshToken(context.Context, string, *models.RefreshToken) (models.User, error) // tokenHash, next
+	RevokeSession(context.Context, int, string, time.Time) error                           // userID, jti, expiresAt
+	CreateUserToken(context.Context, *models.UserToken) error
+	VerifyEmail(context.Context, string) (models.User, error)           // tokenHash
+	ResetPassword(context.Context, string, string) (models.User, error) // tokenHash, passwordHash
+	GetTwoFactor(context.Context, int) (models.TwoFactor, error)
+	SetTOTPSecret(context.Context, int, string) error       // userID, secret
+	EnableTOTP(context.Context, int, int64, []string) error // userID, step, recovery code hashes
+	DisableTOTP(context.Context, int) error
+	UseTOTPStep(context.Context, int, int64) error             // userID, step
+	UseRecoveryCode(context.Context, int, string) error        // userID, codeHash
+	ReplaceRecoveryCodes(context.Context, int, []string) error // userID, codeHashes
+	RecordLoginAttempt(context.Context, *models.LoginAttempt) error
+	GetLoginFailures(context.Context, string, string, time.Time) (models.LoginFailures, error) // email, ip, since
+	GetMailRequests(context.Context, string, string, time.Time) (models.LoginFailures, error)  // email, ip, since
+	GetLoginAttempts(context.Context, int, int, int) ([]models.LoginAttempt, error)            // userID, limit, offset
+	IsTokenRevoked(context.Context, string) (bool, error)
+	AddAPIKey(context.Context, int, *models.APIKey) (models.APIKey, error)
+	GetAPIKeys(context.Context, int) ([]models.APIKey, error)
+	DeleteAPIKey(context.Context, int, int) error             // userID, keyID
+	UseAPIKey(context.Context, string) (models.APIKey, error) // keyHash
+	GetTags(context.Context, int) ([]models.Tag, error)
+	DeleteTag(context.Context, int, int) error // userID, tagID
+	GetCategoryReport(context.Context, int, models.TransactionFilter) (models.CategoryReport, error)
+	GetTimeSeries(context.Context, int, models.TransactionFilter, string) (models.TimeSeries, error) // userID, filter, interval
+	AddAccount(context.Context, int, *models.Account) (models.Account, error)
+	GetAccounts(context.Context, int) ([]models.Account, error)
+	GetAccountByID(context.Context, int, int) (models.Account, error)                       // userID, accountID
+	UpdateAccount(context.Context, int, int, *models.AccountUpdate) (models.Account, error) // userID, accountID
+	DeleteAccount(context.Context, int, int) error                                          // userID, accountID
+	AddTransfer(context.Context, int, *models.Transfer) (models.Transfer, error)
+	AddAttachment(context.Context, int, *models.Attachment) (models.Attachment, error)
+	GetAttachments(context.Context, int, int) ([]models.Attachment, error)   // userID, transactionID
+	GetAttachment(context.Context, int, int, int) (models.Attachment, error) // userID, transactionID, attachmentID
+	DeleteAttachment(context.Context, int, int, int) (string, error)         // userID, transactionID, attachmentID; ключ файла
+	AddCategoryRule(context.Context, int, *models.CategoryRule) (models.CategoryRule, error)
+	GetCategoryRules(context.Context, int) ([]models.CategoryRule, error)
+	UpdateCategoryRule(context.Context, int, int, *models.CategoryRule) (models.CategoryRule, error) // userID, ruleID
+	DeleteCategoryRule(context.Context, int, int) error                                              // userID, ruleID
+	ApplyRuleMatches(context.Context, int, []models.RuleMatch) (int, error)
 }
 
 type PostgresDB struct {
@@ -32,48 +97,106 @@ func NewPostgresDB(pool *pgxpool.Pool) *PostgresDB {
 
 var ErrNotFound = fmt.Errorf("transaction not found")
 
-func InitDB(parentCtx context.Context, dbURL string) (*pgxpool.Pool, error) {
+var (
+	ErrAlreadyExists = fmt.Errorf("record already exists")
+	ErrCategoryInUse = fmt.Errorf("category is in use")
+	ErrTokenExpired  = fmt.Errorf("token expired")
+	ErrTokenReused   = fmt.Errorf("refresh token reuse detected")
+	ErrCategoryCycle = fmt.Errorf("category cannot be nested under itself or its descendant")
+	ErrAccountInUse  = fmt.Errorf("account has transactions")
+
+	// ErrTransferTransaction — транзакцию перевода нельзя менять по отдельности,
+	// иначе расход и доход перестанут совпадать
+	ErrTransferTransaction = fmt.Errorf("transfer transactions cannot be edited, delete the transfer instead")
+	ErrCurrencyMismatch    = fmt.Errorf("currency must match the account currency")
+	ErrSplitMismatch       = fmt.Errorf("split amounts must add up to the transaction amount")
+)
+
+// isUniqueViolation проверяет, что ошибка вызвана нарушением UNIQUE-ограничения
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505"
+	}
+	return isSQLiteUniqueViolation(err)
+}
+
+// isForeignKeyViolation проверяет, что ошибка вызвана нарушением внешнего ключа,
+// например удалением записи, на которую ссылаются транзакции
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23503"
+	}
+	return isSQLiteForeignKeyViolation(err)
+}
+
+// transactionColumns — общий список колонок транзакции для SELECT и RETURNING,
+// порядок совпадает с scanTransaction
+const transactionColumns = `id, is_income, amount, COALESCE(category_id, 0), user_id, COALESCE(note, ''), occurred_at, created_at, updated_at, currency,
+	recurring_rule_id, account_id, transfer_id, ` + transactionTags + `, ` + transactionSplits
+
+// transactionTags — отсортированный массив имён тегов транзакции; таблица transactions
+// в запросе должна быть без псевдонима
+const transactionTags = `ARRAY(SELECT tg.name FROM transaction_tags tt JOIN tags tg ON tg.id = tt.tag_id
+	                       WHERE tt.transaction_id = transactions.id ORDER BY tg.name)`
+
+// transactionSplits — строки разбивки транзакции в виде JSON-массива
+const transactionSplits = `COALESCE((SELECT json_agg(json_build_object('category_id', s.category_id, 'amount', s.amount, 'note', s.note)
+	                                   ORDER BY s.id)
+	                        FROM transaction_splits s WHERE s.transaction_id = transactions.id), '[]')`
+
+func scanTransaction(row pgx.Row, t *models.Transaction) error {
+	return row.Scan(&t.ID, &t.IsIncome, &t.Amount, &t.CategoryID, &t.UserID, &t.Note, &t.OccurredAt, &t.CreatedAt, &t.UpdatedAt, &t.Currency, &t.RecurringRuleID,
+		&t.AccountID, &t.TransferID, &t.Tags, &t.Splits)
+}
+
+// categoryTree возвращает подзапрос с id категории param и всех её потомков
+func categoryTree(param string) string {
+	return `(WITH RECURSIVE tree AS (
+	             SELECT id FROM categories WHERE id = ` + param + `
+	             UNION ALL
+	             SELECT c.id FROM categories c JOIN tree ON c.parent_id = tree.id
+	         ) SELECT id FROM tree)`
+}
+
+// localDay возвращает начало календарного дня date (SQL-выражение с датой) в часовом
+// поясе пользователя userID
+func localDay(date, userID string) string {
+	return `((` + date + `)::date::timestamp AT TIME ZONE user_time_zone(` + userID + `))`
+}
+
+// occurredAt возвращает SQL-выражение для параметра param с датой операции: дата без
+// времени означает полночь в часовом поясе пользователя userID
+func occurredAt(d models.DateTime, param, userID string) string {
+	if d.DateOnly {
+		return `(` + param + `::timestamp AT TIME ZONE user_time_zone(` + userID + `))`
+	}
+	return param + `::timestamptz`
+}
+
+// updateSet накапливает пары "column = $N" для частичных UPDATE
+type updateSet struct {
+	sets []string
+	args []interface{}
+}
+
+func (u *updateSet) add(column string, value interface{}) {
+	u.args = append(u.args, value)
+	u.sets = append(u.sets, column+" = $"+strconv.Itoa(len(u.args)))
+}
+
+// where добавляет аргументы id и user_id и возвращает готовое условие
+func (u *updateSet) where(id, userID int) string {
+	u.args = append(u.args, id, userID)
+	return strings.Join(u.sets, ", ") + fmt.Sprintf(` WHERE id = $%d AND user_id = $%d`, len(u.args)-1, len(u.args))
+}
+
+// Connect открывает пул соединений и проверяет доступность БД
+func Connect(parentCtx context.Context, dbURL string) (*pgxpool.Pool, error) {
 	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
 	defer cancel()
 
-	schema := `
-CREATE TABLE IF NOT EXISTS users (
-    id SERIAL PRIMARY KEY,
-    email VARCHAR(255) NOT NULL UNIQUE,
-    password VARCHAR(255) NOT NULL,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE IF NOT EXISTS categories (
-    id SERIAL PRIMARY KEY,
-    name VARCHAR(100) NOT NULL,
-    description TEXT,
-    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    UNIQUE(name, user_id) -- Уникальность имени категории в рамках пользователя
-);
-
-CREATE TABLE IF NOT EXISTS transactions (
-    id SERIAL PRIMARY KEY,
-    is_income BOOLEAN NOT NULL,
-    amount NUMERIC(10,2) NOT NULL CHECK (amount > 0),
-    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE RESTRICT,
-    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    note TEXT,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE INDEX IF NOT EXISTS idx_transactions_user_id ON transactions(user_id);
-CREATE INDEX IF NOT EXISTS idx_transactions_created_at ON transactions(created_at);
-CREATE INDEX IF NOT EXISTS idx_transactions_category_id ON transactions(category_id);
-CREATE INDEX IF NOT EXISTS idx_transactions_is_income ON transactions(is_income);
-CREATE INDEX IF NOT EXISTS idx_categories_user_id ON categories(user_id);
-CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
-
-CREATE INDEX IF NOT EXISTS idx_transactions_user_created ON transactions(user_id, created_at DESC);
-CREATE INDEX IF NOT EXISTS idx_transactions_user_income ON transactions(user_id, is_income);
-`
-
 	pool, err := pgxpool.New(ctx, dbURL)
 	if err != nil {
 		return nil, err
@@ -85,12 +208,81 @@ CREATE INDEX IF NOT EXISTS idx_transactions_user_income ON transactions(user_id,
 		pool.Close()
 		return nil, err
 	}
+	return pool, nil
+}
 
-	_, err = pool.Exec(ctx, schema)
+// InitDB подключается к БД и применяет недостающие миграции
+func InitDB(ctx context.Context, dbURL string) (*pgxpool.Pool, error) {
+	pool, err := Connect(ctx, dbURL)
 	if err != nil {
-		pool.Close()
 		return nil, err
 	}
 
+	migrator, err := NewMigrator(pool)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
 	return pool, nil
 }
+
+// sqliteScheme — префикс DB_URL, выбирающий SQLite: sqlite://data/expenses.db
+const sqliteScheme = "sqlite://"
+
+// Open подключается к БД по dbURL: sqlite://путь открывает файл SQLite, остальные
+// адреса — PostgreSQL. При migrate применяются недостающие миграции. Возвращает
+// хранилище и функцию закрытия соединений.
+func Open(ctx context.Context, dbURL string, migrate bool) (DB, func(), error) {
+	if path, ok := strings.CutPrefix(dbURL, sqliteScheme); ok {
+		open := OpenSQLite
+		if migrate {
+			open = InitSQLite
+		}
+		conn, err := open(ctx, path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return NewSQLiteDB(conn), func() { conn.Close() }, nil
+	}
+
+	connect := Connect
+	if migrate {
+		connect = InitDB
+	}
+	pool, err := connect(ctx, dbURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewPostgresDB(pool), pool.Close, nil
+}
+
+// OpenMigrator подключается к БД по dbURL и возвращает мигратор её схемы
+func OpenMigrator(ctx context.Context, dbURL string) (SchemaMigrator, func(), error) {
+	if path, ok := strings.CutPrefix(dbURL, sqliteScheme); ok {
+		conn, err := OpenSQLite(ctx, path)
+		if err != nil {
+			return nil, nil, err
+		}
+		migrator, err := NewSQLiteMigrator(conn)
+		if err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+		return migrator, func() { conn.Close() }, nil
+	}
+
+	pool, err := Connect(ctx, dbURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	migrator, err := NewMigrator(pool)
+	if err != nil {
+		pool.Close()
+		return nil, nil, err
+	}
+	return migrator, pool.Close, nil
+}
@@ -0,0 +1,425 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey — ключ pg_advisory_lock, под которым выполняются миграции,
+// чтобы несколько экземпляров приложения не применяли их одновременно
+const migrationLockKey = 7_348_112_001
+
+// Migration — пара SQL-скриптов migrations/NNNN_name.up.sql и NNNN_name.down.sql
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus — миграция и время её применения (nil — не применена)
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+var migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// LoadMigrations читает встроенные миграции, упорядоченные по версии
+func LoadMigrations() ([]Migration, error) {
+	return loadMigrations(migrationFiles, "migrations")
+}
+
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, e := range entries {
+		m := migrationName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", e.Name())
+		}
+		version, _ := strconv.Atoi(m[1])
+		content, err := fs.ReadFile(fsys, dir+"/"+e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		} else if migration.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has different names: %s and %s", version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down scripts", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator применяет и откатывает миграции. Каждая миграция выполняется в отдельной
+// транзакции вместе с записью в schema_migrations, поэтому частично применённых
+// миграций не бывает.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// withLock выполняет fn на отдельном соединении, удерживая advisory lock миграций
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %v", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %v", err)
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+	    version INTEGER PRIMARY KEY,
+	    name VARCHAR(255) NOT NULL,
+	    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %v", err)
+	}
+	return fn(conn)
+}
+
+func appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int]time.Time, error) {
+	rows, err := conn.Query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %v", err)
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+// Up применяет все ещё не применённые миграции по возрастанию версии
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`,
+					migration.Version, migration.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s failed: %v", migration.Version, migration.Name, err)
+			}
+			log.Printf("Applied migration %d_%s", migration.Version, migration.Name)
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down откатывает n последних применённых миграций
+func (m *Migrator) Down(ctx context.Context, n int) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && count < n; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, migration.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("rollback of migration %d_%s failed: %v", migration.Version, migration.Name, err)
+			}
+			log.Printf("Rolled back migration %d_%s", migration.Version, migration.Name)
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Status возвращает все известные миграции с отметкой о применении
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Migration: migration}
+			if at, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &at
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Force отмечает миграции до version включительно применёнными, а более поздние —
+// неприменёнными, не выполняя их SQL. Нужна, чтобы восстановить учёт после ручного
+// вмешательства в схему; version = 0 очищает schema_migrations.
+func (m *Migrator) Force(ctx context.Context, version int) error {
+	known := version == 0
+	for _, migration := range m.migrations {
+		known = known || migration.Version == version
+	}
+	if !known {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version > $1`, version); err != nil {
+				return err
+			}
+			for _, migration := range m.migrations {
+				if migration.Version > version {
+					break
+				}
+				_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)
+				                        ON CONFLICT (version) DO NOTHING`, migration.Version, migration.Name)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// SchemaMigrator — команды миграций, общие для PostgreSQL и SQLite
+type SchemaMigrator interface {
+	Up(ctx context.Context) (int, error)
+	Down(ctx context.Context, n int) (int, error)
+	Status(ctx context.Context) ([]MigrationStatus, error)
+	Force(ctx context.Context, version int) error
+}
+
+//go:embed sqlite_migrations/*.sql
+var sqliteMigrationFiles embed.FS
+
+// SQLiteMigrator — Migrator для SQLite со своим набором миграций sqlite_migrations.
+// Транзакции SQLite берут блокировку записи сразу, поэтому список применённых миграций
+// перечитывается внутри транзакции каждой из них вместо advisory lock.
+type SQLiteMigrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewSQLiteMigrator(db *sql.DB) (*SQLiteMigrator, error) {
+	migrations, err := loadMigrations(sqliteMigrationFiles, "sqlite_migrations")
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+	    version INTEGER PRIMARY KEY,
+	    name TEXT NOT NULL,
+	    applied_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000+00:00', 'now'))
+	)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %v", err)
+	}
+	return &SQLiteMigrator{db: db, migrations: migrations}, nil
+}
+
+// inTx выполняет fn в транзакции со списком уже применённых миграций
+func (m *SQLiteMigrator) inTx(ctx context.Context, fn func(tx *sql.Tx, applied map[int]time.Time) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return fmt.Errorf("failed to read schema_migrations: %v", err)
+	}
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[version] = at
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if err := fn(tx, applied); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *SQLiteMigrator) Up(ctx context.Context) (int, error) {
+	count := 0
+	for _, migration := range m.migrations {
+		applied := false
+		err := m.inTx(ctx, func(tx *sql.Tx, versions map[int]time.Time) error {
+			if _, ok := versions[migration.Version]; ok {
+				return nil
+			}
+			if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES (?1, ?2)`,
+				migration.Version, migration.Name)
+			applied = err == nil
+			return err
+		})
+		if err != nil {
+			return count, fmt.Errorf("migration %d_%s failed: %v", migration.Version, migration.Name, err)
+		}
+		if applied {
+			log.Printf("Applied migration %d_%s", migration.Version, migration.Name)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *SQLiteMigrator) Down(ctx context.Context, n int) (int, error) {
+	count := 0
+	for i := len(m.migrations) - 1; i >= 0 && count < n; i-- {
+		migration := m.migrations[i]
+		rolledBack := false
+		err := m.inTx(ctx, func(tx *sql.Tx, versions map[int]time.Time) error {
+			if _, ok := versions[migration.Version]; !ok {
+				return nil
+			}
+			if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?1`, migration.Version)
+			rolledBack = err == nil
+			return err
+		})
+		if err != nil {
+			return count, fmt.Errorf("rollback of migration %d_%s failed: %v", migration.Version, migration.Name, err)
+		}
+		if rolledBack {
+			log.Printf("Rolled back migration %d_%s", migration.Version, migration.Name)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *SQLiteMigrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.inTx(ctx, func(tx *sql.Tx, applied map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Migration: migration}
+			if at, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &at
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+func (m *SQLiteMigrator) Force(ctx context.Context, version int) error {
+	known := version == 0
+	for _, migration := range m.migrations {
+		known = known || migration.Version == version
+	}
+	if !known {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.inTx(ctx, func(tx *sql.Tx, _ map[int]time.Time) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version > ?1`, version); err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES (?1, ?2)
+			                               ON CONFLICT (version) DO NOTHING`, migration.Version, migration.Name)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,34 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS base_currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+-- 1 единица base_currency стоит rate единиц quote_currency на дату rate_date
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    rate_date DATE NOT NULL,
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate NUMERIC(18,8) NOT NULL CHECK (rate > 0),
+    PRIMARY KEY (base_currency, quote_currency, rate_date)
+);
+
+-- Курс from -> to на дату: последний известный курс не позже on_date,
+-- прямой, обратный или кросс-курс через общую базовую валюту
+CREATE OR REPLACE FUNCTION exchange_rate(from_currency CHAR(3), to_currency CHAR(3), on_date DATE)
+RETURNS NUMERIC AS $$
+    SELECT CASE WHEN from_currency = to_currency THEN 1 ELSE (
+        SELECT rate FROM (
+            (SELECT 1 AS priority, er.rate FROM exchange_rates er
+              WHERE er.base_currency = from_currency AND er.quote_currency = to_currency AND er.rate_date <= on_date
+              ORDER BY er.rate_date DESC LIMIT 1)
+            UNION ALL
+            (SELECT 2, 1 / er.rate FROM exchange_rates er
+              WHERE er.base_currency = to_currency AND er.quote_currency = from_currency AND er.rate_date <= on_date
+              ORDER BY er.rate_date DESC LIMIT 1)
+            UNION ALL
+            (SELECT 3, b.rate / a.rate FROM exchange_rates a
+               JOIN exchange_rates b ON b.base_currency = a.base_currency AND b.rate_date = a.rate_date
+              WHERE a.quote_currency = from_currency AND b.quote_currency = to_currency AND a.rate_date <= on_date
+              ORDER BY a.rate_date DESC LIMIT 1)
+        ) candidates
+        ORDER BY priority LIMIT 1
+    ) END
+$$ LANGUAGE SQL STABLE;
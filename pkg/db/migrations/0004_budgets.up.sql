@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS budgets (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES categories(id) ON DELETE CASCADE, -- NULL: все расходы
+    amount NUMERIC(10,2) NOT NULL CHECK (amount > 0),
+    period VARCHAR(10) NOT NULL CHECK (period IN ('monthly', 'custom')),
+    start_date DATE NOT NULL,
+    end_date DATE,
+    rollover BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_budgets_user_id ON budgets(user_id);
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS recurring_rules (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    is_income BOOLEAN NOT NULL,
+    amount NUMERIC(10,2) NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE RESTRICT,
+    note TEXT,
+    frequency VARCHAR(10) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly', 'yearly')),
+    interval INTEGER NOT NULL DEFAULT 1 CHECK (interval > 0),
+    day_of_month INTEGER CHECK (day_of_month BETWEEN 1 AND 31),
+    start_date DATE NOT NULL,
+    end_date DATE,
+    max_occurrences INTEGER CHECK (max_occurrences > 0),
+    occurrences INTEGER NOT NULL DEFAULT 0,
+    next_date DATE, -- NULL: правило исчерпано
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+-- Транзакции, созданные по правилу; уникальный индекс не даёт провести одно повторение дважды
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS recurring_rule_id INTEGER REFERENCES recurring_rules(id) ON DELETE SET NULL;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS occurrence_date DATE;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_recurring_occurrence ON transactions(recurring_rule_id, occurrence_date);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_rules_user_id ON recurring_rules(user_id);
+CREATE INDEX IF NOT EXISTS idx_recurring_rules_next_date ON recurring_rules(next_date);
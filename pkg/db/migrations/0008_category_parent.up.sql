@@ -0,0 +1,3 @@
+-- Родительская категория; при удалении родителя подкатегории поднимаются на уровень выше
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES categories(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
@@ -0,0 +1,9 @@
+DELETE FROM transactions WHERE transfer_id IS NOT NULL;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_category_required;
+ALTER TABLE transactions ALTER COLUMN category_id SET NOT NULL;
+DROP INDEX IF EXISTS idx_transactions_transfer_id;
+DROP INDEX IF EXISTS idx_transactions_account_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS transfer_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS account_id;
+DROP TABLE IF EXISTS transfers;
+DROP TABLE IF EXISTS accounts;
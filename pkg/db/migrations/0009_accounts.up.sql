@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS accounts (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('cash', 'bank', 'card', 'savings', 'other')),
+    currency CHAR(3) NOT NULL,
+    opening_balance NUMERIC(12,2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+-- Перевод состоит из двух связанных транзакций: расхода со счёта from и дохода на счёт to
+CREATE TABLE IF NOT EXISTS transfers (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    to_account_id INTEGER NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    amount NUMERIC(10,2) NOT NULL CHECK (amount > 0),
+    to_amount NUMERIC(10,2) NOT NULL CHECK (to_amount > 0),
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (from_account_id <> to_account_id)
+);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS account_id INTEGER REFERENCES accounts(id) ON DELETE RESTRICT;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS transfer_id INTEGER REFERENCES transfers(id) ON DELETE CASCADE;
+
+-- У транзакций перевода нет категории
+ALTER TABLE transactions ALTER COLUMN category_id DROP NOT NULL;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_category_required;
+ALTER TABLE transactions ADD CONSTRAINT transactions_category_required CHECK (category_id IS NOT NULL OR transfer_id IS NOT NULL);
+
+CREATE INDEX IF NOT EXISTS idx_accounts_user_id ON accounts(user_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_account_id ON transactions(account_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_transfer_id ON transactions(transfer_id);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS category_rules (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    note_contains TEXT,
+    note_regex TEXT,
+    min_amount NUMERIC(10,2),
+    max_amount NUMERIC(10,2),
+    is_income BOOLEAN,
+    account_id INTEGER REFERENCES accounts(id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES categories(id) ON DELETE CASCADE,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_rules_user_id ON category_rules(user_id, priority, id);
@@ -0,0 +1,6 @@
+-- Без occurred_at датой операции снова становится created_at
+UPDATE transactions SET created_at = occurred_at;
+
+DROP INDEX IF EXISTS idx_transactions_user_occurred;
+ALTER TABLE transactions DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE transactions DROP COLUMN IF EXISTS occurred_at;
@@ -0,0 +1,16 @@
+-- created_at до сих пор служил датой операции: импорт, регулярные транзакции и переводы
+-- записывали в него дату операции, поэтому существующие значения переносятся в occurred_at
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS occurred_at TIMESTAMP;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP;
+
+UPDATE transactions
+SET occurred_at = COALESCE(created_at, CURRENT_TIMESTAMP),
+    updated_at = COALESCE(created_at, CURRENT_TIMESTAMP)
+WHERE occurred_at IS NULL;
+
+ALTER TABLE transactions ALTER COLUMN occurred_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE transactions ALTER COLUMN occurred_at SET NOT NULL;
+ALTER TABLE transactions ALTER COLUMN updated_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE transactions ALTER COLUMN updated_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_user_occurred ON transactions(user_id, occurred_at DESC);
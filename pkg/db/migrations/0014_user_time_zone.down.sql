@@ -0,0 +1,8 @@
+DROP FUNCTION IF EXISTS user_time_zone(INTEGER);
+
+ALTER TABLE transfers ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE transactions ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'UTC';
+ALTER TABLE transactions ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE transactions ALTER COLUMN occurred_at TYPE TIMESTAMP USING occurred_at AT TIME ZONE 'UTC';
+
+ALTER TABLE users DROP COLUMN IF EXISTS time_zone;
@@ -0,0 +1,14 @@
+-- Часовой пояс пользователя (имя IANA): в нём считаются календарные дни фильтров,
+-- сводок, отчётов и бюджетов
+ALTER TABLE users ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT 'UTC';
+
+-- Моменты операций хранятся с часовым поясом; прежние значения записывались в UTC
+ALTER TABLE transactions ALTER COLUMN occurred_at TYPE TIMESTAMPTZ USING occurred_at AT TIME ZONE 'UTC';
+ALTER TABLE transactions ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+ALTER TABLE transactions ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC';
+ALTER TABLE transfers ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+
+CREATE OR REPLACE FUNCTION user_time_zone(p_user_id INTEGER)
+RETURNS TEXT AS $$
+    SELECT COALESCE((SELECT time_zone FROM users WHERE id = p_user_id), 'UTC')
+$$ LANGUAGE SQL STABLE;
@@ -0,0 +1,16 @@
+-- Двухфакторная аутентификация по TOTP (RFC 6238). Секрет записывается при настройке,
+-- а действует после подтверждения первым кодом (totp_enabled_at). totp_last_step —
+-- последний принятый 30-секундный шаг, чтобы один код нельзя было использовать дважды.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(64);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT;
+
+-- Одноразовые коды восстановления на случай потери устройства; хранится только SHA-256
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, code_hash)
+);
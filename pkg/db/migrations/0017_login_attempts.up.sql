@@ -0,0 +1,16 @@
+-- Журнал попыток входа: по нему считаются неудачи для задержек и блокировки,
+-- а пользователь видит попытки входа в свой аккаунт. Адрес хранится и для
+-- неизвестных email, чтобы блокировка не выдавала, зарегистрирован ли адрес.
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    success BOOLEAN NOT NULL,
+    reason VARCHAR(30) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_email ON login_attempts(email, created_at);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_ip ON login_attempts(ip, created_at);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_user_id ON login_attempts(user_id, created_at);
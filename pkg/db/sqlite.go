@@ -0,0 +1,234 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+// SQLiteDB — реализация DB на встроенной SQLite для установок без отдельного сервера БД.
+// Схема та же, что в PostgreSQL, но суммы хранятся целыми центами, моменты времени —
+// строками UTC в формате sqliteTimeFormat, даты — строками YYYY-MM-DD, а массивы — JSON.
+type SQLiteDB struct {
+	db *sql.DB
+}
+
+func NewSQLiteDB(db *sql.DB) *SQLiteDB {
+	return &SQLiteDB{db: db}
+}
+
+// sqliteDriverName — драйвер go-sqlite3 с функциями часовых поясов (local_day, local_date),
+// которых в SQLite нет
+const sqliteDriverName = "sqlite3_expense_tracker"
+
+// sqliteTimeFormat — моменты времени всегда в UTC и фиксированной длины, поэтому строки
+// сравниваются и сортируются так же, как сами моменты
+const sqliteTimeFormat = "2006-01-02 15:04:05.000000-07:00"
+
+var locations sync.Map // имя часового пояса -> *time.Location
+
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// localDayFunc — SQL-функция local_day(date, zone): начало календарного дня date
+// в часовом поясе zone, аналог date::timestamp AT TIME ZONE zone
+func localDayFunc(date, zone string) (string, error) {
+	loc, err := loadLocation(zone)
+	if err != nil {
+		return "", err
+	}
+	d, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return "", err
+	}
+	return sqliteTime(time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)), nil
+}
+
+// localDateFunc — SQL-функция local_date(ts, zone): календарная дата момента ts
+// в часовом поясе zone, аналог (ts AT TIME ZONE zone)::date
+func localDateFunc(ts, zone string) (string, error) {
+	loc, err := loadLocation(zone)
+	if err != nil {
+		return "", err
+	}
+	t, err := time.Parse(sqliteTimeFormat, ts)
+	if err != nil {
+		return "", fmt.Errorf("invalid timestamp %q", ts)
+	}
+	return t.In(loc).Format("2006-01-02"), nil
+}
+
+// OpenSQLite открывает файл базы path, создавая его при необходимости
+func OpenSQLite(parentCtx context.Context, path string) (*sql.DB, error) {
+	if errSQLiteUnavailable != nil {
+		return nil, errSQLiteUnavailable
+	}
+	ctx, cancel := context.WithTimeout(parentCtx, 5*time.Second)
+	defer cancel()
+
+	if path != ":memory:" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+	}
+	// Транзакции сразу берут блокировку записи (_txlock=immediate), поэтому
+	// чтение с последующей записью не нуждается в SELECT ... FOR UPDATE
+	conn, err := sql.Open(sqliteDriverName,
+		path+"?_foreign_keys=on&_busy_timeout=5000&_txlock=immediate&_journal_mode=WAL")
+	if err != nil {
+		return nil, err
+	}
+	if path == ":memory:" {
+		// У каждого соединения была бы своя база в памяти
+		conn.SetMaxOpenConns(1)
+	}
+
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// InitSQLite открывает файл базы и применяет недостающие миграции
+func InitSQLite(ctx context.Context, path string) (*sql.DB, error) {
+	conn, err := OpenSQLite(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := NewSQLiteMigrator(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// sqliteTime приводит момент к формату хранения sqliteTimeFormat
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeFormat)
+}
+
+// sqliteOptionalTime — необязательный момент для параметра запроса
+func sqliteOptionalTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return sqliteTime(*t)
+}
+
+// sqliteDate — необязательная дата для параметра запроса
+func sqliteDate(d *models.Date) interface{} {
+	if d == nil {
+		return nil
+	}
+	return d.String()
+}
+
+// sqliteMoney — необязательная сумма для параметра запроса, в центах
+func sqliteMoney(m *models.Money) interface{} {
+	if m == nil {
+		return nil
+	}
+	return int64(*m)
+}
+
+// sqliteJSON сериализует массив для параметра запроса, который разбирается json_each
+func sqliteJSON(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// jsonColumn разбирает JSON-колонку результата в dest
+type jsonColumn struct {
+	dest interface{}
+}
+
+func (c jsonColumn) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), c.dest)
+	case []byte:
+		return json.Unmarshal(v, c.dest)
+	default:
+		return fmt.Errorf("cannot scan %T into JSON", src)
+	}
+}
+
+// sqliteRow — общее у *sql.Row и *sql.Rows
+type sqliteRow interface {
+	Scan(dest ...interface{}) error
+}
+
+var numberedParam = regexp.MustCompile(`\$(\d+)`)
+
+// rebind заменяет параметры $N на ?N: в SQLite $N — именованный параметр, номер
+// которого определяется порядком появления в запросе
+func rebind(query string) string {
+	return numberedParam.ReplaceAllString(query, "?${1}")
+}
+
+// sqliteUserZone — подзапрос с часовым поясом пользователя userID
+func sqliteUserZone(userID string) string {
+	return `(SELECT time_zone FROM users WHERE id = ` + userID + `)`
+}
+
+// sqliteLocalDay — аналог localDay: начало календарного дня date (YYYY-MM-DD)
+// в часовом поясе пользователя userID
+func sqliteLocalDay(date, userID string) string {
+	return `local_day(` + date + `, ` + sqliteUserZone(userID) + `)`
+}
+
+// sqliteOccurredAt — аналог occurredAt: выражение и значение параметра param
+// для даты операции
+func sqliteOccurredAt(d models.DateTime, param, userID string) (string, interface{}) {
+	if d.DateOnly {
+		return sqliteLocalDay(param, userID), d.Format("2006-01-02")
+	}
+	return param, sqliteTime(d.Time)
+}
+
+// sqliteExchangeRate — аналог SQL-функции exchange_rate из PostgreSQL: курс from -> to
+// на дату date, прямой, обратный или кросс-курс через общую базовую валюту
+func sqliteExchangeRate(from, to, date string) string {
+	return `(CASE WHEN ` + from + ` = ` + to + ` THEN 1.0 ELSE COALESCE(
+	    (SELECT er.rate FROM exchange_rates er
+	      WHERE er.base_currency = ` + from + ` AND er.quote_currency = ` + to + ` AND er.rate_date <= ` + date + `
+	      ORDER BY er.rate_date DESC LIMIT 1),
+	    (SELECT 1.0 / er.rate FROM exchange_rates er
+	      WHERE er.base_currency = ` + to + ` AND er.quote_currency = ` + from + ` AND er.rate_date <= ` + date + `
+	      ORDER BY er.rate_date DESC LIMIT 1),
+	    (SELECT b.rate / a.rate FROM exchange_rates a
+	       JOIN exchange_rates b ON b.base_currency = a.base_currency AND b.rate_date = a.rate_date
+	      WHERE a.quote_currency = ` + from + ` AND b.quote_currency = ` + to + ` AND a.rate_date <= ` + date + `
+	      ORDER BY a.rate_date DESC LIMIT 1)) END)`
+}
+
+// sqliteConverted — сумма amount транзакции t в базовой валюте пользователя u
+// по курсу на местную дату операции, в центах; NULL, если курса нет
+func sqliteConverted(amount string) string {
+	return `CAST(ROUND(` + amount + ` * ` +
+		sqliteExchangeRate("t.currency", "u.base_currency", "local_date(t.occurred_at, u.time_zone)") + `) AS INTEGER)`
+}
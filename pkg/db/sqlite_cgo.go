@@ -0,0 +1,38 @@
+//go:build cgo
+
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// errSQLiteUnavailable — go-sqlite3 требует cgo; в сборке с cgo SQLite доступна
+var errSQLiteUnavailable error
+
+func init() {
+	sql.Register(sqliteDriverName, &sqlite3.SQLiteDriver{ConnectHook: registerSQLiteFunctions})
+}
+
+func registerSQLiteFunctions(conn *sqlite3.SQLiteConn) error {
+	if err := conn.RegisterFunc("local_day", localDayFunc, true); err != nil {
+		return err
+	}
+	return conn.RegisterFunc("local_date", localDateFunc, true)
+}
+
+func isSQLiteUniqueViolation(err error) bool {
+	var liteErr sqlite3.Error
+	return errors.As(err, &liteErr) && liteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
+
+// isSQLiteForeignKeyViolation: для ON DELETE RESTRICT SQLite возвращает
+// SQLITE_CONSTRAINT_TRIGGER, а не SQLITE_CONSTRAINT_FOREIGNKEY, поэтому нарушение
+// узнаётся по тексту ошибки
+func isSQLiteForeignKeyViolation(err error) bool {
+	var liteErr sqlite3.Error
+	return errors.As(err, &liteErr) && liteErr.Code == sqlite3.ErrConstraint && strings.Contains(liteErr.Error(), "FOREIGN KEY")
+}
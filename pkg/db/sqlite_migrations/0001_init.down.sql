@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS category_rules;
+DROP TABLE IF EXISTS attachments;
+DROP VIEW IF EXISTS transaction_lines;
+DROP TABLE IF EXISTS transaction_splits;
+DROP TABLE IF EXISTS transaction_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS budgets;
+DROP TABLE IF EXISTS exchange_rates;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS recurring_rules;
+DROP TABLE IF EXISTS transfers;
+DROP TABLE IF EXISTS accounts;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,12 @@
+//go:build !cgo
+
+package db
+
+import "errors"
+
+// errSQLiteUnavailable — go-sqlite3 требует cgo, без него доступен только PostgreSQL
+var errSQLiteUnavailable = errors.New("sqlite support requires a build with CGO_ENABLED=1")
+
+func isSQLiteUniqueViolation(err error) bool { return false }
+
+func isSQLiteForeignKeyViolation(err error) bool { return false }
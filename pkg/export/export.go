@@ -0,0 +1,89 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+// Writer построчно записывает транзакции в выбранном формате.
+// Close дописывает окончание файла и должен вызываться после последней строки.
+type Writer interface {
+	Write(models.ExportedTransaction) error
+	Close() error
+}
+
+// ContentTypes — поддерживаемые форматы выгрузки и их MIME-типы
+var ContentTypes = map[string]string{
+	"csv":   "text/csv; charset=utf-8",
+	"jsonl": "application/x-ndjson",
+	"xlsx":  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// header — колонки табличных форматов (CSV и XLSX), порядок совпадает с fields
+var header = []string{"id", "date", "type", "amount", "currency", "category", "note", "tags"}
+
+func fields(t models.ExportedTransaction) []string {
+	txType := "expense"
+	if t.IsIncome {
+		txType = "income"
+	}
+	return []string{
+		strconv.Itoa(t.ID),
+		t.OccurredAt.Format("2006-01-02 15:04:05"),
+		txType,
+		t.Amount.String(),
+		t.Currency,
+		t.CategoryName,
+		t.Note,
+		strings.Join(t.Tags, ";"),
+	}
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: cw}, nil
+	case "jsonl":
+		return &jsonlWriter{enc: json.NewEncoder(w)}, nil
+	case "xlsx":
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(t models.ExportedTransaction) error {
+	return c.w.Write(fields(t))
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonlWriter пишет по одному JSON-объекту на строку (JSON Lines)
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func (j *jsonlWriter) Write(t models.ExportedTransaction) error {
+	return j.enc.Encode(t)
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,200 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+// ParseCSV читает выписку с заголовком в первой строке и превращает каждую строку
+// в транзакцию по сопоставлению mapping. Ошибки в отдельных строках не прерывают
+// разбор, а записываются в ImportRow.Error; ошибка возвращается только если файл
+// нельзя прочитать или в заголовке нет нужных колонок.
+func ParseCSV(r io.Reader, m models.ImportMapping) ([]models.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if m.Delimiter != "" {
+		d, size := utf8.DecodeRuneInString(m.Delimiter)
+		if size != len(m.Delimiter) {
+			return nil, errors.New("delimiter must be a single character")
+		}
+		reader.Comma = d
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %v", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	column := func(field, name string, required bool) (int, error) {
+		if name == "" {
+			if required {
+				return -1, fmt.Errorf("mapping for '%s' is required", field)
+			}
+			return -1, nil
+		}
+		i, ok := columns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return -1, fmt.Errorf("column '%s' not found in file", name)
+		}
+		return i, nil
+	}
+
+	dateCol, err := column("date", m.Date, true)
+	if err != nil {
+		return nil, err
+	}
+	amountCol, err := column("amount", m.Amount, true)
+	if err != nil {
+		return nil, err
+	}
+	categoryCol, err := column("category", m.Category, false)
+	if err != nil {
+		return nil, err
+	}
+	noteCol, err := column("note", m.Note, false)
+	if err != nil {
+		return nil, err
+	}
+	currencyCol, err := column("currency", m.Currency, false)
+	if err != nil {
+		return nil, err
+	}
+
+	dateFormat := m.DateFormat
+	if dateFormat == "" {
+		dateFormat = "2006-01-02"
+	}
+	switch m.Sign {
+	case "", models.SignNegativeExpense, models.SignNegativeIncome:
+	default:
+		return nil, fmt.Errorf("sign must be '%s' or '%s'", models.SignNegativeExpense, models.SignNegativeIncome)
+	}
+
+	var rows []models.ImportRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		row := models.ImportRow{Line: line, Status: models.ImportRowNew}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("invalid csv: %v", err)
+			}
+			row.Status, row.Error = models.ImportRowInvalid, parseErr.Err.Error()
+			rows = append(rows, row)
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue // пустая строка
+		}
+
+		cell := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		if err := parseRow(&row, m, dateFormat, cell(dateCol), cell(amountCol), cell(noteCol), cell(currencyCol)); err != nil {
+			row.Status, row.Error = models.ImportRowInvalid, err.Error()
+		}
+		// Пустая категория допустима: её может назначить правило при импорте
+		row.CategoryName = cell(categoryCol)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseRow(row *models.ImportRow, m models.ImportMapping, dateFormat, date, amount, note, currency string) error {
+	t := &row.Transaction
+
+	d, err := time.Parse(dateFormat, date)
+	if err != nil {
+		return fmt.Errorf("invalid date '%s'", date)
+	}
+	t.OccurredAt = models.DateTime{Time: models.NewDate(d).Time}
+
+	value, err := parseAmount(amount, m.DecimalComma)
+	if err != nil {
+		return err
+	}
+	if value == 0 {
+		return errors.New("amount must not be zero")
+	}
+	negative := value < 0
+	if negative {
+		value = -value
+	}
+	if value > models.MaxAmount {
+		return fmt.Errorf("amount must not exceed %s", models.MaxAmount)
+	}
+	t.Amount = value
+	if m.Sign == models.SignNegativeIncome {
+		t.IsIncome = negative
+	} else {
+		t.IsIncome = !negative
+	}
+
+	t.Note = note
+	t.Currency = strings.ToUpper(currency)
+	if t.Currency != "" && !models.IsValidCurrency(t.Currency) {
+		return fmt.Errorf("invalid currency '%s'", currency)
+	}
+	return nil
+}
+
+// parseAmount убирает разделители разрядов и разбирает сумму со знаком
+func parseAmount(s string, decimalComma bool) (models.Money, error) {
+	s = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "", "'", "").Replace(s)
+	// В выписках доход иногда помечен плюсом, ParseMoney его не принимает
+	s = strings.TrimPrefix(s, "+")
+	groupSep := ","
+	if decimalComma {
+		groupSep = "."
+	}
+	s, err := stripGroupSeparator(s, groupSep)
+	if err != nil {
+		return 0, err
+	}
+	if decimalComma {
+		s = strings.ReplaceAll(s, ",", ".")
+	}
+	value, err := models.ParseMoney(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount '%s': %v", s, err)
+	}
+	return value, nil
+}
+
+// stripGroupSeparator убирает разделитель разрядов sep. Разделителем он считается,
+// только если он стоит после цифры и за ним ровно три цифры: "12,50" без
+// decimal_comma — скорее всего десятичная запятая, и молча превращать её в 1250 нельзя.
+func stripGroupSeparator(s, sep string) (string, error) {
+	const digits = "0123456789"
+	groups := strings.Split(s, sep)
+	for i := 1; i < len(groups); i++ {
+		prev, g := groups[i-1], groups[i]
+		if prev == "" || !strings.ContainsAny(prev[len(prev)-1:], digits) ||
+			len(g)-len(strings.TrimLeft(g, digits)) != 3 {
+			return "", fmt.Errorf("invalid amount '%s': '%s' must separate groups of three digits", s, sep)
+		}
+	}
+	return strings.Join(groups, ""), nil
+}
@@ -0,0 +1,55 @@
+package importer_test
+
+import (
+	"strings"
+	"testing"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/ViktorOHJ/expense-tracker/pkg/importer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCSV_Amounts(t *testing.T) {
+	tests := []struct {
+		amount       string
+		decimalComma bool
+		want         models.Money
+		invalid      bool
+	}{
+		{amount: "12.50", want: 1250},
+		{amount: "1,234.56", want: 123456},
+		{amount: "1,234,567", want: 123456700},
+		{amount: "-1,234", want: 123400},
+		{amount: "1 234.56", want: 123456},
+		{amount: "+12.50", want: 1250},
+		{amount: "1/100", invalid: true},
+		{amount: "1e3", invalid: true},
+		// Запятая не перед тремя цифрами — это не разделитель разрядов
+		{amount: "12,50", invalid: true},
+		{amount: "1,2345", invalid: true},
+		{amount: "1,23,456", invalid: true},
+		{amount: ",500", invalid: true},
+		{amount: "-,500", invalid: true},
+		{amount: "12,50", decimalComma: true, want: 1250},
+		{amount: "1.234,56", decimalComma: true, want: 123456},
+		{amount: "1 234,56", decimalComma: true, want: 123456},
+		{amount: "12.50", decimalComma: true, invalid: true},
+		{amount: "1.2345,00", decimalComma: true, invalid: true},
+	}
+
+	for _, tt := range tests {
+		mapping := models.ImportMapping{Date: "date", Amount: "amount", DecimalComma: tt.decimalComma, Delimiter: ";"}
+		rows, err := importer.ParseCSV(strings.NewReader("date;amount\n2024-05-17;"+tt.amount+"\n"), mapping)
+		assert.NoError(t, err)
+		if !assert.Len(t, rows, 1) {
+			continue
+		}
+		if tt.invalid {
+			assert.Equal(t, models.ImportRowInvalid, rows[0].Status, tt.amount)
+			assert.Contains(t, rows[0].Error, "invalid amount", tt.amount)
+			continue
+		}
+		assert.Empty(t, rows[0].Error, tt.amount)
+		assert.Equal(t, tt.want, rows[0].Transaction.Amount, tt.amount)
+	}
+}
@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+)
+
+// Message — текстовое письмо одному получателю
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма пользователям: ссылки подтверждения почты и сброса пароля
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+var ErrInvalidHeader = errors.New("mail header must not contain line breaks")
+
+// validate не пропускает переводы строк в заголовки, иначе через адрес или тему
+// можно дописать в письмо свои заголовки
+func (m Message) validate() error {
+	if strings.ContainsAny(m.To, "\r\n") || strings.ContainsAny(m.Subject, "\r\n") {
+		return ErrInvalidHeader
+	}
+	return nil
+}
+
+// LogMailer пишет письма в лог вместо отправки; для разработки без почтового сервера
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	log.Printf("Mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
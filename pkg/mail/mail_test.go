@@ -0,0 +1,121 @@
+package mail_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ViktorOHJ/expense-tracker/pkg/mail"
+	"github.com/stretchr/testify/assert"
+)
+
+// smtpSink — минимальный SMTP-сервер наподобие MailHog: принимает одно письмо
+// и передаёт в канал конверт и содержимое
+type smtpSink struct {
+	addr     string
+	received chan sinkMessage
+}
+
+type sinkMessage struct {
+	from, to string
+	data     string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	sink := &smtpSink{addr: ln.Addr().String(), received: make(chan sinkMessage, 1)}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+
+		var msg sinkMessage
+		tp.PrintfLine("220 sink ready")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+			case "EHLO", "HELO":
+				tp.PrintfLine("250 sink")
+			case "MAIL":
+				msg.from = strings.TrimPrefix(line, "MAIL FROM:")
+				tp.PrintfLine("250 OK")
+			case "RCPT":
+				msg.to = strings.TrimPrefix(line, "RCPT TO:")
+				tp.PrintfLine("250 OK")
+			case "DATA":
+				tp.PrintfLine("354 go ahead")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				msg.data = string(data)
+				tp.PrintfLine("250 queued")
+				sink.received <- msg
+			case "QUIT":
+				tp.PrintfLine("221 bye")
+				return
+			default:
+				tp.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+	return sink
+}
+
+func TestSMTPMailer(t *testing.T) {
+	sink := newSMTPSink(t)
+	mailer, err := mail.NewSMTPMailer(sink.addr, "noreply@example.com", "", "")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = mailer.Send(ctx, mail.Message{
+		To:      "user@example.com",
+		Subject: "Подтверждение почты",
+		Body:    "Ваш код: abc\nСсылка действует сутки.",
+	})
+	assert.NoError(t, err)
+
+	select {
+	case msg := <-sink.received:
+		assert.Equal(t, "<noreply@example.com>", msg.from)
+		assert.Equal(t, "<user@example.com>", msg.to)
+		assert.Contains(t, msg.data, "To: user@example.com\n")
+		assert.Contains(t, msg.data, "Subject: =?utf-8?q?")
+
+		parts := strings.SplitN(msg.data, "\n\n", 2)
+		if assert.Len(t, parts, 2) {
+			body, err := io.ReadAll(quotedprintable.NewReader(bufio.NewReader(strings.NewReader(parts[1]))))
+			assert.NoError(t, err)
+			assert.Equal(t, "Ваш код: abc\nСсылка действует сутки.", strings.TrimRight(string(body), "\r\n"))
+		}
+	case <-ctx.Done():
+		t.Fatal("message was not delivered")
+	}
+}
+
+func TestMailerRejectsHeaderInjection(t *testing.T) {
+	msg := mail.Message{To: "user@example.com\r\nBcc: victim@example.com", Subject: "hi"}
+	assert.ErrorIs(t, mail.LogMailer{}.Send(context.Background(), msg), mail.ErrInvalidHeader)
+
+	mailer, err := mail.NewSMTPMailer("127.0.0.1:1", "noreply@example.com", "", "")
+	assert.NoError(t, err)
+	assert.ErrorIs(t, mailer.Send(context.Background(), msg), mail.ErrInvalidHeader)
+}
@@ -25,26 +25,26 @@ func (_m *DB) EXPECT() *DB_Expecter {
 	return &DB_Expecter{mock: &_m.Mock}
 }
 
-// AddCategory provides a mock function with given fields: _a0, _a1, _a2
-func (_m *DB) AddCategory(_a0 context.Context, _a1 int, _a2 *models.Category) (models.Category, error) {
+// AddAPIKey provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) AddAPIKey(_a0 context.Context, _a1 int, _a2 *models.APIKey) (models.APIKey, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for AddCategory")
+		panic("no return value specified for AddAPIKey")
 	}
 
-	var r0 models.Category
+	var r0 models.APIKey
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Category) (models.Category, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.APIKey) (models.APIKey, error)); ok {
 		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Category) models.Category); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.APIKey) models.APIKey); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Get(0).(models.Category)
+		r0 = ret.Get(0).(models.APIKey)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, *models.Category) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.APIKey) error); ok {
 		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
@@ -53,56 +53,56 @@ func (_m *DB) AddCategory(_a0 context.Context, _a1 int, _a2 *models.Category) (m
 	return r0, r1
 }
 
-// DB_AddCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddCategory'
-type DB_AddCategory_Call struct {
+// DB_AddAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddAPIKey'
+type DB_AddAPIKey_Call struct {
 	*mock.Call
 }
 
-// AddCategory is a helper method to define mock.On call
+// AddAPIKey is a helper method to define mock.On call
 //   - _a0 context.Context
 //   - _a1 int
-//   - _a2 *models.Category
-func (_e *DB_Expecter) AddCategory(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_AddCategory_Call {
-	return &DB_AddCategory_Call{Call: _e.mock.On("AddCategory", _a0, _a1, _a2)}
+//   - _a2 *models.APIKey
+func (_e *DB_Expecter) AddAPIKey(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_AddAPIKey_Call {
+	return &DB_AddAPIKey_Call{Call: _e.mock.On("AddAPIKey", _a0, _a1, _a2)}
 }
 
-func (_c *DB_AddCategory_Call) Run(run func(_a0 context.Context, _a1 int, _a2 *models.Category)) *DB_AddCategory_Call {
+func (_c *DB_AddAPIKey_Call) Run(run func(_a0 context.Context, _a1 int, _a2 *models.APIKey)) *DB_AddAPIKey_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(*models.Category))
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.APIKey))
 	})
 	return _c
 }
 
-func (_c *DB_AddCategory_Call) Return(_a0 models.Category, _a1 error) *DB_AddCategory_Call {
+func (_c *DB_AddAPIKey_Call) Return(_a0 models.APIKey, _a1 error) *DB_AddAPIKey_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *DB_AddCategory_Call) RunAndReturn(run func(context.Context, int, *models.Category) (models.Category, error)) *DB_AddCategory_Call {
+func (_c *DB_AddAPIKey_Call) RunAndReturn(run func(context.Context, int, *models.APIKey) (models.APIKey, error)) *DB_AddAPIKey_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// AddTransaction provides a mock function with given fields: _a0, _a1, _a2
-func (_m *DB) AddTransaction(_a0 context.Context, _a1 int, _a2 *models.Transaction) (models.Transaction, error) {
+// AddAccount provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) AddAccount(_a0 context.Context, _a1 int, _a2 *models.Account) (models.Account, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for AddTransaction")
+		panic("no return value specified for AddAccount")
 	}
 
-	var r0 models.Transaction
+	var r0 models.Account
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Transaction) (models.Transaction, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Account) (models.Account, error)); ok {
 		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Transaction) models.Transaction); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Account) models.Account); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Get(0).(models.Transaction)
+		r0 = ret.Get(0).(models.Account)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, *models.Transaction) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.Account) error); ok {
 		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
@@ -111,56 +111,56 @@ func (_m *DB) AddTransaction(_a0 context.Context, _a1 int, _a2 *models.Transacti
 	return r0, r1
 }
 
-// DB_AddTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddTransaction'
-type DB_AddTransaction_Call struct {
+// DB_AddAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddAccount'
+type DB_AddAccount_Call struct {
 	*mock.Call
 }
 
-// AddTransaction is a helper method to define mock.On call
+// AddAccount is a helper method to define mock.On call
 //   - _a0 context.Context
 //   - _a1 int
-//   - _a2 *models.Transaction
-func (_e *DB_Expecter) AddTransaction(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_AddTransaction_Call {
-	return &DB_AddTransaction_Call{Call: _e.mock.On("AddTransaction", _a0, _a1, _a2)}
+//   - _a2 *models.Account
+func (_e *DB_Expecter) AddAccount(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_AddAccount_Call {
+	return &DB_AddAccount_Call{Call: _e.mock.On("AddAccount", _a0, _a1, _a2)}
 }
 
-func (_c *DB_AddTransaction_Call) Run(run func(_a0 context.Context, _a1 int, _a2 *models.Transaction)) *DB_AddTransaction_Call {
+func (_c *DB_AddAccount_Call) Run(run func(_a0 context.Context, _a1 int, _a2 *models.Account)) *DB_AddAccount_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(*models.Transaction))
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.Account))
 	})
 	return _c
 }
 
-func (_c *DB_AddTransaction_Call) Return(_a0 models.Transaction, _a1 error) *DB_AddTransaction_Call {
+func (_c *DB_AddAccount_Call) Return(_a0 models.Account, _a1 error) *DB_AddAccount_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *DB_AddTransaction_Call) RunAndReturn(run func(context.Context, int, *models.Transaction) (models.Transaction, error)) *DB_AddTransaction_Call {
+func (_c *DB_AddAccount_Call) RunAndReturn(run func(context.Context, int, *models.Account) (models.Account, error)) *DB_AddAccount_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CheckCategory provides a mock function with given fields: _a0, _a1, _a2
-func (_m *DB) CheckCategory(_a0 context.Context, _a1 int, _a2 int) (bool, error) {
+// AddAttachment provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) AddAttachment(_a0 context.Context, _a1 int, _a2 *models.Attachment) (models.Attachment, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CheckCategory")
+		panic("no return value specified for AddAttachment")
 	}
 
-	var r0 bool
+	var r0 models.Attachment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) (bool, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Attachment) (models.Attachment, error)); ok {
 		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) bool); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Attachment) models.Attachment); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Get(0).(models.Attachment)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.Attachment) error); ok {
 		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
@@ -169,57 +169,57 @@ func (_m *DB) CheckCategory(_a0 context.Context, _a1 int, _a2 int) (bool, error)
 	return r0, r1
 }
 
-// DB_CheckCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckCategory'
-type DB_CheckCategory_Call struct {
+// DB_AddAttachment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddAttachment'
+type DB_AddAttachment_Call struct {
 	*mock.Call
 }
 
-// CheckCategory is a helper method to define mock.On call
+// AddAttachment is a helper method to define mock.On call
 //   - _a0 context.Context
 //   - _a1 int
-//   - _a2 int
-func (_e *DB_Expecter) CheckCategory(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_CheckCategory_Call {
-	return &DB_CheckCategory_Call{Call: _e.mock.On("CheckCategory", _a0, _a1, _a2)}
+//   - _a2 *models.Attachment
+func (_e *DB_Expecter) AddAttachment(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_AddAttachment_Call {
+	return &DB_AddAttachment_Call{Call: _e.mock.On("AddAttachment", _a0, _a1, _a2)}
 }
 
-func (_c *DB_CheckCategory_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_CheckCategory_Call {
+func (_c *DB_AddAttachment_Call) Run(run func(_a0 context.Context, _a1 int, _a2 *models.Attachment)) *DB_AddAttachment_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.Attachment))
 	})
 	return _c
 }
 
-func (_c *DB_CheckCategory_Call) Return(_a0 bool, _a1 error) *DB_CheckCategory_Call {
+func (_c *DB_AddAttachment_Call) Return(_a0 models.Attachment, _a1 error) *DB_AddAttachment_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *DB_CheckCategory_Call) RunAndReturn(run func(context.Context, int, int) (bool, error)) *DB_CheckCategory_Call {
+func (_c *DB_AddAttachment_Call) RunAndReturn(run func(context.Context, int, *models.Attachment) (models.Attachment, error)) *DB_AddAttachment_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateUser provides a mock function with given fields: _a0, _a1
-func (_m *DB) CreateUser(_a0 context.Context, _a1 *models.User) (models.User, error) {
-	ret := _m.Called(_a0, _a1)
+// AddBudget provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) AddBudget(_a0 context.Context, _a1 int, _a2 *models.Budget) (models.Budget, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateUser")
+		panic("no return value specified for AddBudget")
 	}
 
-	var r0 models.User
+	var r0 models.Budget
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.User) (models.User, error)); ok {
-		return rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Budget) (models.Budget, error)); ok {
+		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *models.User) models.User); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Budget) models.Budget); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Get(0).(models.User)
+		r0 = ret.Get(0).(models.Budget)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *models.User) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.Budget) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -227,104 +227,115 @@ func (_m *DB) CreateUser(_a0 context.Context, _a1 *models.User) (models.User, er
 	return r0, r1
 }
 
-// DB_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
-type DB_CreateUser_Call struct {
+// DB_AddBudget_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddBudget'
+type DB_AddBudget_Call struct {
 	*mock.Call
 }
 
-// CreateUser is a helper method to define mock.On call
+// AddBudget is a helper method to define mock.On call
 //   - _a0 context.Context
-//   - _a1 *models.User
-func (_e *DB_Expecter) CreateUser(_a0 interface{}, _a1 interface{}) *DB_CreateUser_Call {
-	return &DB_CreateUser_Call{Call: _e.mock.On("CreateUser", _a0, _a1)}
+//   - _a1 int
+//   - _a2 *models.Budget
+func (_e *DB_Expecter) AddBudget(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_AddBudget_Call {
+	return &DB_AddBudget_Call{Call: _e.mock.On("AddBudget", _a0, _a1, _a2)}
 }
 
-func (_c *DB_CreateUser_Call) Run(run func(_a0 context.Context, _a1 *models.User)) *DB_CreateUser_Call {
+func (_c *DB_AddBudget_Call) Run(run func(_a0 context.Context, _a1 int, _a2 *models.Budget)) *DB_AddBudget_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*models.User))
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.Budget))
 	})
 	return _c
 }
 
-func (_c *DB_CreateUser_Call) Return(_a0 models.User, _a1 error) *DB_CreateUser_Call {
+func (_c *DB_AddBudget_Call) Return(_a0 models.Budget, _a1 error) *DB_AddBudget_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *DB_CreateUser_Call) RunAndReturn(run func(context.Context, *models.User) (models.User, error)) *DB_CreateUser_Call {
+func (_c *DB_AddBudget_Call) RunAndReturn(run func(context.Context, int, *models.Budget) (models.Budget, error)) *DB_AddBudget_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteTransaction provides a mock function with given fields: _a0, _a1, _a2
-func (_m *DB) DeleteTransaction(_a0 context.Context, _a1 int, _a2 int) error {
+// AddCategory provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) AddCategory(_a0 context.Context, _a1 int, _a2 *models.Category) (models.Category, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteTransaction")
+		panic("no return value specified for AddCategory")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+	var r0 models.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Category) (models.Category, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Category) models.Category); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(models.Category)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.Category) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// DB_DeleteTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTransaction'
-type DB_DeleteTransaction_Call struct {
+// DB_AddCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddCategory'
+type DB_AddCategory_Call struct {
 	*mock.Call
 }
 
-// DeleteTransaction is a helper method to define mock.On call
+// AddCategory is a helper method to define mock.On call
 //   - _a0 context.Context
 //   - _a1 int
-//   - _a2 int
-func (_e *DB_Expecter) DeleteTransaction(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_DeleteTransaction_Call {
-	return &DB_DeleteTransaction_Call{Call: _e.mock.On("DeleteTransaction", _a0, _a1, _a2)}
+//   - _a2 *models.Category
+func (_e *DB_Expecter) AddCategory(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_AddCategory_Call {
+	return &DB_AddCategory_Call{Call: _e.mock.On("AddCategory", _a0, _a1, _a2)}
 }
 
-func (_c *DB_DeleteTransaction_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_DeleteTransaction_Call {
+func (_c *DB_AddCategory_Call) Run(run func(_a0 context.Context, _a1 int, _a2 *models.Category)) *DB_AddCategory_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.Category))
 	})
 	return _c
 }
 
-func (_c *DB_DeleteTransaction_Call) Return(_a0 error) *DB_DeleteTransaction_Call {
-	_c.Call.Return(_a0)
+func (_c *DB_AddCategory_Call) Return(_a0 models.Category, _a1 error) *DB_AddCategory_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *DB_DeleteTransaction_Call) RunAndReturn(run func(context.Context, int, int) error) *DB_DeleteTransaction_Call {
+func (_c *DB_AddCategory_Call) RunAndReturn(run func(context.Context, int, *models.Category) (models.Category, error)) *DB_AddCategory_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetSummary provides a mock function with given fields: _a0, _a1, _a2, _a3
-func (_m *DB) GetSummary(_a0 context.Context, _a1 int, _a2 time.Time, _a3 time.Time) (models.Summary, error) {
-	ret := _m.Called(_a0, _a1, _a2, _a3)
+// AddCategoryRule provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) AddCategoryRule(_a0 context.Context, _a1 int, _a2 *models.CategoryRule) (models.CategoryRule, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetSummary")
+		panic("no return value specified for AddCategoryRule")
 	}
 
-	var r0 models.Summary
+	var r0 models.CategoryRule
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time) (models.Summary, error)); ok {
-		return rf(_a0, _a1, _a2, _a3)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.CategoryRule) (models.CategoryRule, error)); ok {
+		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time) models.Summary); ok {
-		r0 = rf(_a0, _a1, _a2, _a3)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.CategoryRule) models.CategoryRule); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Get(0).(models.Summary)
+		r0 = ret.Get(0).(models.CategoryRule)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, time.Time) error); ok {
-		r1 = rf(_a0, _a1, _a2, _a3)
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.CategoryRule) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -332,57 +343,56 @@ func (_m *DB) GetSummary(_a0 context.Context, _a1 int, _a2 time.Time, _a3 time.T
 	return r0, r1
 }
 
-// DB_GetSummary_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSummary'
-type DB_GetSummary_Call struct {
+// DB_AddCategoryRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddCategoryRule'
+type DB_AddCategoryRule_Call struct {
 	*mock.Call
 }
 
-// GetSummary is a helper method to define mock.On call
+// AddCategoryRule is a helper method to define mock.On call
 //   - _a0 context.Context
 //   - _a1 int
-//   - _a2 time.Time
-//   - _a3 time.Time
-func (_e *DB_Expecter) GetSummary(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_GetSummary_Call {
-	return &DB_GetSummary_Call{Call: _e.mock.On("GetSummary", _a0, _a1, _a2, _a3)}
+//   - _a2 *models.CategoryRule
+func (_e *DB_Expecter) AddCategoryRule(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_AddCategoryRule_Call {
+	return &DB_AddCategoryRule_Call{Call: _e.mock.On("AddCategoryRule", _a0, _a1, _a2)}
 }
 
-func (_c *DB_GetSummary_Call) Run(run func(_a0 context.Context, _a1 int, _a2 time.Time, _a3 time.Time)) *DB_GetSummary_Call {
+func (_c *DB_AddCategoryRule_Call) Run(run func(_a0 context.Context, _a1 int, _a2 *models.CategoryRule)) *DB_AddCategoryRule_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(time.Time), args[3].(time.Time))
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.CategoryRule))
 	})
 	return _c
 }
 
-func (_c *DB_GetSummary_Call) Return(_a0 models.Summary, _a1 error) *DB_GetSummary_Call {
+func (_c *DB_AddCategoryRule_Call) Return(_a0 models.CategoryRule, _a1 error) *DB_AddCategoryRule_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *DB_GetSummary_Call) RunAndReturn(run func(context.Context, int, time.Time, time.Time) (models.Summary, error)) *DB_GetSummary_Call {
+func (_c *DB_AddCategoryRule_Call) RunAndReturn(run func(context.Context, int, *models.CategoryRule) (models.CategoryRule, error)) *DB_AddCategoryRule_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTransactionByID provides a mock function with given fields: _a0, _a1, _a2
-func (_m *DB) GetTransactionByID(_a0 context.Context, _a1 int, _a2 int) (models.Transaction, error) {
+// AddRecurringRule provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) AddRecurringRule(_a0 context.Context, _a1 int, _a2 *models.RecurringRule) (models.RecurringRule, error) {
 	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTransactionByID")
+		panic("no return value specified for AddRecurringRule")
 	}
 
-	var r0 models.Transaction
+	var r0 models.RecurringRule
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) (models.Transaction, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.RecurringRule) (models.RecurringRule, error)); ok {
 		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) models.Transaction); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.RecurringRule) models.RecurringRule); ok {
 		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Get(0).(models.Transaction)
+		r0 = ret.Get(0).(models.RecurringRule)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.RecurringRule) error); ok {
 		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
@@ -391,59 +401,57 @@ func (_m *DB) GetTransactionByID(_a0 context.Context, _a1 int, _a2 int) (models.
 	return r0, r1
 }
 
-// DB_GetTransactionByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransactionByID'
-type DB_GetTransactionByID_Call struct {
+// DB_AddRecurringRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddRecurringRule'
+type DB_AddRecurringRule_Call struct {
 	*mock.Call
 }
 
-// GetTransactionByID is a helper method to define mock.On call
+// AddRecurringRule is a helper method to define mock.On call
 //   - _a0 context.Context
 //   - _a1 int
-//   - _a2 int
-func (_e *DB_Expecter) GetTransactionByID(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_GetTransactionByID_Call {
-	return &DB_GetTransactionByID_Call{Call: _e.mock.On("GetTransactionByID", _a0, _a1, _a2)}
+//   - _a2 *models.RecurringRule
+func (_e *DB_Expecter) AddRecurringRule(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_AddRecurringRule_Call {
+	return &DB_AddRecurringRule_Call{Call: _e.mock.On("AddRecurringRule", _a0, _a1, _a2)}
 }
 
-func (_c *DB_GetTransactionByID_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_GetTransactionByID_Call {
+func (_c *DB_AddRecurringRule_Call) Run(run func(_a0 context.Context, _a1 int, _a2 *models.RecurringRule)) *DB_AddRecurringRule_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.RecurringRule))
 	})
 	return _c
 }
 
-func (_c *DB_GetTransactionByID_Call) Return(_a0 models.Transaction, _a1 error) *DB_GetTransactionByID_Call {
+func (_c *DB_AddRecurringRule_Call) Return(_a0 models.RecurringRule, _a1 error) *DB_AddRecurringRule_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *DB_GetTransactionByID_Call) RunAndReturn(run func(context.Context, int, int) (models.Transaction, error)) *DB_GetTransactionByID_Call {
+func (_c *DB_AddRecurringRule_Call) RunAndReturn(run func(context.Context, int, *models.RecurringRule) (models.RecurringRule, error)) *DB_AddRecurringRule_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetTransactions provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4, _a5, _a6, _a7
-func (_m *DB) GetTransactions(_a0 context.Context, _a1 int, _a2 *bool, _a3 *int, _a4 *time.Time, _a5 *time.Time, _a6 int, _a7 int) ([]*models.Transaction, error) {
-	ret := _m.Called(_a0, _a1, _a2, _a3, _a4, _a5, _a6, _a7)
+// AddTransaction provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) AddTransaction(_a0 context.Context, _a1 int, _a2 *models.Transaction) (models.Transaction, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTransactions")
+		panic("no return value specified for AddTransaction")
 	}
 
-	var r0 []*models.Transaction
+	var r0 models.Transaction
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, *bool, *int, *time.Time, *time.Time, int, int) ([]*models.Transaction, error)); ok {
-		return rf(_a0, _a1, _a2, _a3, _a4, _a5, _a6, _a7)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Transaction) (models.Transaction, error)); ok {
+		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, *bool, *int, *time.Time, *time.Time, int, int) []*models.Transaction); ok {
-		r0 = rf(_a0, _a1, _a2, _a3, _a4, _a5, _a6, _a7)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Transaction) models.Transaction); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.Transaction)
-		}
+		r0 = ret.Get(0).(models.Transaction)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, *bool, *int, *time.Time, *time.Time, int, int) error); ok {
-		r1 = rf(_a0, _a1, _a2, _a3, _a4, _a5, _a6, _a7)
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.Transaction) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -451,62 +459,57 @@ func (_m *DB) GetTransactions(_a0 context.Context, _a1 int, _a2 *bool, _a3 *int,
 	return r0, r1
 }
 
-// DB_GetTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransactions'
-type DB_GetTransactions_Call struct {
+// DB_AddTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddTransaction'
+type DB_AddTransaction_Call struct {
 	*mock.Call
 }
 
-// GetTransactions is a helper method to define mock.On call
+// AddTransaction is a helper method to define mock.On call
 //   - _a0 context.Context
 //   - _a1 int
-//   - _a2 *bool
-//   - _a3 *int
-//   - _a4 *time.Time
-//   - _a5 *time.Time
-//   - _a6 int
-//   - _a7 int
-func (_e *DB_Expecter) GetTransactions(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}, _a4 interface{}, _a5 interface{}, _a6 interface{}, _a7 interface{}) *DB_GetTransactions_Call {
-	return &DB_GetTransactions_Call{Call: _e.mock.On("GetTransactions", _a0, _a1, _a2, _a3, _a4, _a5, _a6, _a7)}
+//   - _a2 *models.Transaction
+func (_e *DB_Expecter) AddTransaction(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_AddTransaction_Call {
+	return &DB_AddTransaction_Call{Call: _e.mock.On("AddTransaction", _a0, _a1, _a2)}
 }
 
-func (_c *DB_GetTransactions_Call) Run(run func(_a0 context.Context, _a1 int, _a2 *bool, _a3 *int, _a4 *time.Time, _a5 *time.Time, _a6 int, _a7 int)) *DB_GetTransactions_Call {
+func (_c *DB_AddTransaction_Call) Run(run func(_a0 context.Context, _a1 int, _a2 *models.Transaction)) *DB_AddTransaction_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(*bool), args[3].(*int), args[4].(*time.Time), args[5].(*time.Time), args[6].(int), args[7].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.Transaction))
 	})
 	return _c
 }
 
-func (_c *DB_GetTransactions_Call) Return(_a0 []*models.Transaction, _a1 error) *DB_GetTransactions_Call {
+func (_c *DB_AddTransaction_Call) Return(_a0 models.Transaction, _a1 error) *DB_AddTransaction_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *DB_GetTransactions_Call) RunAndReturn(run func(context.Context, int, *bool, *int, *time.Time, *time.Time, int, int) ([]*models.Transaction, error)) *DB_GetTransactions_Call {
+func (_c *DB_AddTransaction_Call) RunAndReturn(run func(context.Context, int, *models.Transaction) (models.Transaction, error)) *DB_AddTransaction_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserByEmail provides a mock function with given fields: _a0, _a1
-func (_m *DB) GetUserByEmail(_a0 context.Context, _a1 string) (models.User, error) {
-	ret := _m.Called(_a0, _a1)
+// AddTransfer provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) AddTransfer(_a0 context.Context, _a1 int, _a2 *models.Transfer) (models.Transfer, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserByEmail")
+		panic("no return value specified for AddTransfer")
 	}
 
-	var r0 models.User
+	var r0 models.Transfer
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (models.User, error)); ok {
-		return rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Transfer) (models.Transfer, error)); ok {
+		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) models.User); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.Transfer) models.Transfer); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Get(0).(models.User)
+		r0 = ret.Get(0).(models.Transfer)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.Transfer) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -514,56 +517,57 @@ func (_m *DB) GetUserByEmail(_a0 context.Context, _a1 string) (models.User, erro
 	return r0, r1
 }
 
-// DB_GetUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByEmail'
-type DB_GetUserByEmail_Call struct {
+// DB_AddTransfer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddTransfer'
+type DB_AddTransfer_Call struct {
 	*mock.Call
 }
 
-// GetUserByEmail is a helper method to define mock.On call
+// AddTransfer is a helper method to define mock.On call
 //   - _a0 context.Context
-//   - _a1 string
-func (_e *DB_Expecter) GetUserByEmail(_a0 interface{}, _a1 interface{}) *DB_GetUserByEmail_Call {
-	return &DB_GetUserByEmail_Call{Call: _e.mock.On("GetUserByEmail", _a0, _a1)}
+//   - _a1 int
+//   - _a2 *models.Transfer
+func (_e *DB_Expecter) AddTransfer(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_AddTransfer_Call {
+	return &DB_AddTransfer_Call{Call: _e.mock.On("AddTransfer", _a0, _a1, _a2)}
 }
 
-func (_c *DB_GetUserByEmail_Call) Run(run func(_a0 context.Context, _a1 string)) *DB_GetUserByEmail_Call {
+func (_c *DB_AddTransfer_Call) Run(run func(_a0 context.Context, _a1 int, _a2 *models.Transfer)) *DB_AddTransfer_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.Transfer))
 	})
 	return _c
 }
 
-func (_c *DB_GetUserByEmail_Call) Return(_a0 models.User, _a1 error) *DB_GetUserByEmail_Call {
+func (_c *DB_AddTransfer_Call) Return(_a0 models.Transfer, _a1 error) *DB_AddTransfer_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *DB_GetUserByEmail_Call) RunAndReturn(run func(context.Context, string) (models.User, error)) *DB_GetUserByEmail_Call {
+func (_c *DB_AddTransfer_Call) RunAndReturn(run func(context.Context, int, *models.Transfer) (models.Transfer, error)) *DB_AddTransfer_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserByID provides a mock function with given fields: _a0, _a1
-func (_m *DB) GetUserByID(_a0 context.Context, _a1 int) (models.User, error) {
-	ret := _m.Called(_a0, _a1)
+// ApplyRuleMatches provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) ApplyRuleMatches(_a0 context.Context, _a1 int, _a2 []models.RuleMatch) (int, error) {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserByID")
+		panic("no return value specified for ApplyRuleMatches")
 	}
 
-	var r0 models.User
+	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) (models.User, error)); ok {
-		return rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.RuleMatch) (int, error)); ok {
+		return rf(_a0, _a1, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) models.User); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.RuleMatch) int); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
-		r0 = ret.Get(0).(models.User)
+		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(_a0, _a1)
+	if rf, ok := ret.Get(1).(func(context.Context, int, []models.RuleMatch) error); ok {
+		r1 = rf(_a0, _a1, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -571,31 +575,3356 @@ func (_m *DB) GetUserByID(_a0 context.Context, _a1 int) (models.User, error) {
 	return r0, r1
 }
 
-// DB_GetUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByID'
-type DB_GetUserByID_Call struct {
+// DB_ApplyRuleMatches_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyRuleMatches'
+type DB_ApplyRuleMatches_Call struct {
 	*mock.Call
 }
 
-// GetUserByID is a helper method to define mock.On call
+// ApplyRuleMatches is a helper method to define mock.On call
 //   - _a0 context.Context
 //   - _a1 int
-func (_e *DB_Expecter) GetUserByID(_a0 interface{}, _a1 interface{}) *DB_GetUserByID_Call {
-	return &DB_GetUserByID_Call{Call: _e.mock.On("GetUserByID", _a0, _a1)}
+//   - _a2 []models.RuleMatch
+func (_e *DB_Expecter) ApplyRuleMatches(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_ApplyRuleMatches_Call {
+	return &DB_ApplyRuleMatches_Call{Call: _e.mock.On("ApplyRuleMatches", _a0, _a1, _a2)}
 }
 
-func (_c *DB_GetUserByID_Call) Run(run func(_a0 context.Context, _a1 int)) *DB_GetUserByID_Call {
+func (_c *DB_ApplyRuleMatches_Call) Run(run func(_a0 context.Context, _a1 int, _a2 []models.RuleMatch)) *DB_ApplyRuleMatches_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(int), args[2].([]models.RuleMatch))
 	})
 	return _c
 }
 
-func (_c *DB_GetUserByID_Call) Return(_a0 models.User, _a1 error) *DB_GetUserByID_Call {
+func (_c *DB_ApplyRuleMatches_Call) Return(_a0 int, _a1 error) *DB_ApplyRuleMatches_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *DB_GetUserByID_Call) RunAndReturn(run func(context.Context, int) (models.User, error)) *DB_GetUserByID_Call {
+func (_c *DB_ApplyRuleMatches_Call) RunAndReturn(run func(context.Context, int, []models.RuleMatch) (int, error)) *DB_ApplyRuleMatches_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CheckCategory provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) CheckCategory(_a0 context.Context, _a1 int, _a2 int) (bool, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckCategory")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (bool, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) bool); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_CheckCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckCategory'
+type DB_CheckCategory_Call struct {
+	*mock.Call
+}
+
+// CheckCategory is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+func (_e *DB_Expecter) CheckCategory(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_CheckCategory_Call {
+	return &DB_CheckCategory_Call{Call: _e.mock.On("CheckCategory", _a0, _a1, _a2)}
+}
+
+func (_c *DB_CheckCategory_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_CheckCategory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DB_CheckCategory_Call) Return(_a0 bool, _a1 error) *DB_CheckCategory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_CheckCategory_Call) RunAndReturn(run func(context.Context, int, int) (bool, error)) *DB_CheckCategory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateRefreshToken provides a mock function with given fields: _a0, _a1
+func (_m *DB) CreateRefreshToken(_a0 context.Context, _a1 *models.RefreshToken) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.RefreshToken) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_CreateRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRefreshToken'
+type DB_CreateRefreshToken_Call struct {
+	*mock.Call
+}
+
+// CreateRefreshToken is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *models.RefreshToken
+func (_e *DB_Expecter) CreateRefreshToken(_a0 interface{}, _a1 interface{}) *DB_CreateRefreshToken_Call {
+	return &DB_CreateRefreshToken_Call{Call: _e.mock.On("CreateRefreshToken", _a0, _a1)}
+}
+
+func (_c *DB_CreateRefreshToken_Call) Run(run func(_a0 context.Context, _a1 *models.RefreshToken)) *DB_CreateRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.RefreshToken))
+	})
+	return _c
+}
+
+func (_c *DB_CreateRefreshToken_Call) Return(_a0 error) *DB_CreateRefreshToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_CreateRefreshToken_Call) RunAndReturn(run func(context.Context, *models.RefreshToken) error) *DB_CreateRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateUser provides a mock function with given fields: _a0, _a1
+func (_m *DB) CreateUser(_a0 context.Context, _a1 *models.User) (models.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
+	}
+
+	var r0 models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) (models.User, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) models.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.User) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
+type DB_CreateUser_Call struct {
+	*mock.Call
+}
+
+// CreateUser is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *models.User
+func (_e *DB_Expecter) CreateUser(_a0 interface{}, _a1 interface{}) *DB_CreateUser_Call {
+	return &DB_CreateUser_Call{Call: _e.mock.On("CreateUser", _a0, _a1)}
+}
+
+func (_c *DB_CreateUser_Call) Run(run func(_a0 context.Context, _a1 *models.User)) *DB_CreateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.User))
+	})
+	return _c
+}
+
+func (_c *DB_CreateUser_Call) Return(_a0 models.User, _a1 error) *DB_CreateUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_CreateUser_Call) RunAndReturn(run func(context.Context, *models.User) (models.User, error)) *DB_CreateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateUserToken provides a mock function with given fields: _a0, _a1
+func (_m *DB) CreateUserToken(_a0 context.Context, _a1 *models.UserToken) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUserToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.UserToken) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_CreateUserToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUserToken'
+type DB_CreateUserToken_Call struct {
+	*mock.Call
+}
+
+// CreateUserToken is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *models.UserToken
+func (_e *DB_Expecter) CreateUserToken(_a0 interface{}, _a1 interface{}) *DB_CreateUserToken_Call {
+	return &DB_CreateUserToken_Call{Call: _e.mock.On("CreateUserToken", _a0, _a1)}
+}
+
+func (_c *DB_CreateUserToken_Call) Run(run func(_a0 context.Context, _a1 *models.UserToken)) *DB_CreateUserToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.UserToken))
+	})
+	return _c
+}
+
+func (_c *DB_CreateUserToken_Call) Return(_a0 error) *DB_CreateUserToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_CreateUserToken_Call) RunAndReturn(run func(context.Context, *models.UserToken) error) *DB_CreateUserToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAPIKey provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) DeleteAPIKey(_a0 context.Context, _a1 int, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_DeleteAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAPIKey'
+type DB_DeleteAPIKey_Call struct {
+	*mock.Call
+}
+
+// DeleteAPIKey is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+func (_e *DB_Expecter) DeleteAPIKey(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_DeleteAPIKey_Call {
+	return &DB_DeleteAPIKey_Call{Call: _e.mock.On("DeleteAPIKey", _a0, _a1, _a2)}
+}
+
+func (_c *DB_DeleteAPIKey_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_DeleteAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DB_DeleteAPIKey_Call) Return(_a0 error) *DB_DeleteAPIKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_DeleteAPIKey_Call) RunAndReturn(run func(context.Context, int, int) error) *DB_DeleteAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAccount provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) DeleteAccount(_a0 context.Context, _a1 int, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_DeleteAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAccount'
+type DB_DeleteAccount_Call struct {
+	*mock.Call
+}
+
+// DeleteAccount is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+func (_e *DB_Expecter) DeleteAccount(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_DeleteAccount_Call {
+	return &DB_DeleteAccount_Call{Call: _e.mock.On("DeleteAccount", _a0, _a1, _a2)}
+}
+
+func (_c *DB_DeleteAccount_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_DeleteAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DB_DeleteAccount_Call) Return(_a0 error) *DB_DeleteAccount_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_DeleteAccount_Call) RunAndReturn(run func(context.Context, int, int) error) *DB_DeleteAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteAttachment provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) DeleteAttachment(_a0 context.Context, _a1 int, _a2 int, _a3 int) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAttachment")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (string, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_DeleteAttachment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteAttachment'
+type DB_DeleteAttachment_Call struct {
+	*mock.Call
+}
+
+// DeleteAttachment is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+//   - _a3 int
+func (_e *DB_Expecter) DeleteAttachment(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_DeleteAttachment_Call {
+	return &DB_DeleteAttachment_Call{Call: _e.mock.On("DeleteAttachment", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_DeleteAttachment_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int, _a3 int)) *DB_DeleteAttachment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *DB_DeleteAttachment_Call) Return(_a0 string, _a1 error) *DB_DeleteAttachment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_DeleteAttachment_Call) RunAndReturn(run func(context.Context, int, int, int) (string, error)) *DB_DeleteAttachment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteBudget provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) DeleteBudget(_a0 context.Context, _a1 int, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBudget")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_DeleteBudget_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteBudget'
+type DB_DeleteBudget_Call struct {
+	*mock.Call
+}
+
+// DeleteBudget is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+func (_e *DB_Expecter) DeleteBudget(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_DeleteBudget_Call {
+	return &DB_DeleteBudget_Call{Call: _e.mock.On("DeleteBudget", _a0, _a1, _a2)}
+}
+
+func (_c *DB_DeleteBudget_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_DeleteBudget_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DB_DeleteBudget_Call) Return(_a0 error) *DB_DeleteBudget_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_DeleteBudget_Call) RunAndReturn(run func(context.Context, int, int) error) *DB_DeleteBudget_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteCategory provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) DeleteCategory(_a0 context.Context, _a1 int, _a2 int, _a3 *int) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCategory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *int) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_DeleteCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCategory'
+type DB_DeleteCategory_Call struct {
+	*mock.Call
+}
+
+// DeleteCategory is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+//   - _a3 *int
+func (_e *DB_Expecter) DeleteCategory(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_DeleteCategory_Call {
+	return &DB_DeleteCategory_Call{Call: _e.mock.On("DeleteCategory", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_DeleteCategory_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int, _a3 *int)) *DB_DeleteCategory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(*int))
+	})
+	return _c
+}
+
+func (_c *DB_DeleteCategory_Call) Return(_a0 error) *DB_DeleteCategory_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_DeleteCategory_Call) RunAndReturn(run func(context.Context, int, int, *int) error) *DB_DeleteCategory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteCategoryRule provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) DeleteCategoryRule(_a0 context.Context, _a1 int, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCategoryRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_DeleteCategoryRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCategoryRule'
+type DB_DeleteCategoryRule_Call struct {
+	*mock.Call
+}
+
+// DeleteCategoryRule is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+func (_e *DB_Expecter) DeleteCategoryRule(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_DeleteCategoryRule_Call {
+	return &DB_DeleteCategoryRule_Call{Call: _e.mock.On("DeleteCategoryRule", _a0, _a1, _a2)}
+}
+
+func (_c *DB_DeleteCategoryRule_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_DeleteCategoryRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DB_DeleteCategoryRule_Call) Return(_a0 error) *DB_DeleteCategoryRule_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_DeleteCategoryRule_Call) RunAndReturn(run func(context.Context, int, int) error) *DB_DeleteCategoryRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRecurringRule provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) DeleteRecurringRule(_a0 context.Context, _a1 int, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRecurringRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_DeleteRecurringRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRecurringRule'
+type DB_DeleteRecurringRule_Call struct {
+	*mock.Call
+}
+
+// DeleteRecurringRule is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+func (_e *DB_Expecter) DeleteRecurringRule(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_DeleteRecurringRule_Call {
+	return &DB_DeleteRecurringRule_Call{Call: _e.mock.On("DeleteRecurringRule", _a0, _a1, _a2)}
+}
+
+func (_c *DB_DeleteRecurringRule_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_DeleteRecurringRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DB_DeleteRecurringRule_Call) Return(_a0 error) *DB_DeleteRecurringRule_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_DeleteRecurringRule_Call) RunAndReturn(run func(context.Context, int, int) error) *DB_DeleteRecurringRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTag provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) DeleteTag(_a0 context.Context, _a1 int, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTag")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_DeleteTag_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTag'
+type DB_DeleteTag_Call struct {
+	*mock.Call
+}
+
+// DeleteTag is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+func (_e *DB_Expecter) DeleteTag(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_DeleteTag_Call {
+	return &DB_DeleteTag_Call{Call: _e.mock.On("DeleteTag", _a0, _a1, _a2)}
+}
+
+func (_c *DB_DeleteTag_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_DeleteTag_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DB_DeleteTag_Call) Return(_a0 error) *DB_DeleteTag_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_DeleteTag_Call) RunAndReturn(run func(context.Context, int, int) error) *DB_DeleteTag_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTransaction provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) DeleteTransaction(_a0 context.Context, _a1 int, _a2 int) ([]string, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTransaction")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]string, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []string); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_DeleteTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTransaction'
+type DB_DeleteTransaction_Call struct {
+	*mock.Call
+}
+
+// DeleteTransaction is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+func (_e *DB_Expecter) DeleteTransaction(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_DeleteTransaction_Call {
+	return &DB_DeleteTransaction_Call{Call: _e.mock.On("DeleteTransaction", _a0, _a1, _a2)}
+}
+
+func (_c *DB_DeleteTransaction_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_DeleteTransaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DB_DeleteTransaction_Call) Return(_a0 []string, _a1 error) *DB_DeleteTransaction_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_DeleteTransaction_Call) RunAndReturn(run func(context.Context, int, int) ([]string, error)) *DB_DeleteTransaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DisableTOTP provides a mock function with given fields: _a0, _a1
+func (_m *DB) DisableTOTP(_a0 context.Context, _a1 int) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_DisableTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisableTOTP'
+type DB_DisableTOTP_Call struct {
+	*mock.Call
+}
+
+// DisableTOTP is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+func (_e *DB_Expecter) DisableTOTP(_a0 interface{}, _a1 interface{}) *DB_DisableTOTP_Call {
+	return &DB_DisableTOTP_Call{Call: _e.mock.On("DisableTOTP", _a0, _a1)}
+}
+
+func (_c *DB_DisableTOTP_Call) Run(run func(_a0 context.Context, _a1 int)) *DB_DisableTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *DB_DisableTOTP_Call) Return(_a0 error) *DB_DisableTOTP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_DisableTOTP_Call) RunAndReturn(run func(context.Context, int) error) *DB_DisableTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnableTOTP provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) EnableTOTP(_a0 context.Context, _a1 int, _a2 int64, _a3 []string) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64, []string) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_EnableTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnableTOTP'
+type DB_EnableTOTP_Call struct {
+	*mock.Call
+}
+
+// EnableTOTP is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int64
+//   - _a3 []string
+func (_e *DB_Expecter) EnableTOTP(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_EnableTOTP_Call {
+	return &DB_EnableTOTP_Call{Call: _e.mock.On("EnableTOTP", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_EnableTOTP_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int64, _a3 []string)) *DB_EnableTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int64), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *DB_EnableTOTP_Call) Return(_a0 error) *DB_EnableTOTP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_EnableTOTP_Call) RunAndReturn(run func(context.Context, int, int64, []string) error) *DB_EnableTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExportTransactions provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) ExportTransactions(_a0 context.Context, _a1 int, _a2 models.TransactionFilter, _a3 func(models.ExportedTransaction) error) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportTransactions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.TransactionFilter, func(models.ExportedTransaction) error) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_ExportTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportTransactions'
+type DB_ExportTransactions_Call struct {
+	*mock.Call
+}
+
+// ExportTransactions is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 models.TransactionFilter
+//   - _a3 func(models.ExportedTransaction) error
+func (_e *DB_Expecter) ExportTransactions(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_ExportTransactions_Call {
+	return &DB_ExportTransactions_Call{Call: _e.mock.On("ExportTransactions", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_ExportTransactions_Call) Run(run func(_a0 context.Context, _a1 int, _a2 models.TransactionFilter, _a3 func(models.ExportedTransaction) error)) *DB_ExportTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.TransactionFilter), args[3].(func(models.ExportedTransaction) error))
+	})
+	return _c
+}
+
+func (_c *DB_ExportTransactions_Call) Return(_a0 error) *DB_ExportTransactions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_ExportTransactions_Call) RunAndReturn(run func(context.Context, int, models.TransactionFilter, func(models.ExportedTransaction) error) error) *DB_ExportTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAPIKeys provides a mock function with given fields: _a0, _a1
+func (_m *DB) GetAPIKeys(_a0 context.Context, _a1 int) ([]models.APIKey, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPIKeys")
+	}
+
+	var r0 []models.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.APIKey, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.APIKey); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetAPIKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAPIKeys'
+type DB_GetAPIKeys_Call struct {
+	*mock.Call
+}
+
+// GetAPIKeys is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+func (_e *DB_Expecter) GetAPIKeys(_a0 interface{}, _a1 interface{}) *DB_GetAPIKeys_Call {
+	return &DB_GetAPIKeys_Call{Call: _e.mock.On("GetAPIKeys", _a0, _a1)}
+}
+
+func (_c *DB_GetAPIKeys_Call) Run(run func(_a0 context.Context, _a1 int)) *DB_GetAPIKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetAPIKeys_Call) Return(_a0 []models.APIKey, _a1 error) *DB_GetAPIKeys_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetAPIKeys_Call) RunAndReturn(run func(context.Context, int) ([]models.APIKey, error)) *DB_GetAPIKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAccountByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) GetAccountByID(_a0 context.Context, _a1 int, _a2 int) (models.Account, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountByID")
+	}
+
+	var r0 models.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (models.Account, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) models.Account); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(models.Account)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetAccountByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAccountByID'
+type DB_GetAccountByID_Call struct {
+	*mock.Call
+}
+
+// GetAccountByID is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+func (_e *DB_Expecter) GetAccountByID(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_GetAccountByID_Call {
+	return &DB_GetAccountByID_Call{Call: _e.mock.On("GetAccountByID", _a0, _a1, _a2)}
+}
+
+func (_c *DB_GetAccountByID_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_GetAccountByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetAccountByID_Call) Return(_a0 models.Account, _a1 error) *DB_GetAccountByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetAccountByID_Call) RunAndReturn(run func(context.Context, int, int) (models.Account, error)) *DB_GetAccountByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAccounts provides a mock function with given fields: _a0, _a1
+func (_m *DB) GetAccounts(_a0 context.Context, _a1 int) ([]models.Account, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccounts")
+	}
+
+	var r0 []models.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Account, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Account); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetAccounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAccounts'
+type DB_GetAccounts_Call struct {
+	*mock.Call
+}
+
+// GetAccounts is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+func (_e *DB_Expecter) GetAccounts(_a0 interface{}, _a1 interface{}) *DB_GetAccounts_Call {
+	return &DB_GetAccounts_Call{Call: _e.mock.On("GetAccounts", _a0, _a1)}
+}
+
+func (_c *DB_GetAccounts_Call) Run(run func(_a0 context.Context, _a1 int)) *DB_GetAccounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetAccounts_Call) Return(_a0 []models.Account, _a1 error) *DB_GetAccounts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetAccounts_Call) RunAndReturn(run func(context.Context, int) ([]models.Account, error)) *DB_GetAccounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAttachment provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) GetAttachment(_a0 context.Context, _a1 int, _a2 int, _a3 int) (models.Attachment, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttachment")
+	}
+
+	var r0 models.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) (models.Attachment, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) models.Attachment); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(models.Attachment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetAttachment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAttachment'
+type DB_GetAttachment_Call struct {
+	*mock.Call
+}
+
+// GetAttachment is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+//   - _a3 int
+func (_e *DB_Expecter) GetAttachment(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_GetAttachment_Call {
+	return &DB_GetAttachment_Call{Call: _e.mock.On("GetAttachment", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_GetAttachment_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int, _a3 int)) *DB_GetAttachment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetAttachment_Call) Return(_a0 models.Attachment, _a1 error) *DB_GetAttachment_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetAttachment_Call) RunAndReturn(run func(context.Context, int, int, int) (models.Attachment, error)) *DB_GetAttachment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAttachments provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) GetAttachments(_a0 context.Context, _a1 int, _a2 int) ([]models.Attachment, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttachments")
+	}
+
+	var r0 []models.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]models.Attachment, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []models.Attachment); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetAttachments_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAttachments'
+type DB_GetAttachments_Call struct {
+	*mock.Call
+}
+
+// GetAttachments is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+func (_e *DB_Expecter) GetAttachments(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_GetAttachments_Call {
+	return &DB_GetAttachments_Call{Call: _e.mock.On("GetAttachments", _a0, _a1, _a2)}
+}
+
+func (_c *DB_GetAttachments_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_GetAttachments_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetAttachments_Call) Return(_a0 []models.Attachment, _a1 error) *DB_GetAttachments_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetAttachments_Call) RunAndReturn(run func(context.Context, int, int) ([]models.Attachment, error)) *DB_GetAttachments_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBudgetByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) GetBudgetByID(_a0 context.Context, _a1 int, _a2 int) (models.Budget, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBudgetByID")
+	}
+
+	var r0 models.Budget
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (models.Budget, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) models.Budget); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(models.Budget)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetBudgetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBudgetByID'
+type DB_GetBudgetByID_Call struct {
+	*mock.Call
+}
+
+// GetBudgetByID is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+func (_e *DB_Expecter) GetBudgetByID(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_GetBudgetByID_Call {
+	return &DB_GetBudgetByID_Call{Call: _e.mock.On("GetBudgetByID", _a0, _a1, _a2)}
+}
+
+func (_c *DB_GetBudgetByID_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_GetBudgetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetBudgetByID_Call) Return(_a0 models.Budget, _a1 error) *DB_GetBudgetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetBudgetByID_Call) RunAndReturn(run func(context.Context, int, int) (models.Budget, error)) *DB_GetBudgetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBudgetStatuses provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) GetBudgetStatuses(_a0 context.Context, _a1 int, _a2 time.Time) ([]models.BudgetStatus, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBudgetStatuses")
+	}
+
+	var r0 []models.BudgetStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) ([]models.BudgetStatus, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) []models.BudgetStatus); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.BudgetStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetBudgetStatuses_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBudgetStatuses'
+type DB_GetBudgetStatuses_Call struct {
+	*mock.Call
+}
+
+// GetBudgetStatuses is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 time.Time
+func (_e *DB_Expecter) GetBudgetStatuses(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_GetBudgetStatuses_Call {
+	return &DB_GetBudgetStatuses_Call{Call: _e.mock.On("GetBudgetStatuses", _a0, _a1, _a2)}
+}
+
+func (_c *DB_GetBudgetStatuses_Call) Run(run func(_a0 context.Context, _a1 int, _a2 time.Time)) *DB_GetBudgetStatuses_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *DB_GetBudgetStatuses_Call) Return(_a0 []models.BudgetStatus, _a1 error) *DB_GetBudgetStatuses_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetBudgetStatuses_Call) RunAndReturn(run func(context.Context, int, time.Time) ([]models.BudgetStatus, error)) *DB_GetBudgetStatuses_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCategories provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) GetCategories(_a0 context.Context, _a1 int, _a2 bool) ([]models.Category, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCategories")
+	}
+
+	var r0 []models.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) ([]models.Category, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) []models.Category); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, bool) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetCategories_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCategories'
+type DB_GetCategories_Call struct {
+	*mock.Call
+}
+
+// GetCategories is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 bool
+func (_e *DB_Expecter) GetCategories(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_GetCategories_Call {
+	return &DB_GetCategories_Call{Call: _e.mock.On("GetCategories", _a0, _a1, _a2)}
+}
+
+func (_c *DB_GetCategories_Call) Run(run func(_a0 context.Context, _a1 int, _a2 bool)) *DB_GetCategories_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *DB_GetCategories_Call) Return(_a0 []models.Category, _a1 error) *DB_GetCategories_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetCategories_Call) RunAndReturn(run func(context.Context, int, bool) ([]models.Category, error)) *DB_GetCategories_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCategoryByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) GetCategoryByID(_a0 context.Context, _a1 int, _a2 int) (models.Category, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCategoryByID")
+	}
+
+	var r0 models.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (models.Category, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) models.Category); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(models.Category)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetCategoryByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCategoryByID'
+type DB_GetCategoryByID_Call struct {
+	*mock.Call
+}
+
+// GetCategoryByID is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+func (_e *DB_Expecter) GetCategoryByID(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_GetCategoryByID_Call {
+	return &DB_GetCategoryByID_Call{Call: _e.mock.On("GetCategoryByID", _a0, _a1, _a2)}
+}
+
+func (_c *DB_GetCategoryByID_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_GetCategoryByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetCategoryByID_Call) Return(_a0 models.Category, _a1 error) *DB_GetCategoryByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetCategoryByID_Call) RunAndReturn(run func(context.Context, int, int) (models.Category, error)) *DB_GetCategoryByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCategoryReport provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) GetCategoryReport(_a0 context.Context, _a1 int, _a2 models.TransactionFilter) (models.CategoryReport, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCategoryReport")
+	}
+
+	var r0 models.CategoryReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.TransactionFilter) (models.CategoryReport, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.TransactionFilter) models.CategoryReport); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(models.CategoryReport)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, models.TransactionFilter) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetCategoryReport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCategoryReport'
+type DB_GetCategoryReport_Call struct {
+	*mock.Call
+}
+
+// GetCategoryReport is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 models.TransactionFilter
+func (_e *DB_Expecter) GetCategoryReport(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_GetCategoryReport_Call {
+	return &DB_GetCategoryReport_Call{Call: _e.mock.On("GetCategoryReport", _a0, _a1, _a2)}
+}
+
+func (_c *DB_GetCategoryReport_Call) Run(run func(_a0 context.Context, _a1 int, _a2 models.TransactionFilter)) *DB_GetCategoryReport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.TransactionFilter))
+	})
+	return _c
+}
+
+func (_c *DB_GetCategoryReport_Call) Return(_a0 models.CategoryReport, _a1 error) *DB_GetCategoryReport_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetCategoryReport_Call) RunAndReturn(run func(context.Context, int, models.TransactionFilter) (models.CategoryReport, error)) *DB_GetCategoryReport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCategoryRules provides a mock function with given fields: _a0, _a1
+func (_m *DB) GetCategoryRules(_a0 context.Context, _a1 int) ([]models.CategoryRule, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCategoryRules")
+	}
+
+	var r0 []models.CategoryRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.CategoryRule, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.CategoryRule); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.CategoryRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetCategoryRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCategoryRules'
+type DB_GetCategoryRules_Call struct {
+	*mock.Call
+}
+
+// GetCategoryRules is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+func (_e *DB_Expecter) GetCategoryRules(_a0 interface{}, _a1 interface{}) *DB_GetCategoryRules_Call {
+	return &DB_GetCategoryRules_Call{Call: _e.mock.On("GetCategoryRules", _a0, _a1)}
+}
+
+func (_c *DB_GetCategoryRules_Call) Run(run func(_a0 context.Context, _a1 int)) *DB_GetCategoryRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetCategoryRules_Call) Return(_a0 []models.CategoryRule, _a1 error) *DB_GetCategoryRules_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetCategoryRules_Call) RunAndReturn(run func(context.Context, int) ([]models.CategoryRule, error)) *DB_GetCategoryRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLoginAttempts provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) GetLoginAttempts(_a0 context.Context, _a1 int, _a2 int, _a3 int) ([]models.LoginAttempt, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginAttempts")
+	}
+
+	var r0 []models.LoginAttempt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) ([]models.LoginAttempt, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) []models.LoginAttempt); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.LoginAttempt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetLoginAttempts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLoginAttempts'
+type DB_GetLoginAttempts_Call struct {
+	*mock.Call
+}
+
+// GetLoginAttempts is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+//   - _a3 int
+func (_e *DB_Expecter) GetLoginAttempts(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_GetLoginAttempts_Call {
+	return &DB_GetLoginAttempts_Call{Call: _e.mock.On("GetLoginAttempts", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_GetLoginAttempts_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int, _a3 int)) *DB_GetLoginAttempts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetLoginAttempts_Call) Return(_a0 []models.LoginAttempt, _a1 error) *DB_GetLoginAttempts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetLoginAttempts_Call) RunAndReturn(run func(context.Context, int, int, int) ([]models.LoginAttempt, error)) *DB_GetLoginAttempts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLoginFailures provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) GetLoginFailures(_a0 context.Context, _a1 string, _a2 string, _a3 time.Time) (models.LoginFailures, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginFailures")
+	}
+
+	var r0 models.LoginFailures
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (models.LoginFailures, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) models.LoginFailures); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(models.LoginFailures)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetLoginFailures_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLoginFailures'
+type DB_GetLoginFailures_Call struct {
+	*mock.Call
+}
+
+// GetLoginFailures is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 string
+//   - _a3 time.Time
+func (_e *DB_Expecter) GetLoginFailures(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_GetLoginFailures_Call {
+	return &DB_GetLoginFailures_Call{Call: _e.mock.On("GetLoginFailures", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_GetLoginFailures_Call) Run(run func(_a0 context.Context, _a1 string, _a2 string, _a3 time.Time)) *DB_GetLoginFailures_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *DB_GetLoginFailures_Call) Return(_a0 models.LoginFailures, _a1 error) *DB_GetLoginFailures_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetLoginFailures_Call) RunAndReturn(run func(context.Context, string, string, time.Time) (models.LoginFailures, error)) *DB_GetLoginFailures_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMailRequests provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) GetMailRequests(_a0 context.Context, _a1 string, _a2 string, _a3 time.Time) (models.LoginFailures, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMailRequests")
+	}
+
+	var r0 models.LoginFailures
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (models.LoginFailures, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) models.LoginFailures); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(models.LoginFailures)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetMailRequests_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMailRequests'
+type DB_GetMailRequests_Call struct {
+	*mock.Call
+}
+
+// GetMailRequests is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 string
+//   - _a3 time.Time
+func (_e *DB_Expecter) GetMailRequests(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_GetMailRequests_Call {
+	return &DB_GetMailRequests_Call{Call: _e.mock.On("GetMailRequests", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_GetMailRequests_Call) Run(run func(_a0 context.Context, _a1 string, _a2 string, _a3 time.Time)) *DB_GetMailRequests_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *DB_GetMailRequests_Call) Return(_a0 models.LoginFailures, _a1 error) *DB_GetMailRequests_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetMailRequests_Call) RunAndReturn(run func(context.Context, string, string, time.Time) (models.LoginFailures, error)) *DB_GetMailRequests_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRecurringRules provides a mock function with given fields: _a0, _a1
+func (_m *DB) GetRecurringRules(_a0 context.Context, _a1 int) ([]models.RecurringRule, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecurringRules")
+	}
+
+	var r0 []models.RecurringRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.RecurringRule, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.RecurringRule); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.RecurringRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetRecurringRules_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRecurringRules'
+type DB_GetRecurringRules_Call struct {
+	*mock.Call
+}
+
+// GetRecurringRules is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+func (_e *DB_Expecter) GetRecurringRules(_a0 interface{}, _a1 interface{}) *DB_GetRecurringRules_Call {
+	return &DB_GetRecurringRules_Call{Call: _e.mock.On("GetRecurringRules", _a0, _a1)}
+}
+
+func (_c *DB_GetRecurringRules_Call) Run(run func(_a0 context.Context, _a1 int)) *DB_GetRecurringRules_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetRecurringRules_Call) Return(_a0 []models.RecurringRule, _a1 error) *DB_GetRecurringRules_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetRecurringRules_Call) RunAndReturn(run func(context.Context, int) ([]models.RecurringRule, error)) *DB_GetRecurringRules_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSummary provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) GetSummary(_a0 context.Context, _a1 int, _a2 time.Time, _a3 time.Time) (models.Summary, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSummary")
+	}
+
+	var r0 models.Summary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time) (models.Summary, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time) models.Summary); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(models.Summary)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, time.Time) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetSummary_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSummary'
+type DB_GetSummary_Call struct {
+	*mock.Call
+}
+
+// GetSummary is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 time.Time
+//   - _a3 time.Time
+func (_e *DB_Expecter) GetSummary(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_GetSummary_Call {
+	return &DB_GetSummary_Call{Call: _e.mock.On("GetSummary", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_GetSummary_Call) Run(run func(_a0 context.Context, _a1 int, _a2 time.Time, _a3 time.Time)) *DB_GetSummary_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *DB_GetSummary_Call) Return(_a0 models.Summary, _a1 error) *DB_GetSummary_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetSummary_Call) RunAndReturn(run func(context.Context, int, time.Time, time.Time) (models.Summary, error)) *DB_GetSummary_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTags provides a mock function with given fields: _a0, _a1
+func (_m *DB) GetTags(_a0 context.Context, _a1 int) ([]models.Tag, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTags")
+	}
+
+	var r0 []models.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.Tag, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.Tag); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetTags_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTags'
+type DB_GetTags_Call struct {
+	*mock.Call
+}
+
+// GetTags is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+func (_e *DB_Expecter) GetTags(_a0 interface{}, _a1 interface{}) *DB_GetTags_Call {
+	return &DB_GetTags_Call{Call: _e.mock.On("GetTags", _a0, _a1)}
+}
+
+func (_c *DB_GetTags_Call) Run(run func(_a0 context.Context, _a1 int)) *DB_GetTags_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetTags_Call) Return(_a0 []models.Tag, _a1 error) *DB_GetTags_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetTags_Call) RunAndReturn(run func(context.Context, int) ([]models.Tag, error)) *DB_GetTags_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTimeSeries provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) GetTimeSeries(_a0 context.Context, _a1 int, _a2 models.TransactionFilter, _a3 string) (models.TimeSeries, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTimeSeries")
+	}
+
+	var r0 models.TimeSeries
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.TransactionFilter, string) (models.TimeSeries, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.TransactionFilter, string) models.TimeSeries); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(models.TimeSeries)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, models.TransactionFilter, string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetTimeSeries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTimeSeries'
+type DB_GetTimeSeries_Call struct {
+	*mock.Call
+}
+
+// GetTimeSeries is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 models.TransactionFilter
+//   - _a3 string
+func (_e *DB_Expecter) GetTimeSeries(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_GetTimeSeries_Call {
+	return &DB_GetTimeSeries_Call{Call: _e.mock.On("GetTimeSeries", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_GetTimeSeries_Call) Run(run func(_a0 context.Context, _a1 int, _a2 models.TransactionFilter, _a3 string)) *DB_GetTimeSeries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.TransactionFilter), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *DB_GetTimeSeries_Call) Return(_a0 models.TimeSeries, _a1 error) *DB_GetTimeSeries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetTimeSeries_Call) RunAndReturn(run func(context.Context, int, models.TransactionFilter, string) (models.TimeSeries, error)) *DB_GetTimeSeries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTransactionByID provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) GetTransactionByID(_a0 context.Context, _a1 int, _a2 int) (models.Transaction, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionByID")
+	}
+
+	var r0 models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (models.Transaction, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) models.Transaction); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(models.Transaction)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetTransactionByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransactionByID'
+type DB_GetTransactionByID_Call struct {
+	*mock.Call
+}
+
+// GetTransactionByID is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+func (_e *DB_Expecter) GetTransactionByID(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_GetTransactionByID_Call {
+	return &DB_GetTransactionByID_Call{Call: _e.mock.On("GetTransactionByID", _a0, _a1, _a2)}
+}
+
+func (_c *DB_GetTransactionByID_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int)) *DB_GetTransactionByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetTransactionByID_Call) Return(_a0 models.Transaction, _a1 error) *DB_GetTransactionByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetTransactionByID_Call) RunAndReturn(run func(context.Context, int, int) (models.Transaction, error)) *DB_GetTransactionByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTransactions provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *DB) GetTransactions(_a0 context.Context, _a1 int, _a2 models.TransactionFilter, _a3 int, _a4 int) ([]*models.Transaction, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactions")
+	}
+
+	var r0 []*models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.TransactionFilter, int, int) ([]*models.Transaction, error)); ok {
+		return rf(_a0, _a1, _a2, _a3, _a4)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.TransactionFilter, int, int) []*models.Transaction); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, models.TransactionFilter, int, int) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransactions'
+type DB_GetTransactions_Call struct {
+	*mock.Call
+}
+
+// GetTransactions is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 models.TransactionFilter
+//   - _a3 int
+//   - _a4 int
+func (_e *DB_Expecter) GetTransactions(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}, _a4 interface{}) *DB_GetTransactions_Call {
+	return &DB_GetTransactions_Call{Call: _e.mock.On("GetTransactions", _a0, _a1, _a2, _a3, _a4)}
+}
+
+func (_c *DB_GetTransactions_Call) Run(run func(_a0 context.Context, _a1 int, _a2 models.TransactionFilter, _a3 int, _a4 int)) *DB_GetTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(models.TransactionFilter), args[3].(int), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetTransactions_Call) Return(_a0 []*models.Transaction, _a1 error) *DB_GetTransactions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetTransactions_Call) RunAndReturn(run func(context.Context, int, models.TransactionFilter, int, int) ([]*models.Transaction, error)) *DB_GetTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTwoFactor provides a mock function with given fields: _a0, _a1
+func (_m *DB) GetTwoFactor(_a0 context.Context, _a1 int) (models.TwoFactor, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTwoFactor")
+	}
+
+	var r0 models.TwoFactor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.TwoFactor, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.TwoFactor); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(models.TwoFactor)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetTwoFactor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTwoFactor'
+type DB_GetTwoFactor_Call struct {
+	*mock.Call
+}
+
+// GetTwoFactor is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+func (_e *DB_Expecter) GetTwoFactor(_a0 interface{}, _a1 interface{}) *DB_GetTwoFactor_Call {
+	return &DB_GetTwoFactor_Call{Call: _e.mock.On("GetTwoFactor", _a0, _a1)}
+}
+
+func (_c *DB_GetTwoFactor_Call) Run(run func(_a0 context.Context, _a1 int)) *DB_GetTwoFactor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetTwoFactor_Call) Return(_a0 models.TwoFactor, _a1 error) *DB_GetTwoFactor_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetTwoFactor_Call) RunAndReturn(run func(context.Context, int) (models.TwoFactor, error)) *DB_GetTwoFactor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByEmail provides a mock function with given fields: _a0, _a1
+func (_m *DB) GetUserByEmail(_a0 context.Context, _a1 string) (models.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByEmail")
+	}
+
+	var r0 models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.User, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByEmail'
+type DB_GetUserByEmail_Call struct {
+	*mock.Call
+}
+
+// GetUserByEmail is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *DB_Expecter) GetUserByEmail(_a0 interface{}, _a1 interface{}) *DB_GetUserByEmail_Call {
+	return &DB_GetUserByEmail_Call{Call: _e.mock.On("GetUserByEmail", _a0, _a1)}
+}
+
+func (_c *DB_GetUserByEmail_Call) Run(run func(_a0 context.Context, _a1 string)) *DB_GetUserByEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *DB_GetUserByEmail_Call) Return(_a0 models.User, _a1 error) *DB_GetUserByEmail_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetUserByEmail_Call) RunAndReturn(run func(context.Context, string) (models.User, error)) *DB_GetUserByEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByID provides a mock function with given fields: _a0, _a1
+func (_m *DB) GetUserByID(_a0 context.Context, _a1 int) (models.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (models.User, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) models.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_GetUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByID'
+type DB_GetUserByID_Call struct {
+	*mock.Call
+}
+
+// GetUserByID is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+func (_e *DB_Expecter) GetUserByID(_a0 interface{}, _a1 interface{}) *DB_GetUserByID_Call {
+	return &DB_GetUserByID_Call{Call: _e.mock.On("GetUserByID", _a0, _a1)}
+}
+
+func (_c *DB_GetUserByID_Call) Run(run func(_a0 context.Context, _a1 int)) *DB_GetUserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *DB_GetUserByID_Call) Return(_a0 models.User, _a1 error) *DB_GetUserByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_GetUserByID_Call) RunAndReturn(run func(context.Context, int) (models.User, error)) *DB_GetUserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ImportTransactions provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) ImportTransactions(_a0 context.Context, _a1 int, _a2 []models.ImportRow, _a3 models.ImportOptions) (models.ImportResult, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportTransactions")
+	}
+
+	var r0 models.ImportResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.ImportRow, models.ImportOptions) (models.ImportResult, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.ImportRow, models.ImportOptions) models.ImportResult); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(models.ImportResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, []models.ImportRow, models.ImportOptions) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_ImportTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ImportTransactions'
+type DB_ImportTransactions_Call struct {
+	*mock.Call
+}
+
+// ImportTransactions is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 []models.ImportRow
+//   - _a3 models.ImportOptions
+func (_e *DB_Expecter) ImportTransactions(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_ImportTransactions_Call {
+	return &DB_ImportTransactions_Call{Call: _e.mock.On("ImportTransactions", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_ImportTransactions_Call) Run(run func(_a0 context.Context, _a1 int, _a2 []models.ImportRow, _a3 models.ImportOptions)) *DB_ImportTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].([]models.ImportRow), args[3].(models.ImportOptions))
+	})
+	return _c
+}
+
+func (_c *DB_ImportTransactions_Call) Return(_a0 models.ImportResult, _a1 error) *DB_ImportTransactions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_ImportTransactions_Call) RunAndReturn(run func(context.Context, int, []models.ImportRow, models.ImportOptions) (models.ImportResult, error)) *DB_ImportTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsTokenRevoked provides a mock function with given fields: _a0, _a1
+func (_m *DB) IsTokenRevoked(_a0 context.Context, _a1 string) (bool, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsTokenRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_IsTokenRevoked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsTokenRevoked'
+type DB_IsTokenRevoked_Call struct {
+	*mock.Call
+}
+
+// IsTokenRevoked is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *DB_Expecter) IsTokenRevoked(_a0 interface{}, _a1 interface{}) *DB_IsTokenRevoked_Call {
+	return &DB_IsTokenRevoked_Call{Call: _e.mock.On("IsTokenRevoked", _a0, _a1)}
+}
+
+func (_c *DB_IsTokenRevoked_Call) Run(run func(_a0 context.Context, _a1 string)) *DB_IsTokenRevoked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *DB_IsTokenRevoked_Call) Return(_a0 bool, _a1 error) *DB_IsTokenRevoked_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_IsTokenRevoked_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *DB_IsTokenRevoked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MaterializeRecurring provides a mock function with given fields: _a0, _a1
+func (_m *DB) MaterializeRecurring(_a0 context.Context, _a1 time.Time) (int, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MaterializeRecurring")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_MaterializeRecurring_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MaterializeRecurring'
+type DB_MaterializeRecurring_Call struct {
+	*mock.Call
+}
+
+// MaterializeRecurring is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 time.Time
+func (_e *DB_Expecter) MaterializeRecurring(_a0 interface{}, _a1 interface{}) *DB_MaterializeRecurring_Call {
+	return &DB_MaterializeRecurring_Call{Call: _e.mock.On("MaterializeRecurring", _a0, _a1)}
+}
+
+func (_c *DB_MaterializeRecurring_Call) Run(run func(_a0 context.Context, _a1 time.Time)) *DB_MaterializeRecurring_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *DB_MaterializeRecurring_Call) Return(_a0 int, _a1 error) *DB_MaterializeRecurring_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_MaterializeRecurring_Call) RunAndReturn(run func(context.Context, time.Time) (int, error)) *DB_MaterializeRecurring_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordLoginAttempt provides a mock function with given fields: _a0, _a1
+func (_m *DB) RecordLoginAttempt(_a0 context.Context, _a1 *models.LoginAttempt) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordLoginAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.LoginAttempt) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_RecordLoginAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordLoginAttempt'
+type DB_RecordLoginAttempt_Call struct {
+	*mock.Call
+}
+
+// RecordLoginAttempt is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 *models.LoginAttempt
+func (_e *DB_Expecter) RecordLoginAttempt(_a0 interface{}, _a1 interface{}) *DB_RecordLoginAttempt_Call {
+	return &DB_RecordLoginAttempt_Call{Call: _e.mock.On("RecordLoginAttempt", _a0, _a1)}
+}
+
+func (_c *DB_RecordLoginAttempt_Call) Run(run func(_a0 context.Context, _a1 *models.LoginAttempt)) *DB_RecordLoginAttempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.LoginAttempt))
+	})
+	return _c
+}
+
+func (_c *DB_RecordLoginAttempt_Call) Return(_a0 error) *DB_RecordLoginAttempt_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_RecordLoginAttempt_Call) RunAndReturn(run func(context.Context, *models.LoginAttempt) error) *DB_RecordLoginAttempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReplaceRecoveryCodes provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) ReplaceRecoveryCodes(_a0 context.Context, _a1 int, _a2 []string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceRecoveryCodes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_ReplaceRecoveryCodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReplaceRecoveryCodes'
+type DB_ReplaceRecoveryCodes_Call struct {
+	*mock.Call
+}
+
+// ReplaceRecoveryCodes is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 []string
+func (_e *DB_Expecter) ReplaceRecoveryCodes(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_ReplaceRecoveryCodes_Call {
+	return &DB_ReplaceRecoveryCodes_Call{Call: _e.mock.On("ReplaceRecoveryCodes", _a0, _a1, _a2)}
+}
+
+func (_c *DB_ReplaceRecoveryCodes_Call) Run(run func(_a0 context.Context, _a1 int, _a2 []string)) *DB_ReplaceRecoveryCodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *DB_ReplaceRecoveryCodes_Call) Return(_a0 error) *DB_ReplaceRecoveryCodes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_ReplaceRecoveryCodes_Call) RunAndReturn(run func(context.Context, int, []string) error) *DB_ReplaceRecoveryCodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetPassword provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) ResetPassword(_a0 context.Context, _a1 string, _a2 string) (models.User, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (models.User, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) models.User); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type DB_ResetPassword_Call struct {
+	*mock.Call
+}
+
+// ResetPassword is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 string
+func (_e *DB_Expecter) ResetPassword(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_ResetPassword_Call {
+	return &DB_ResetPassword_Call{Call: _e.mock.On("ResetPassword", _a0, _a1, _a2)}
+}
+
+func (_c *DB_ResetPassword_Call) Run(run func(_a0 context.Context, _a1 string, _a2 string)) *DB_ResetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *DB_ResetPassword_Call) Return(_a0 models.User, _a1 error) *DB_ResetPassword_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_ResetPassword_Call) RunAndReturn(run func(context.Context, string, string) (models.User, error)) *DB_ResetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSession provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) RevokeSession(_a0 context.Context, _a1 int, _a2 string, _a3 time.Time) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, time.Time) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_RevokeSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSession'
+type DB_RevokeSession_Call struct {
+	*mock.Call
+}
+
+// RevokeSession is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 string
+//   - _a3 time.Time
+func (_e *DB_Expecter) RevokeSession(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_RevokeSession_Call {
+	return &DB_RevokeSession_Call{Call: _e.mock.On("RevokeSession", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_RevokeSession_Call) Run(run func(_a0 context.Context, _a1 int, _a2 string, _a3 time.Time)) *DB_RevokeSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *DB_RevokeSession_Call) Return(_a0 error) *DB_RevokeSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_RevokeSession_Call) RunAndReturn(run func(context.Context, int, string, time.Time) error) *DB_RevokeSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RotateRefreshToken provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) RotateRefreshToken(_a0 context.Context, _a1 string, _a2 *models.RefreshToken) (models.User, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateRefreshToken")
+	}
+
+	var r0 models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.RefreshToken) (models.User, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.RefreshToken) models.User); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *models.RefreshToken) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_RotateRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RotateRefreshToken'
+type DB_RotateRefreshToken_Call struct {
+	*mock.Call
+}
+
+// RotateRefreshToken is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 *models.RefreshToken
+func (_e *DB_Expecter) RotateRefreshToken(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_RotateRefreshToken_Call {
+	return &DB_RotateRefreshToken_Call{Call: _e.mock.On("RotateRefreshToken", _a0, _a1, _a2)}
+}
+
+func (_c *DB_RotateRefreshToken_Call) Run(run func(_a0 context.Context, _a1 string, _a2 *models.RefreshToken)) *DB_RotateRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*models.RefreshToken))
+	})
+	return _c
+}
+
+func (_c *DB_RotateRefreshToken_Call) Return(_a0 models.User, _a1 error) *DB_RotateRefreshToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_RotateRefreshToken_Call) RunAndReturn(run func(context.Context, string, *models.RefreshToken) (models.User, error)) *DB_RotateRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveExchangeRates provides a mock function with given fields: _a0, _a1
+func (_m *DB) SaveExchangeRates(_a0 context.Context, _a1 []models.ExchangeRate) (int, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveExchangeRates")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.ExchangeRate) (int, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.ExchangeRate) int); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.ExchangeRate) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_SaveExchangeRates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveExchangeRates'
+type DB_SaveExchangeRates_Call struct {
+	*mock.Call
+}
+
+// SaveExchangeRates is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 []models.ExchangeRate
+func (_e *DB_Expecter) SaveExchangeRates(_a0 interface{}, _a1 interface{}) *DB_SaveExchangeRates_Call {
+	return &DB_SaveExchangeRates_Call{Call: _e.mock.On("SaveExchangeRates", _a0, _a1)}
+}
+
+func (_c *DB_SaveExchangeRates_Call) Run(run func(_a0 context.Context, _a1 []models.ExchangeRate)) *DB_SaveExchangeRates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.ExchangeRate))
+	})
+	return _c
+}
+
+func (_c *DB_SaveExchangeRates_Call) Return(_a0 int, _a1 error) *DB_SaveExchangeRates_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_SaveExchangeRates_Call) RunAndReturn(run func(context.Context, []models.ExchangeRate) (int, error)) *DB_SaveExchangeRates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetTOTPSecret provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) SetTOTPSecret(_a0 context.Context, _a1 int, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTOTPSecret")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_SetTOTPSecret_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetTOTPSecret'
+type DB_SetTOTPSecret_Call struct {
+	*mock.Call
+}
+
+// SetTOTPSecret is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 string
+func (_e *DB_Expecter) SetTOTPSecret(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_SetTOTPSecret_Call {
+	return &DB_SetTOTPSecret_Call{Call: _e.mock.On("SetTOTPSecret", _a0, _a1, _a2)}
+}
+
+func (_c *DB_SetTOTPSecret_Call) Run(run func(_a0 context.Context, _a1 int, _a2 string)) *DB_SetTOTPSecret_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *DB_SetTOTPSecret_Call) Return(_a0 error) *DB_SetTOTPSecret_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_SetTOTPSecret_Call) RunAndReturn(run func(context.Context, int, string) error) *DB_SetTOTPSecret_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAccount provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) UpdateAccount(_a0 context.Context, _a1 int, _a2 int, _a3 *models.AccountUpdate) (models.Account, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAccount")
+	}
+
+	var r0 models.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.AccountUpdate) (models.Account, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.AccountUpdate) models.Account); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(models.Account)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, *models.AccountUpdate) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_UpdateAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAccount'
+type DB_UpdateAccount_Call struct {
+	*mock.Call
+}
+
+// UpdateAccount is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+//   - _a3 *models.AccountUpdate
+func (_e *DB_Expecter) UpdateAccount(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_UpdateAccount_Call {
+	return &DB_UpdateAccount_Call{Call: _e.mock.On("UpdateAccount", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_UpdateAccount_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int, _a3 *models.AccountUpdate)) *DB_UpdateAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(*models.AccountUpdate))
+	})
+	return _c
+}
+
+func (_c *DB_UpdateAccount_Call) Return(_a0 models.Account, _a1 error) *DB_UpdateAccount_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_UpdateAccount_Call) RunAndReturn(run func(context.Context, int, int, *models.AccountUpdate) (models.Account, error)) *DB_UpdateAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateBudget provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) UpdateBudget(_a0 context.Context, _a1 int, _a2 int, _a3 *models.BudgetUpdate) (models.Budget, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBudget")
+	}
+
+	var r0 models.Budget
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.BudgetUpdate) (models.Budget, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.BudgetUpdate) models.Budget); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(models.Budget)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, *models.BudgetUpdate) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_UpdateBudget_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateBudget'
+type DB_UpdateBudget_Call struct {
+	*mock.Call
+}
+
+// UpdateBudget is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+//   - _a3 *models.BudgetUpdate
+func (_e *DB_Expecter) UpdateBudget(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_UpdateBudget_Call {
+	return &DB_UpdateBudget_Call{Call: _e.mock.On("UpdateBudget", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_UpdateBudget_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int, _a3 *models.BudgetUpdate)) *DB_UpdateBudget_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(*models.BudgetUpdate))
+	})
+	return _c
+}
+
+func (_c *DB_UpdateBudget_Call) Return(_a0 models.Budget, _a1 error) *DB_UpdateBudget_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_UpdateBudget_Call) RunAndReturn(run func(context.Context, int, int, *models.BudgetUpdate) (models.Budget, error)) *DB_UpdateBudget_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCategory provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) UpdateCategory(_a0 context.Context, _a1 int, _a2 int, _a3 *models.CategoryUpdate) (models.Category, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCategory")
+	}
+
+	var r0 models.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.CategoryUpdate) (models.Category, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.CategoryUpdate) models.Category); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(models.Category)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, *models.CategoryUpdate) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_UpdateCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCategory'
+type DB_UpdateCategory_Call struct {
+	*mock.Call
+}
+
+// UpdateCategory is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+//   - _a3 *models.CategoryUpdate
+func (_e *DB_Expecter) UpdateCategory(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_UpdateCategory_Call {
+	return &DB_UpdateCategory_Call{Call: _e.mock.On("UpdateCategory", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_UpdateCategory_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int, _a3 *models.CategoryUpdate)) *DB_UpdateCategory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(*models.CategoryUpdate))
+	})
+	return _c
+}
+
+func (_c *DB_UpdateCategory_Call) Return(_a0 models.Category, _a1 error) *DB_UpdateCategory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_UpdateCategory_Call) RunAndReturn(run func(context.Context, int, int, *models.CategoryUpdate) (models.Category, error)) *DB_UpdateCategory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCategoryRule provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) UpdateCategoryRule(_a0 context.Context, _a1 int, _a2 int, _a3 *models.CategoryRule) (models.CategoryRule, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCategoryRule")
+	}
+
+	var r0 models.CategoryRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.CategoryRule) (models.CategoryRule, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.CategoryRule) models.CategoryRule); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(models.CategoryRule)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, *models.CategoryRule) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_UpdateCategoryRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCategoryRule'
+type DB_UpdateCategoryRule_Call struct {
+	*mock.Call
+}
+
+// UpdateCategoryRule is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+//   - _a3 *models.CategoryRule
+func (_e *DB_Expecter) UpdateCategoryRule(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_UpdateCategoryRule_Call {
+	return &DB_UpdateCategoryRule_Call{Call: _e.mock.On("UpdateCategoryRule", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_UpdateCategoryRule_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int, _a3 *models.CategoryRule)) *DB_UpdateCategoryRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(*models.CategoryRule))
+	})
+	return _c
+}
+
+func (_c *DB_UpdateCategoryRule_Call) Return(_a0 models.CategoryRule, _a1 error) *DB_UpdateCategoryRule_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_UpdateCategoryRule_Call) RunAndReturn(run func(context.Context, int, int, *models.CategoryRule) (models.CategoryRule, error)) *DB_UpdateCategoryRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateRecurringRule provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) UpdateRecurringRule(_a0 context.Context, _a1 int, _a2 int, _a3 *models.RecurringRuleUpdate) (models.RecurringRule, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRecurringRule")
+	}
+
+	var r0 models.RecurringRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.RecurringRuleUpdate) (models.RecurringRule, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.RecurringRuleUpdate) models.RecurringRule); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(models.RecurringRule)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, *models.RecurringRuleUpdate) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_UpdateRecurringRule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRecurringRule'
+type DB_UpdateRecurringRule_Call struct {
+	*mock.Call
+}
+
+// UpdateRecurringRule is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+//   - _a3 *models.RecurringRuleUpdate
+func (_e *DB_Expecter) UpdateRecurringRule(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_UpdateRecurringRule_Call {
+	return &DB_UpdateRecurringRule_Call{Call: _e.mock.On("UpdateRecurringRule", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_UpdateRecurringRule_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int, _a3 *models.RecurringRuleUpdate)) *DB_UpdateRecurringRule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(*models.RecurringRuleUpdate))
+	})
+	return _c
+}
+
+func (_c *DB_UpdateRecurringRule_Call) Return(_a0 models.RecurringRule, _a1 error) *DB_UpdateRecurringRule_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_UpdateRecurringRule_Call) RunAndReturn(run func(context.Context, int, int, *models.RecurringRuleUpdate) (models.RecurringRule, error)) *DB_UpdateRecurringRule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateTransaction provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *DB) UpdateTransaction(_a0 context.Context, _a1 int, _a2 int, _a3 *models.TransactionUpdate) (models.Transaction, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTransaction")
+	}
+
+	var r0 models.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.TransactionUpdate) (models.Transaction, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, *models.TransactionUpdate) models.Transaction); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(models.Transaction)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, *models.TransactionUpdate) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_UpdateTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTransaction'
+type DB_UpdateTransaction_Call struct {
+	*mock.Call
+}
+
+// UpdateTransaction is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+//   - _a3 *models.TransactionUpdate
+func (_e *DB_Expecter) UpdateTransaction(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *DB_UpdateTransaction_Call {
+	return &DB_UpdateTransaction_Call{Call: _e.mock.On("UpdateTransaction", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *DB_UpdateTransaction_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int, _a3 *models.TransactionUpdate)) *DB_UpdateTransaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(*models.TransactionUpdate))
+	})
+	return _c
+}
+
+func (_c *DB_UpdateTransaction_Call) Return(_a0 models.Transaction, _a1 error) *DB_UpdateTransaction_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_UpdateTransaction_Call) RunAndReturn(run func(context.Context, int, int, *models.TransactionUpdate) (models.Transaction, error)) *DB_UpdateTransaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUserSettings provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) UpdateUserSettings(_a0 context.Context, _a1 int, _a2 *models.UserSettings) (models.User, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserSettings")
+	}
+
+	var r0 models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.UserSettings) (models.User, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *models.UserSettings) models.User); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *models.UserSettings) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_UpdateUserSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserSettings'
+type DB_UpdateUserSettings_Call struct {
+	*mock.Call
+}
+
+// UpdateUserSettings is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 *models.UserSettings
+func (_e *DB_Expecter) UpdateUserSettings(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_UpdateUserSettings_Call {
+	return &DB_UpdateUserSettings_Call{Call: _e.mock.On("UpdateUserSettings", _a0, _a1, _a2)}
+}
+
+func (_c *DB_UpdateUserSettings_Call) Run(run func(_a0 context.Context, _a1 int, _a2 *models.UserSettings)) *DB_UpdateUserSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*models.UserSettings))
+	})
+	return _c
+}
+
+func (_c *DB_UpdateUserSettings_Call) Return(_a0 models.User, _a1 error) *DB_UpdateUserSettings_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_UpdateUserSettings_Call) RunAndReturn(run func(context.Context, int, *models.UserSettings) (models.User, error)) *DB_UpdateUserSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseAPIKey provides a mock function with given fields: _a0, _a1
+func (_m *DB) UseAPIKey(_a0 context.Context, _a1 string) (models.APIKey, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseAPIKey")
+	}
+
+	var r0 models.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.APIKey, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.APIKey); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(models.APIKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_UseAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseAPIKey'
+type DB_UseAPIKey_Call struct {
+	*mock.Call
+}
+
+// UseAPIKey is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *DB_Expecter) UseAPIKey(_a0 interface{}, _a1 interface{}) *DB_UseAPIKey_Call {
+	return &DB_UseAPIKey_Call{Call: _e.mock.On("UseAPIKey", _a0, _a1)}
+}
+
+func (_c *DB_UseAPIKey_Call) Run(run func(_a0 context.Context, _a1 string)) *DB_UseAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *DB_UseAPIKey_Call) Return(_a0 models.APIKey, _a1 error) *DB_UseAPIKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_UseAPIKey_Call) RunAndReturn(run func(context.Context, string) (models.APIKey, error)) *DB_UseAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseRecoveryCode provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) UseRecoveryCode(_a0 context.Context, _a1 int, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_UseRecoveryCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseRecoveryCode'
+type DB_UseRecoveryCode_Call struct {
+	*mock.Call
+}
+
+// UseRecoveryCode is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 string
+func (_e *DB_Expecter) UseRecoveryCode(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_UseRecoveryCode_Call {
+	return &DB_UseRecoveryCode_Call{Call: _e.mock.On("UseRecoveryCode", _a0, _a1, _a2)}
+}
+
+func (_c *DB_UseRecoveryCode_Call) Run(run func(_a0 context.Context, _a1 int, _a2 string)) *DB_UseRecoveryCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *DB_UseRecoveryCode_Call) Return(_a0 error) *DB_UseRecoveryCode_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_UseRecoveryCode_Call) RunAndReturn(run func(context.Context, int, string) error) *DB_UseRecoveryCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseTOTPStep provides a mock function with given fields: _a0, _a1, _a2
+func (_m *DB) UseTOTPStep(_a0 context.Context, _a1 int, _a2 int64) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTOTPStep")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DB_UseTOTPStep_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseTOTPStep'
+type DB_UseTOTPStep_Call struct {
+	*mock.Call
+}
+
+// UseTOTPStep is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int64
+func (_e *DB_Expecter) UseTOTPStep(_a0 interface{}, _a1 interface{}, _a2 interface{}) *DB_UseTOTPStep_Call {
+	return &DB_UseTOTPStep_Call{Call: _e.mock.On("UseTOTPStep", _a0, _a1, _a2)}
+}
+
+func (_c *DB_UseTOTPStep_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int64)) *DB_UseTOTPStep_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *DB_UseTOTPStep_Call) Return(_a0 error) *DB_UseTOTPStep_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *DB_UseTOTPStep_Call) RunAndReturn(run func(context.Context, int, int64) error) *DB_UseTOTPStep_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyEmail provides a mock function with given fields: _a0, _a1
+func (_m *DB) VerifyEmail(_a0 context.Context, _a1 string) (models.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 models.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.User, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DB_VerifyEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyEmail'
+type DB_VerifyEmail_Call struct {
+	*mock.Call
+}
+
+// VerifyEmail is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *DB_Expecter) VerifyEmail(_a0 interface{}, _a1 interface{}) *DB_VerifyEmail_Call {
+	return &DB_VerifyEmail_Call{Call: _e.mock.On("VerifyEmail", _a0, _a1)}
+}
+
+func (_c *DB_VerifyEmail_Call) Run(run func(_a0 context.Context, _a1 string)) *DB_VerifyEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *DB_VerifyEmail_Call) Return(_a0 models.User, _a1 error) *DB_VerifyEmail_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *DB_VerifyEmail_Call) RunAndReturn(run func(context.Context, string) (models.User, error)) *DB_VerifyEmail_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -1,30 +1,348 @@
 package models
 
 import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+	_ "time/tzdata" // имена часовых поясов проверяются и там, где в системе нет базы zoneinfo
 )
 
+// Money хранит сумму в минимальных единицах (центах), чтобы значения
+// NUMERIC(10,2) передавались без потерь на двоичной плавающей точке.
+// В JSON сумма представлена числом с фиксированной точкой: 25.50
+type Money int64
+
+// MaxAmount — наибольшая сумма, помещающаяся в колонку NUMERIC(10,2)
+const MaxAmount Money = 99999999_99
+
+var (
+	ErrAmountPrecision = errors.New("amount must have at most two decimal places")
+	ErrAmountRange     = errors.New("amount is out of range")
+	ErrAmountFormat    = errors.New("invalid amount format")
+	ErrTimeFormat      = errors.New("time must be a string in YYYY-MM-DD or RFC 3339 format")
+)
+
+var hundred = big.NewInt(100)
+
+// moneyPattern — допустимая запись суммы: big.Rat понимает и дроби "1/100", и
+// экспоненту "1e3", но клиенты так суммы не передают
+var moneyPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// ParseMoney разбирает десятичную запись суммы [-]цифры[.цифры] без промежуточного float64
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if !moneyPattern.MatchString(s) {
+		return 0, ErrAmountFormat
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, ErrAmountFormat
+	}
+	r.Mul(r, new(big.Rat).SetInt(hundred))
+	if !r.IsInt() {
+		return 0, ErrAmountPrecision
+	}
+	if !r.Num().IsInt64() {
+		return 0, ErrAmountRange
+	}
+	return Money(r.Num().Int64()), nil
+}
+
+func (m Money) String() string {
+	// Знак отделяется после деления: -math.MinInt64 не помещается в int64
+	units, cents := int64(m)/100, int64(m)%100
+	sign := ""
+	if m < 0 {
+		sign, units, cents = "-", -units, -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, units, cents)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON принимает как число (25.5), так и строку ("25.50")
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Scan реализует sql.Scanner; pgx передаёт NUMERIC в текстовом виде,
+// SQLite хранит суммы целым числом центов
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case int64:
+		*m = Money(v)
+		return nil
+	case string:
+		parsed, err := ParseMoney(v)
+		if err != nil {
+			return fmt.Errorf("cannot scan %q into Money: %v", v, err)
+		}
+		*m = parsed
+		return nil
+	case []byte:
+		return m.Scan(string(v))
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+}
+
+// Value реализует driver.Valuer, передавая сумму в NUMERIC без округления
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Date — календарная дата без времени, в JSON передаётся как "2006-01-02"
+type Date struct {
+	time.Time
+}
+
+func NewDate(t time.Time) Date {
+	return Date{time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+func (d Date) String() string {
+	return d.Format("2006-01-02")
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return fmt.Errorf("date must be a string in YYYY-MM-DD format")
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return fmt.Errorf("date must be a string in YYYY-MM-DD format")
+	}
+	d.Time = t
+	return nil
+}
+
+func (d *Date) Scan(src interface{}) error {
+	t, ok := src.(time.Time)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into Date", src)
+	}
+	*d = NewDate(t)
+	return nil
+}
+
+func (d Date) Value() (driver.Value, error) {
+	return d.Time, nil
+}
+
+// DateTime — момент времени, который клиент может передать датой "2006-01-02"
+// (полночь в часовом поясе пользователя) или временем RFC 3339; в ответах всегда RFC 3339
+type DateTime struct {
+	time.Time
+	DateOnly bool // передана дата без времени; Time — полночь этой даты в UTC
+}
+
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	return d.Time.MarshalJSON()
+}
+
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return ErrTimeFormat
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		d.Time, d.DateOnly = t, true
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return ErrTimeFormat
+	}
+	d.Time, d.DateOnly = t, false
+	return nil
+}
+
+func (d *DateTime) Scan(src interface{}) error {
+	t, ok := src.(time.Time)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into DateTime", src)
+	}
+	d.Time = t
+	return nil
+}
+
+func (d DateTime) Value() (driver.Value, error) {
+	return d.Time, nil
+}
+
 type Category struct {
-	ID          int    `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	UserID      int    `json:"user_id"`
+	ID               int    `json:"id"`
+	Name             string `json:"name"`
+	Description      string `json:"description,omitempty"`
+	UserID           int    `json:"user_id"`
+	ParentID         *int   `json:"parent_id,omitempty"`
+	Archived         bool   `json:"archived"`
+	TransactionCount int    `json:"transaction_count"`
+}
+
+// CategoryUpdate описывает частичное изменение категории: nil-поля не меняются
+type CategoryUpdate struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	ParentID    *int    `json:"parent_id"` // 0 — сделать категорию верхнего уровня
+	Archived    *bool   `json:"archived"`
 }
 
 type Transaction struct {
 	ID         int       `json:"id"`
 	IsIncome   bool      `json:"is_income"`
-	Amount     float64   `json:"amount"`
+	Amount     Money     `json:"amount"`
 	CategoryID int       `json:"category_id"`
 	UserID     int       `json:"user_id"`
 	Note       string    `json:"note,omitempty"`
-	CreatedAt  time.Time `json:"created_at"`
+	OccurredAt DateTime  `json:"occurred_at"` // когда совершена операция; по ней фильтруются и группируются транзакции
+	CreatedAt  time.Time `json:"created_at"`  // когда запись создана
+	UpdatedAt  time.Time `json:"updated_at"`  // когда запись последний раз изменена
+	Currency   string    `json:"currency"`    // ISO 4217; пусто — базовая валюта пользователя
+
+	RecurringRuleID *int     `json:"recurring_rule_id,omitempty"` // правило, по которому создана транзакция
+	AccountID       *int     `json:"account_id,omitempty"`
+	TransferID      *int     `json:"transfer_id,omitempty"` // транзакция — часть перевода между счетами
+	Tags            []string `json:"tags,omitempty"`
+	Splits          []Split  `json:"splits,omitempty"`
+}
+
+// TransactionFilter — условия отбора транзакций для списка и выгрузки; nil-поля не ограничивают
+type TransactionFilter struct {
+	IsIncome   *bool
+	CategoryID *int
+	From       *time.Time
+	To         *time.Time
+	Tags       []string
+	AllTags    bool // true — транзакция должна иметь все теги из Tags, иначе хотя бы один
+}
+
+// ExportedTransaction — транзакция с названием категории для выгрузки
+type ExportedTransaction struct {
+	Transaction
+	CategoryName string `json:"category_name"`
 }
 
+// TransactionUpdate описывает частичное изменение транзакции: nil-поля не меняются
+type TransactionUpdate struct {
+	IsIncome   *bool      `json:"is_income"`
+	Amount     *Money     `json:"amount"`
+	CategoryID *int       `json:"category_id"`
+	Note       *string    `json:"note"`
+	OccurredAt *DateTime  `json:"occurred_at"`
+	CreatedAt  *time.Time `json:"created_at"` // устаревший синоним occurred_at: created_at больше не меняется
+	Currency   *string    `json:"currency"`
+	AccountID  *int       `json:"account_id"` // 0 — отвязать от счёта
+	Tags       *[]string  `json:"tags"`       // заменяет все теги транзакции; [] — снять теги
+	Splits     *[]Split   `json:"splits"`     // заменяет разбивку; [] — убрать разбивку
+}
+
+// Summary содержит итоги, пересчитанные в базовую валюту пользователя
+// по курсу на дату каждой транзакции, и итоги в исходных валютах
 type Summary struct {
-	TotalIncome  float64 `json:"total_income"`
-	TotalExpense float64 `json:"total_expense"`
-	Balance      float64 `json:"balance"`
+	TotalIncome  Money             `json:"total_income"`
+	TotalExpense Money             `json:"total_expense"`
+	Balance      Money             `json:"balance"`
+	Currency     string            `json:"currency,omitempty"`
+	ByCurrency   []CurrencySummary `json:"by_currency,omitempty"`
+	ByTag        []TagSummary      `json:"by_tag,omitempty"`
+	MissingRates []string          `json:"missing_rates,omitempty"` // валюты без курса, не вошедшие в итоги
+}
+
+type CurrencySummary struct {
+	Currency     string `json:"currency"`
+	TotalIncome  Money  `json:"total_income"`
+	TotalExpense Money  `json:"total_expense"`
+	Balance      Money  `json:"balance"`
+}
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// IsValidCurrency проверяет, что code похож на код валюты ISO 4217 (три заглавные буквы)
+func IsValidCurrency(code string) bool {
+	return currencyPattern.MatchString(code)
+}
+
+// IsValidTimeZone проверяет, что name — имя часового пояса IANA, например "Europe/Berlin"
+func IsValidTimeZone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// ExchangeRate означает: 1 единица Base стоит Rate единиц Quote на дату Date
+type ExchangeRate struct {
+	Date  time.Time `json:"date"`
+	Base  string    `json:"base"`
+	Quote string    `json:"quote"`
+	Rate  float64   `json:"rate"`
+}
+
+const (
+	BudgetMonthly = "monthly"
+	BudgetCustom  = "custom"
+)
+
+// Budget — лимит расходов в базовой валюте пользователя на категорию
+// (или на все расходы, если CategoryID не задан) за месяц или за произвольный период
+type Budget struct {
+	ID         int       `json:"id"`
+	UserID     int       `json:"user_id"`
+	CategoryID *int      `json:"category_id,omitempty"`
+	Amount     Money     `json:"amount"`
+	Period     string    `json:"period"`
+	StartDate  Date      `json:"start_date"`
+	EndDate    *Date     `json:"end_date,omitempty"`
+	Rollover   bool      `json:"rollover"` // переносить неизрасходованный остаток на следующий месяц
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type BudgetUpdate struct {
+	Amount   *Money `json:"amount"`
+	EndDate  *Date  `json:"end_date"`
+	Rollover *bool  `json:"rollover"`
+}
+
+// BudgetStatus — состояние бюджета за текущий период
+type BudgetStatus struct {
+	Budget
+	PeriodStart Date    `json:"period_start"`
+	PeriodEnd   Date    `json:"period_end"`
+	CarriedOver Money   `json:"carried_over"`
+	Limit       Money   `json:"limit"` // amount + carried_over
+	Spent       Money   `json:"spent"`
+	Remaining   Money   `json:"remaining"`
+	Percentage  float64 `json:"percentage"`
+	Overspent   bool    `json:"overspent"`
 }
 
 type ErrorResponse struct {
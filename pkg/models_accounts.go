@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+const (
+	AccountCash    = "cash"
+	AccountBank    = "bank"
+	AccountCard    = "card"
+	AccountSavings = "savings"
+	AccountOther   = "other"
+)
+
+func IsValidAccountType(t string) bool {
+	switch t {
+	case AccountCash, AccountBank, AccountCard, AccountSavings, AccountOther:
+		return true
+	}
+	return false
+}
+
+// MaxBalance — наибольший по модулю начальный остаток, помещающийся в колонку NUMERIC(12,2)
+const MaxBalance Money = 9999999999_99
+
+// Account — счёт или кошелёк пользователя. Balance — начальный остаток плюс доходы
+// и минус расходы по транзакциям счёта, включая переводы.
+type Account struct {
+	ID             int       `json:"id"`
+	UserID         int       `json:"user_id"`
+	Name           string    `json:"name"`
+	Type           string    `json:"type"`
+	Currency       string    `json:"currency"`
+	OpeningBalance Money     `json:"opening_balance"`
+	Balance        Money     `json:"balance"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AccountUpdate — изменяемые поля счёта; валюта не меняется, чтобы не исказить остаток
+type AccountUpdate struct {
+	Name           *string `json:"name"`
+	Type           *string `json:"type"`
+	OpeningBalance *Money  `json:"opening_balance"`
+}
+
+// Transfer — перевод между счетами пользователя. Записывается как расход со счёта
+// FromAccountID (DebitID) и доход на счёт ToAccountID (CreditID), которые не входят
+// в сводку доходов и расходов. ToAmount отличается от Amount только при переводе
+// между счетами в разных валютах.
+type Transfer struct {
+	ID            int       `json:"id"`
+	UserID        int       `json:"user_id"`
+	FromAccountID int       `json:"from_account_id"`
+	ToAccountID   int       `json:"to_account_id"`
+	Amount        Money     `json:"amount"`
+	ToAmount      Money     `json:"to_amount"`
+	Note          string    `json:"note,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	DebitID       int       `json:"debit_transaction_id"`
+	CreditID      int       `json:"credit_transaction_id"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// MaxAttachmentSize ограничивает размер одного вложения (10 МБ)
+const MaxAttachmentSize = 10 << 20
+
+// AttachmentContentTypes — допустимые типы вложений. Тип определяется по содержимому
+// файла, а не по заголовку клиента.
+var AttachmentContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+// Attachment — файл (фото чека, гарантийный талон), прикреплённый к транзакции.
+// Содержимое лежит в хранилище файлов под ключом StorageKey.
+type Attachment struct {
+	ID            int       `json:"id"`
+	TransactionID int       `json:"transaction_id"`
+	UserID        int       `json:"user_id"`
+	FileName      string    `json:"file_name"`
+	ContentType   string    `json:"content_type"`
+	Size          int64     `json:"size"`
+	StorageKey    string    `json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+}
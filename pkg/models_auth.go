@@ -3,15 +3,27 @@ package models
 import "time"
 
 type User struct {
-	ID        int       `json:"id"`
-	Email     string    `json:"email"`
-	Password  string    `json:"-"`
-	CreatedAt time.Time `json:"created_at"`
+	ID               int       `json:"id"`
+	Email            string    `json:"email"`
+	Password         string    `json:"-"`
+	BaseCurrency     string    `json:"base_currency"`
+	TimeZone         string    `json:"time_zone"` // IANA; в нём считаются календарные дни фильтров и отчётов
+	EmailVerified    bool      `json:"email_verified"`
+	TwoFactorEnabled bool      `json:"two_factor_enabled"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// UserSettings описывает частичное изменение настроек пользователя
+type UserSettings struct {
+	BaseCurrency *string `json:"base_currency"`
+	TimeZone     *string `json:"time_zone"`
 }
 
 type RegisterRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email        string `json:"email"`
+	Password     string `json:"password"`
+	BaseCurrency string `json:"base_currency,omitempty"`
+	TimeZone     string `json:"time_zone,omitempty"`
 }
 
 type LoginRequest struct {
@@ -20,6 +32,172 @@ type LoginRequest struct {
 }
 
 type AuthResponse struct {
+	Token        string    `json:"token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token"`
+	User         User      `json:"user"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RefreshToken — запись о выданном refresh-токене. Токены одной сессии образуют
+// семейство: при каждом обновлении старый токен помечается использованным и
+// выдаётся новый с тем же FamilyID. Вместе с ним хранится jti access-токена,
+// выданного в паре, чтобы при отзыве сессии отозвать и его.
+type RefreshToken struct {
+	ID              int        `json:"id"`
+	UserID          int        `json:"user_id"`
+	FamilyID        string     `json:"family_id"`
+	TokenHash       string     `json:"-"`
+	AccessJTI       string     `json:"-"`
+	AccessExpiresAt time.Time  `json:"-"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	UsedAt          *time.Time `json:"used_at,omitempty"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+const (
+	TokenVerifyEmail   = "verify_email"
+	TokenResetPassword = "reset_password"
+)
+
+// UserToken — одноразовый токен из письма пользователю: подтверждение почты или
+// сброс пароля. Хранится только хеш; новый токен того же назначения отменяет прежние.
+type UserToken struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	Purpose   string     `json:"purpose"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type VerifyEmailRequest struct {
 	Token string `json:"token"`
-	User  User   `json:"user"`
+}
+
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// TwoFactor — состояние TOTP пользователя. Secret задан после настройки, но коды
+// требуются при входе только после подтверждения (Enabled).
+type TwoFactor struct {
+	Secret  string
+	Enabled bool
+}
+
+// TwoFactorSetup возвращается при настройке: секрет для ручного ввода и otpauth://
+// URI для приложения-аутентификатора; QR-код с тем же URI отдаёт GET /auth/2fa/qr
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+}
+
+// RecoveryCodes показываются один раз: в БД хранятся только хеши
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// LoginChallenge — ответ на вход с паролем, когда включена 2FA: challenge_token
+// обменивается на сессию только через /auth/2fa/verify
+type LoginChallenge struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// TwoFactorCodeRequest — код из приложения или код восстановления
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// Причины в журнале попыток входа
+const (
+	LoginSucceeded          = "success"
+	LoginInvalidCredentials = "invalid_credentials"
+	LoginInvalidCode        = "invalid_2fa_code"
+	LoginEmailNotVerified   = "email_not_verified"
+	LoginThrottled          = "throttled"
+	// LoginMailRequested — запрос письма сброса пароля или подтверждения адреса;
+	// такие записи только ограничивают частоту писем и не видны в журнале пользователя
+	LoginMailRequested = "mail_requested"
+)
+
+// LoginAttempt — запись журнала попыток входа. UserID пуст для неизвестных адресов.
+type LoginAttempt struct {
+	ID        int64     `json:"id"`
+	UserID    *int      `json:"-"`
+	Email     string    `json:"-"`
+	IP        string    `json:"ip"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoginFailures — неудачные попытки входа (неверный пароль или код 2FA) за окно
+// наблюдения: для адреса — после последнего успешного входа, для IP — все
+type LoginFailures struct {
+	Account     int
+	AccountLast time.Time
+	IP          int
+	IPLast      time.Time
+}
+
+// Области доступа API-ключей имеют вид "<ресурс>:read" или "<ресурс>:write";
+// write включает read
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// APIKeyResources — ресурсы, на которые выдаются области доступа. Отчёты только
+// читаются, поэтому для них есть лишь reports:read.
+var APIKeyResources = []string{
+	"transactions", "categories", "accounts", "budgets", "recurring", "rules", "tags", "reports", "profile",
+}
+
+// APIKey — персональный ключ для скриптов и интеграций. Сам ключ показывается
+// один раз при создании, хранится только хеш; Prefix помогает узнать ключ в списке.
+type APIKey struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"-"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type APIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreatedAPIKey — ответ на создание ключа, единственный раз, когда виден Key
+type CreatedAPIKey struct {
+	Key string `json:"key"`
+	APIKey
 }
@@ -0,0 +1,51 @@
+package models
+
+const (
+	// SignNegativeExpense — отрицательная сумма означает расход (выписки по дебетовым картам)
+	SignNegativeExpense = "negative_expense"
+	// SignNegativeIncome — отрицательная сумма означает доход (выписки по кредитным картам)
+	SignNegativeIncome = "negative_income"
+)
+
+const (
+	ImportRowNew       = "new"
+	ImportRowDuplicate = "duplicate"
+	ImportRowInvalid   = "invalid"
+)
+
+// ImportMapping сопоставляет поля транзакции с колонками CSV по заголовку
+type ImportMapping struct {
+	Date         string `json:"date"`
+	DateFormat   string `json:"date_format"` // формат Go, по умолчанию 2006-01-02
+	Amount       string `json:"amount"`
+	Sign         string `json:"sign"`          // negative_expense (по умолчанию) или negative_income
+	DecimalComma bool   `json:"decimal_comma"` // "1 234,56" вместо "1,234.56"
+	Note         string `json:"note"`
+	Category     string `json:"category"`
+	Currency     string `json:"currency"`  // необязательно; без колонки — базовая валюта
+	Delimiter    string `json:"delimiter"` // по умолчанию ","
+}
+
+type ImportOptions struct {
+	DryRun           bool
+	CreateCategories bool
+}
+
+// ImportRow — строка файла после разбора и проверки
+type ImportRow struct {
+	Line         int         `json:"line"`
+	Status       string      `json:"status"`
+	Error        string      `json:"error,omitempty"`
+	CategoryName string      `json:"category,omitempty"`
+	Transaction  Transaction `json:"transaction"`
+}
+
+type ImportResult struct {
+	DryRun            bool        `json:"dry_run"`
+	Total             int         `json:"total"`
+	Imported          int         `json:"imported"`
+	Duplicates        int         `json:"duplicates"`
+	Invalid           int         `json:"invalid"`
+	CreatedCategories []string    `json:"created_categories,omitempty"`
+	Rows              []ImportRow `json:"rows"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import "time"
+
+const (
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	FrequencyYearly  = "yearly"
+)
+
+// RecurringRule — шаблон регулярной транзакции (аренда, зарплата, подписки).
+// Планировщик создаёт по нему транзакции на каждую наступившую дату повторения.
+type RecurringRule struct {
+	ID             int       `json:"id"`
+	UserID         int       `json:"user_id"`
+	IsIncome       bool      `json:"is_income"`
+	Amount         Money     `json:"amount"`
+	Currency       string    `json:"currency"`
+	CategoryID     int       `json:"category_id"`
+	Note           string    `json:"note,omitempty"`
+	Frequency      string    `json:"frequency"`
+	Interval       int       `json:"interval"`               // каждые N дней/недель/месяцев/лет
+	DayOfMonth     *int      `json:"day_of_month,omitempty"` // для monthly/yearly; в коротких месяцах — последний день
+	StartDate      Date      `json:"start_date"`
+	EndDate        *Date     `json:"end_date,omitempty"`
+	MaxOccurrences *int      `json:"max_occurrences,omitempty"`
+	Occurrences    int       `json:"occurrences"`         // сколько повторений уже проведено
+	NextDate       *Date     `json:"next_date,omitempty"` // nil — правило исчерпано
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type RecurringRuleUpdate struct {
+	Amount         *Money  `json:"amount"`
+	CategoryID     *int    `json:"category_id"`
+	Note           *string `json:"note"`
+	EndDate        *Date   `json:"end_date"`
+	MaxOccurrences *int    `json:"max_occurrences"`
+}
+
+// Normalize заполняет значения по умолчанию и сдвигает StartDate на первое
+// повторение: при day_of_month=5 и старте 20-го первое повторение — 5-е следующего месяца
+func (r *RecurringRule) Normalize() {
+	if r.Interval < 1 {
+		r.Interval = 1
+	}
+	if first := r.OccurrenceDate(0); first.Before(r.StartDate.Time) {
+		r.StartDate = r.OccurrenceDate(1)
+	}
+	r.NextDate = r.NextOccurrence()
+}
+
+// OccurrenceDate возвращает дату n-го (с нуля) повторения. Дата считается от
+// StartDate, а не от предыдущего повторения, поэтому 31-е число не «сползает»
+// на 28-е после февраля.
+func (r RecurringRule) OccurrenceDate(n int) Date {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	start := r.StartDate.Time
+
+	switch r.Frequency {
+	case FrequencyDaily:
+		return NewDate(start.AddDate(0, 0, n*interval))
+	case FrequencyWeekly:
+		return NewDate(start.AddDate(0, 0, 7*n*interval))
+	case FrequencyYearly:
+		return r.dayInMonth(start.Year()+n*interval, start.Month())
+	default:
+		months := int(start.Month()) - 1 + n*interval
+		return r.dayInMonth(start.Year()+months/12, time.Month(months%12+1))
+	}
+}
+
+func (r RecurringRule) dayInMonth(year int, month time.Month) Date {
+	day := r.StartDate.Day()
+	if r.DayOfMonth != nil {
+		day = *r.DayOfMonth
+	}
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return NewDate(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// NextOccurrence возвращает дату следующего непроведённого повторения
+// или nil, если правило исчерпано по EndDate или MaxOccurrences
+func (r RecurringRule) NextOccurrence() *Date {
+	if r.MaxOccurrences != nil && r.Occurrences >= *r.MaxOccurrences {
+		return nil
+	}
+	next := r.OccurrenceDate(r.Occurrences)
+	if r.EndDate != nil && next.After(r.EndDate.Time) {
+		return nil
+	}
+	return &next
+}
@@ -0,0 +1,157 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func date(s string) models.Date {
+	t, _ := time.Parse("2006-01-02", s)
+	return models.NewDate(t)
+}
+
+func intPtr(v int) *int { return &v }
+
+func datePtr(s string) *models.Date {
+	d := date(s)
+	return &d
+}
+
+// schedule проводит правило так же, как MaterializeRecurring, до исчерпания
+// или limit повторений и возвращает даты проведённых повторений
+func schedule(rule models.RecurringRule, limit int) []string {
+	rule.Normalize()
+	var dates []string
+	for rule.NextDate != nil && len(dates) < limit {
+		dates = append(dates, rule.NextDate.String())
+		rule.Occurrences++
+		rule.NextDate = rule.NextOccurrence()
+	}
+	return dates
+}
+
+func TestRecurringRule_Schedule(t *testing.T) {
+	tests := []struct {
+		name string
+		rule models.RecurringRule
+		want []string
+	}{
+		{
+			name: "31st rolls into february and back",
+			rule: models.RecurringRule{Frequency: models.FrequencyMonthly, StartDate: date("2024-01-31")},
+			want: []string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30", "2024-05-31"},
+		},
+		{
+			name: "31st in a non-leap february",
+			rule: models.RecurringRule{Frequency: models.FrequencyMonthly, StartDate: date("2023-01-31")},
+			want: []string{"2023-01-31", "2023-02-28", "2023-03-31"},
+		},
+		{
+			name: "yearly on february 29",
+			rule: models.RecurringRule{Frequency: models.FrequencyYearly, StartDate: date("2024-02-29")},
+			want: []string{"2024-02-29", "2025-02-28", "2026-02-28", "2027-02-28", "2028-02-29"},
+		},
+		{
+			name: "day of month after start day moves start to next month",
+			rule: models.RecurringRule{Frequency: models.FrequencyMonthly, DayOfMonth: intPtr(5), StartDate: date("2024-01-20")},
+			want: []string{"2024-02-05", "2024-03-05", "2024-04-05"},
+		},
+		{
+			name: "day of month 31 clamps in short months",
+			rule: models.RecurringRule{Frequency: models.FrequencyMonthly, DayOfMonth: intPtr(31), StartDate: date("2024-04-10")},
+			want: []string{"2024-04-30", "2024-05-31", "2024-06-30"},
+		},
+		{
+			name: "every 3 months across a year",
+			rule: models.RecurringRule{Frequency: models.FrequencyMonthly, Interval: 3, StartDate: date("2024-11-30")},
+			want: []string{"2024-11-30", "2025-02-28", "2025-05-30", "2025-08-30"},
+		},
+		{
+			name: "every 2 weeks",
+			rule: models.RecurringRule{Frequency: models.FrequencyWeekly, Interval: 2, StartDate: date("2024-12-23")},
+			want: []string{"2024-12-23", "2025-01-06", "2025-01-20"},
+		},
+		{
+			name: "every 10 days",
+			rule: models.RecurringRule{Frequency: models.FrequencyDaily, Interval: 10, StartDate: date("2024-02-25")},
+			want: []string{"2024-02-25", "2024-03-06", "2024-03-16"},
+		},
+		{
+			name: "every 2 years from february 29",
+			rule: models.RecurringRule{Frequency: models.FrequencyYearly, Interval: 2, StartDate: date("2024-02-29")},
+			want: []string{"2024-02-29", "2026-02-28", "2028-02-29"},
+		},
+		{
+			name: "exhausted by max occurrences",
+			rule: models.RecurringRule{Frequency: models.FrequencyMonthly, StartDate: date("2024-01-15"), MaxOccurrences: intPtr(3)},
+			want: []string{"2024-01-15", "2024-02-15", "2024-03-15"},
+		},
+		{
+			name: "exhausted by end date",
+			rule: models.RecurringRule{Frequency: models.FrequencyMonthly, StartDate: date("2024-01-31"), EndDate: datePtr("2024-04-29")},
+			want: []string{"2024-01-31", "2024-02-29", "2024-03-31"},
+		},
+		{
+			name: "end date on the last occurrence is inclusive",
+			rule: models.RecurringRule{Frequency: models.FrequencyWeekly, StartDate: date("2024-01-01"), EndDate: datePtr("2024-01-15")},
+			want: []string{"2024-01-01", "2024-01-08", "2024-01-15"},
+		},
+		{
+			name: "end date before first occurrence",
+			rule: models.RecurringRule{Frequency: models.FrequencyMonthly, DayOfMonth: intPtr(5), StartDate: date("2024-01-20"), EndDate: datePtr("2024-01-31")},
+			want: nil,
+		},
+		{
+			name: "zero max occurrences",
+			rule: models.RecurringRule{Frequency: models.FrequencyDaily, StartDate: date("2024-01-01"), MaxOccurrences: intPtr(0)},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit := len(tt.want)
+			if limit == 0 {
+				limit = 1
+			}
+			assert.Equal(t, tt.want, schedule(tt.rule, limit))
+		})
+	}
+}
+
+func TestRecurringRule_Normalize(t *testing.T) {
+	rule := models.RecurringRule{Frequency: models.FrequencyMonthly, DayOfMonth: intPtr(5), StartDate: date("2024-12-20")}
+	rule.Normalize()
+	assert.Equal(t, 1, rule.Interval)
+	assert.Equal(t, "2025-01-05", rule.StartDate.String())
+	if assert.NotNil(t, rule.NextDate) {
+		assert.Equal(t, "2025-01-05", rule.NextDate.String())
+	}
+
+	// Старт в день повторения не сдвигается
+	rule = models.RecurringRule{Frequency: models.FrequencyMonthly, DayOfMonth: intPtr(5), StartDate: date("2024-12-05"), Interval: 2}
+	rule.Normalize()
+	assert.Equal(t, 2, rule.Interval)
+	assert.Equal(t, "2024-12-05", rule.StartDate.String())
+}
+
+func TestRecurringRule_NextOccurrence(t *testing.T) {
+	rule := models.RecurringRule{Frequency: models.FrequencyMonthly, StartDate: date("2024-01-31"), MaxOccurrences: intPtr(12)}
+
+	// Дата считается от StartDate: после февраля снова 31-е
+	rule.Occurrences = 2
+	assert.Equal(t, "2024-03-31", rule.NextOccurrence().String())
+	rule.Occurrences = 11
+	assert.Equal(t, "2024-12-31", rule.NextOccurrence().String())
+	rule.Occurrences = 12
+	assert.Nil(t, rule.NextOccurrence())
+
+	// Правило, исчерпанное по дате, остаётся исчерпанным после продления лимита
+	rule.MaxOccurrences = nil
+	rule.EndDate = datePtr("2024-12-30")
+	rule.Occurrences = 11
+	assert.Nil(t, rule.NextOccurrence())
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Интервалы группировки временного ряда; значения совпадают с полями date_trunc в Postgres
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+	IntervalYear  = "year"
+)
+
+// MaxTimeSeriesPoints ограничивает длину ряда, чтобы запрос по дням за десятилетия
+// не порождал огромный ответ
+const MaxTimeSeriesPoints = 1000
+
+func IsValidInterval(interval string) bool {
+	switch interval {
+	case IntervalDay, IntervalWeek, IntervalMonth, IntervalYear:
+		return true
+	}
+	return false
+}
+
+// TimeSeriesLength возвращает число периодов interval между from и to включительно
+func TimeSeriesLength(interval string, from, to time.Time) int {
+	switch interval {
+	case IntervalDay:
+		return int(to.Sub(from).Hours()/24) + 1
+	case IntervalWeek:
+		// Недели начинаются с понедельника, как у date_trunc('week', ...)
+		from = from.AddDate(0, 0, -(int(from.Weekday())+6)%7)
+		return int(to.Sub(from).Hours()/24)/7 + 1
+	case IntervalMonth:
+		return (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	case IntervalYear:
+		return to.Year() - from.Year() + 1
+	}
+	return 0
+}
+
+// CategoryReport — доходы и расходы по категориям в базовой валюте пользователя.
+// Итоги категории включают её подкатегории, доли считаются от итогов отчёта.
+type CategoryReport struct {
+	Currency     string              `json:"currency"`
+	TotalIncome  Money               `json:"total_income"`
+	TotalExpense Money               `json:"total_expense"`
+	Categories   []CategoryReportRow `json:"categories"`
+	MissingRates []string            `json:"missing_rates,omitempty"`
+}
+
+type CategoryReportRow struct {
+	CategoryID   int     `json:"category_id"`
+	Name         string  `json:"name"`
+	ParentID     *int    `json:"parent_id,omitempty"`
+	Income       Money   `json:"income"`
+	Expense      Money   `json:"expense"`
+	IncomeShare  float64 `json:"income_share"`  // процент от total_income
+	ExpenseShare float64 `json:"expense_share"` // процент от total_expense
+}
+
+// TimeSeries — доходы и расходы по периодам; периоды без транзакций заполнены нулями
+type TimeSeries struct {
+	Currency     string            `json:"currency"`
+	Interval     string            `json:"interval"`
+	Points       []TimeSeriesPoint `json:"points"`
+	MissingRates []string          `json:"missing_rates,omitempty"`
+}
+
+type TimeSeriesPoint struct {
+	Period  Date  `json:"period"` // начало периода; неделя начинается с понедельника
+	Income  Money `json:"income"`
+	Expense Money `json:"expense"`
+	Balance Money `json:"balance"`
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxRuleNameLength    = 100
+	MaxRulePatternLength = 500
+)
+
+// CategoryRule назначает категорию и/или теги транзакциям, подходящим под все заданные
+// условия. Правила проверяются по возрастанию Priority (при равенстве — по ID),
+// применяется первое подошедшее.
+type CategoryRule struct {
+	ID           int       `json:"id"`
+	UserID       int       `json:"user_id"`
+	Name         string    `json:"name"`
+	Priority     int       `json:"priority"`
+	NoteContains string    `json:"note_contains,omitempty"` // без учёта регистра
+	NoteRegex    string    `json:"note_regex,omitempty"`    // синтаксис Go RE2, (?i) — без учёта регистра
+	MinAmount    *Money    `json:"min_amount,omitempty"`
+	MaxAmount    *Money    `json:"max_amount,omitempty"`
+	IsIncome     *bool     `json:"is_income,omitempty"`
+	AccountID    *int      `json:"account_id,omitempty"`
+	CategoryID   *int      `json:"category_id,omitempty"`
+	Tags         []string  `json:"tags,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	noteRegex *regexp.Regexp
+}
+
+// Normalize приводит поля правила к каноническому виду и проверяет их
+func (r *CategoryRule) Normalize() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return errors.New("rule name cannot be empty")
+	}
+	if utf8.RuneCountInString(r.Name) > MaxRuleNameLength {
+		return fmt.Errorf("rule name must not exceed %d characters", MaxRuleNameLength)
+	}
+
+	r.NoteContains = strings.TrimSpace(r.NoteContains)
+	if utf8.RuneCountInString(r.NoteContains) > MaxRulePatternLength || len(r.NoteRegex) > MaxRulePatternLength {
+		return fmt.Errorf("note patterns must not exceed %d characters", MaxRulePatternLength)
+	}
+	if r.NoteRegex != "" {
+		re, err := regexp.Compile(r.NoteRegex)
+		if err != nil {
+			return fmt.Errorf("invalid note_regex: %v", err)
+		}
+		r.noteRegex = re
+	}
+
+	if r.MinAmount != nil && *r.MinAmount < 0 || r.MaxAmount != nil && *r.MaxAmount < 0 {
+		return errors.New("amount bounds must not be negative")
+	}
+	if r.MinAmount != nil && *r.MinAmount > MaxAmount || r.MaxAmount != nil && *r.MaxAmount > MaxAmount {
+		return fmt.Errorf("amount bounds must not exceed %s", MaxAmount)
+	}
+	if r.MinAmount != nil && r.MaxAmount != nil && *r.MinAmount > *r.MaxAmount {
+		return errors.New("min_amount must not exceed max_amount")
+	}
+	if r.CategoryID != nil && *r.CategoryID <= 0 {
+		r.CategoryID = nil
+	}
+	if r.AccountID != nil && *r.AccountID <= 0 {
+		r.AccountID = nil
+	}
+
+	if r.NoteContains == "" && r.NoteRegex == "" && r.MinAmount == nil && r.MaxAmount == nil &&
+		r.IsIncome == nil && r.AccountID == nil {
+		return errors.New("rule must have at least one condition")
+	}
+
+	tags, err := NormalizeTags(r.Tags)
+	if err != nil {
+		return err
+	}
+	r.Tags = tags
+	if r.CategoryID == nil && len(r.Tags) == 0 {
+		return errors.New("rule must assign a category or tags")
+	}
+	return nil
+}
+
+// Matches проверяет, подходит ли транзакция под все условия правила
+func (r *CategoryRule) Matches(t *Transaction) bool {
+	if r.IsIncome != nil && *r.IsIncome != t.IsIncome {
+		return false
+	}
+	if r.AccountID != nil && (t.AccountID == nil || *t.AccountID != *r.AccountID) {
+		return false
+	}
+	if r.MinAmount != nil && t.Amount < *r.MinAmount || r.MaxAmount != nil && t.Amount > *r.MaxAmount {
+		return false
+	}
+	if r.NoteContains != "" && !strings.Contains(strings.ToLower(t.Note), strings.ToLower(r.NoteContains)) {
+		return false
+	}
+	if r.NoteRegex != "" {
+		if r.noteRegex == nil {
+			re, err := regexp.Compile(r.NoteRegex)
+			if err != nil {
+				return false
+			}
+			r.noteRegex = re
+		}
+		if !r.noteRegex.MatchString(t.Note) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchRule возвращает первое подходящее правило; rules должны быть упорядочены по приоритету
+func MatchRule(rules []CategoryRule, t *Transaction) *CategoryRule {
+	for i := range rules {
+		if rules[i].Matches(t) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// ApplyRule назначает транзакции категорию правила (если задана) и добавляет его теги
+func ApplyRule(rule *CategoryRule, t *Transaction) error {
+	if rule.CategoryID != nil {
+		t.CategoryID = *rule.CategoryID
+	}
+	if len(rule.Tags) == 0 {
+		return nil
+	}
+	tags, err := NormalizeTags(append(append([]string(nil), t.Tags...), rule.Tags...))
+	if err != nil {
+		return err
+	}
+	t.Tags = tags
+	return nil
+}
+
+// RuleMatch — изменение, которое правило вносит в существующую транзакцию
+type RuleMatch struct {
+	TransactionID int      `json:"transaction_id"`
+	RuleID        int      `json:"rule_id"`
+	Note          string   `json:"note,omitempty"`
+	OldCategoryID int      `json:"old_category_id"`
+	CategoryID    int      `json:"category_id"`
+	AddedTags     []string `json:"added_tags,omitempty"`
+	Tags          []string `json:"-"` // итоговый набор тегов
+}
+
+type RuleApplyResult struct {
+	DryRun  bool        `json:"dry_run"`
+	Scanned int         `json:"scanned"`
+	Changes []RuleMatch `json:"changes"`
+	Applied int         `json:"applied"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxSplits ограничивает число строк разбивки одной транзакции
+const MaxSplits = 50
+
+// Split — часть транзакции, отнесённая к своей категории. Если у транзакции есть
+// разбивка, в отчётах и фильтрах по категориям учитываются строки разбивки,
+// а не категория самой транзакции.
+type Split struct {
+	CategoryID int    `json:"category_id"`
+	Amount     Money  `json:"amount"`
+	Note       string `json:"note,omitempty"`
+}
+
+// ValidateSplits проверяет строки разбивки и то, что в сумме они равны сумме транзакции
+func ValidateSplits(amount Money, splits []Split) error {
+	if err := ValidateSplitLines(splits); err != nil {
+		return err
+	}
+	var total Money
+	for _, s := range splits {
+		total += s.Amount
+	}
+	if total != amount {
+		return fmt.Errorf("split amounts add up to %s, expected %s", total, amount)
+	}
+	return nil
+}
+
+// ValidateSplitLines проверяет строки разбивки без сверки с суммой транзакции:
+// не меньше двух строк, у каждой категория и положительная сумма
+func ValidateSplitLines(splits []Split) error {
+	if len(splits) < 2 {
+		return errors.New("splits must contain at least two lines")
+	}
+	if len(splits) > MaxSplits {
+		return fmt.Errorf("splits must contain at most %d lines", MaxSplits)
+	}
+	for _, s := range splits {
+		if s.CategoryID <= 0 {
+			return errors.New("category_id is required for every split line")
+		}
+		if s.Amount <= 0 {
+			return errors.New("split amount must be greater than 0")
+		}
+	}
+	return nil
+}
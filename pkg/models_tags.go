@@ -0,0 +1,54 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	MaxTagLength          = 50
+	MaxTagsPerTransaction = 20
+)
+
+type Tag struct {
+	ID               int       `json:"id"`
+	UserID           int       `json:"user_id"`
+	Name             string    `json:"name"`
+	TransactionCount int       `json:"transaction_count"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// TagSummary — доходы и расходы по тегу в базовой валюте пользователя. Транзакция
+// с несколькими тегами учитывается в каждом из них, поэтому суммы по тегам
+// не складываются в общий итог.
+type TagSummary struct {
+	Tag          string `json:"tag"`
+	TotalIncome  Money  `json:"total_income"`
+	TotalExpense Money  `json:"total_expense"`
+}
+
+// NormalizeTags приводит теги к нижнему регистру, обрезает пробелы и убирает повторы,
+// сохраняя порядок
+func NormalizeTags(tags []string) ([]string, error) {
+	if len(tags) > MaxTagsPerTransaction {
+		return nil, fmt.Errorf("a transaction can have at most %d tags", MaxTagsPerTransaction)
+	}
+	normalized := make([]string, 0, len(tags))
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			return nil, fmt.Errorf("tag cannot be empty")
+		}
+		if len([]rune(tag)) > MaxTagLength {
+			return nil, fmt.Errorf("tag must not exceed %d characters", MaxTagLength)
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized, nil
+}
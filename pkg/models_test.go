@@ -0,0 +1,51 @@
+package models_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	valid := map[string]models.Money{
+		"25":      2500,
+		"25.5":    2550,
+		"25.50":   2550,
+		" 0.01 ":  1,
+		"-120.50": -12050,
+		"1.500":   150,
+	}
+	for s, want := range valid {
+		got, err := models.ParseMoney(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, want, got, s)
+	}
+
+	// Дроби, экспонента и прочие формы, которые понимает big.Rat, не принимаются
+	for _, s := range []string{"", "1/100", "1e3", "2.5E1", "+5", ".5", "5.", "0x10", "1_000", "--1", "abc"} {
+		_, err := models.ParseMoney(s)
+		assert.ErrorIs(t, err, models.ErrAmountFormat, s)
+	}
+
+	_, err := models.ParseMoney("1.005")
+	assert.ErrorIs(t, err, models.ErrAmountPrecision)
+	_, err = models.ParseMoney("92233720368547758.08")
+	assert.ErrorIs(t, err, models.ErrAmountRange)
+
+	var m models.Money
+	assert.NoError(t, json.Unmarshal([]byte(`"12.30"`), &m))
+	assert.Equal(t, models.Money(1230), m)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`1e3`), &m), models.ErrAmountFormat)
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "0.00", models.Money(0).String())
+	assert.Equal(t, "0.05", models.Money(5).String())
+	assert.Equal(t, "-0.05", models.Money(-5).String())
+	assert.Equal(t, "-120.50", models.Money(-12050).String())
+	assert.Equal(t, "92233720368547758.07", models.Money(math.MaxInt64).String())
+	assert.Equal(t, "-92233720368547758.08", models.Money(math.MinInt64).String())
+}
@@ -0,0 +1,98 @@
+package rates
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+// FileProvider читает курсы из локального файла CSV или JSON
+type FileProvider struct {
+	Path string
+}
+
+func (p FileProvider) Fetch(ctx context.Context, date time.Time) ([]models.ExchangeRate, error) {
+	return LoadFile(p.Path)
+}
+
+// LoadFile загружает курсы из файла; формат определяется по расширению (.csv или .json)
+func LoadFile(path string) ([]models.ExchangeRate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return LoadCSV(f)
+	case ".json":
+		var rates []models.ExchangeRate
+		if err := json.NewDecoder(f).Decode(&rates); err != nil {
+			return nil, fmt.Errorf("invalid rates file %s: %v", path, err)
+		}
+		for i := range rates {
+			if err := validateRate(&rates[i]); err != nil {
+				return nil, fmt.Errorf("invalid rate #%d in %s: %v", i+1, path, err)
+			}
+		}
+		return rates, nil
+	default:
+		return nil, fmt.Errorf("unsupported rates file format: %s", path)
+	}
+}
+
+// LoadCSV читает курсы в формате "date,base,quote,rate" с заголовком в первой строке,
+// например: 2024-01-02,EUR,PLN,4.3480
+func LoadCSV(r io.Reader) ([]models.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid rates csv: %v", err)
+	}
+	if len(records) > 0 && strings.EqualFold(records[0][0], "date") {
+		records = records[1:]
+	}
+
+	rates := make([]models.ExchangeRate, 0, len(records))
+	for i, rec := range records {
+		date, err := time.Parse("2006-01-02", rec[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid date on line %d: %v", i+2, err)
+		}
+		value, err := strconv.ParseFloat(rec[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate on line %d: %v", i+2, err)
+		}
+		rate := models.ExchangeRate{Date: date, Base: rec[1], Quote: rec[2], Rate: value}
+		if err := validateRate(&rate); err != nil {
+			return nil, fmt.Errorf("invalid rate on line %d: %v", i+2, err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+func validateRate(r *models.ExchangeRate) error {
+	r.Base = strings.ToUpper(strings.TrimSpace(r.Base))
+	r.Quote = strings.ToUpper(strings.TrimSpace(r.Quote))
+	if !models.IsValidCurrency(r.Base) || !models.IsValidCurrency(r.Quote) {
+		return fmt.Errorf("invalid currency pair %s/%s", r.Base, r.Quote)
+	}
+	if r.Rate <= 0 {
+		return fmt.Errorf("rate must be greater than 0")
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+// HTTPProvider получает курсы из API в формате Frankfurter/ECB:
+// GET {BaseURL}/{YYYY-MM-DD}?from={Base} -> {"base": "EUR", "date": "...", "rates": {"USD": 1.09}}
+type HTTPProvider struct {
+	BaseURL string
+	Base    string
+	Client  *http.Client
+}
+
+func NewHTTPProvider(baseURL, base string) *HTTPProvider {
+	return &HTTPProvider{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Base:    strings.ToUpper(base),
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type httpRatesResponse struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+func (p *HTTPProvider) Fetch(ctx context.Context, date time.Time) ([]models.ExchangeRate, error) {
+	url := fmt.Sprintf("%s/%s?from=%s", p.BaseURL, date.Format("2006-01-02"), p.Base)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rates provider returned status %d", resp.StatusCode)
+	}
+
+	var body httpRatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid rates provider response: %v", err)
+	}
+
+	// Провайдер может вернуть курс за ближайший предыдущий рабочий день
+	rateDate, err := time.Parse("2006-01-02", body.Date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date in rates provider response: %v", err)
+	}
+
+	rates := make([]models.ExchangeRate, 0, len(body.Rates))
+	for quote, value := range body.Rates {
+		rate := models.ExchangeRate{Date: rateDate, Base: body.Base, Quote: quote, Rate: value}
+		if err := validateRate(&rate); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
@@ -0,0 +1,61 @@
+package rates
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	models "github.com/ViktorOHJ/expense-tracker/pkg"
+)
+
+// Provider возвращает курсы валют на указанную дату. Реализации: HTTPProvider
+// для внешнего API и FileProvider/StaticProvider для локальной работы и тестов.
+type Provider interface {
+	Fetch(ctx context.Context, date time.Time) ([]models.ExchangeRate, error)
+}
+
+// Store — хранилище курсов, его реализует db.DB
+type Store interface {
+	SaveExchangeRates(context.Context, []models.ExchangeRate) (int, error)
+}
+
+// Sync загружает курсы на дату из провайдера и сохраняет их
+func Sync(ctx context.Context, provider Provider, store Store, date time.Time) error {
+	rates, err := provider.Fetch(ctx, date)
+	if err != nil {
+		return fmt.Errorf("failed to fetch exchange rates: %v", err)
+	}
+	saved, err := store.SaveExchangeRates(ctx, rates)
+	if err != nil {
+		return err
+	}
+	log.Printf("Exchange rates synced: %d rates for %s", saved, date.Format("2006-01-02"))
+	return nil
+}
+
+// Run синхронизирует курсы сразу и затем с заданным интервалом, пока не отменён ctx
+func Run(ctx context.Context, provider Provider, store Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := Sync(ctx, provider, store, time.Now().UTC()); err != nil {
+			log.Printf("exchange rate sync failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// StaticProvider отдаёт фиксированный набор курсов независимо от даты
+type StaticProvider struct {
+	Rates []models.ExchangeRate
+}
+
+func (p StaticProvider) Fetch(ctx context.Context, date time.Time) ([]models.ExchangeRate, error) {
+	return p.Rates, nil
+}
@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Store — хранилище регулярных правил, его реализует db.DB
+type Store interface {
+	MaterializeRecurring(context.Context, time.Time) (int, error)
+}
+
+// Tick проводит все повторения регулярных транзакций, наступившие к моменту now
+// по календарю часового пояса каждого пользователя
+func Tick(ctx context.Context, store Store, now time.Time) error {
+	created, err := store.MaterializeRecurring(ctx, now)
+	if err != nil {
+		return err
+	}
+	if created > 0 {
+		log.Printf("Recurring transactions posted: %d", created)
+	}
+	return nil
+}
+
+// Run проводит повторения сразу и затем с заданным интервалом, пока не отменён ctx.
+// Первый запуск догоняет даты, пропущенные, пока приложение было остановлено.
+func Run(ctx context.Context, store Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := Tick(ctx, store, time.Now()); err != nil {
+			log.Printf("recurring transactions failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ViktorOHJ/expense-tracker/pkg/scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	calls []time.Time
+	err   error
+}
+
+func (s *fakeStore) MaterializeRecurring(_ context.Context, today time.Time) (int, error) {
+	s.calls = append(s.calls, today)
+	return len(s.calls), s.err
+}
+
+func TestTick(t *testing.T) {
+	store := &fakeStore{}
+	now := time.Date(2024, 3, 1, 5, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+
+	// Передаётся сам момент: дату в поясе каждого пользователя определяет хранилище
+	assert.NoError(t, scheduler.Tick(context.Background(), store, now))
+	if assert.Len(t, store.calls, 1) {
+		assert.True(t, store.calls[0].Equal(now))
+	}
+
+	store.err = errors.New("database is down")
+	assert.ErrorIs(t, scheduler.Tick(context.Background(), store, time.Now()), store.err)
+}
+
+func TestRun(t *testing.T) {
+	store := &fakeStore{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Первый проход выполняется сразу, даже если ctx уже отменён
+	scheduler.Run(ctx, store, time.Hour)
+	assert.Len(t, store.calls, 1)
+}
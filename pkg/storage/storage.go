@@ -0,0 +1,19 @@
+// Package storage хранит файлы вложений вне базы данных: в локальной папке
+// или в S3-совместимом хранилище (AWS S3, MinIO)
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore — хранилище файлов по ключу. Ключи формирует приложение,
+// они состоят из латиницы, цифр и "/".
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
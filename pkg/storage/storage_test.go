@@ -0,0 +1,62 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ViktorOHJ/expense-tracker/pkg/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// testBlobStore проверяет запись, чтение и удаление файла; общий для всех реализаций
+func testBlobStore(t *testing.T, store storage.BlobStore) {
+	ctx := context.Background()
+	key := "attachments/1/2/storage-test"
+	content := "%PDF-1.7 receipt"
+
+	err := store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "application/pdf")
+	assert.NoError(t, err)
+
+	r, err := store.Get(ctx, key)
+	if assert.NoError(t, err) {
+		data, err := io.ReadAll(r)
+		r.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+
+	assert.NoError(t, store.Delete(ctx, key))
+	_, err = store.Get(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Повторное удаление не ошибка
+	assert.NoError(t, store.Delete(ctx, key))
+}
+
+func TestLocalStore(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	testBlobStore(t, store)
+
+	err = store.Put(context.Background(), "../escape", strings.NewReader("x"), 1, "text/plain")
+	assert.Error(t, err)
+}
+
+// TestS3Store запускается против MinIO или другого S3-совместимого хранилища, например:
+//
+//	docker run -p 9000:9000 minio/minio server /data
+//	S3_TEST_ENDPOINT=http://localhost:9000 S3_TEST_BUCKET=test S3_TEST_ACCESS_KEY=minioadmin S3_TEST_SECRET_KEY=minioadmin go test ./pkg/storage
+//
+// Бакет должен существовать.
+func TestS3Store(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT is not set")
+	}
+	store := storage.NewS3Store(endpoint, os.Getenv("S3_TEST_BUCKET"), os.Getenv("S3_TEST_REGION"),
+		os.Getenv("S3_TEST_ACCESS_KEY"), os.Getenv("S3_TEST_SECRET_KEY"))
+	testBlobStore(t, store)
+}